@@ -14,9 +14,11 @@ import (
 type Cacher interface {
 	Shutdown()
 	Get(ctx context.Context, key string) ([]byte, error)
+	GetDelete(ctx context.Context, key string) ([]byte, error)
 	Set(ctx context.Context, key string, val []byte) error
 	SetWithExpiry(ctx context.Context, key string, val []byte, expiry time.Duration) error
 	Delete(ctx context.Context, key string) error
+	IncrementWithExpiry(ctx context.Context, key string, expiry time.Duration) (int64, error)
 }
 
 type cache struct {
@@ -51,8 +53,13 @@ func (s *cache) Shutdown() {
 	s.Client.Close()
 }
 
+// Get returns the value of the key or nil if the key does not exist, ex: it
+// expired.
 func (s *cache) Get(ctx context.Context, key string) ([]byte, error) {
 	val, err := s.Client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
 	if err != nil {
 		s.Logger.Error("cache get failed", slog.Any("error", err))
 		return nil, err
@@ -60,6 +67,20 @@ func (s *cache) Get(ctx context.Context, key string) ([]byte, error) {
 	return []byte(val), nil
 }
 
+// GetDelete returns the value of the key like Get and deletes the key at the
+// same time, so only one caller ever gets the value.
+func (s *cache) GetDelete(ctx context.Context, key string) ([]byte, error) {
+	val, err := s.Client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		s.Logger.Error("cache get delete failed", slog.Any("error", err))
+		return nil, err
+	}
+	return []byte(val), nil
+}
+
 func (s *cache) Set(ctx context.Context, key string, val []byte) error {
 	err := s.Client.Set(ctx, key, val, 0).Err()
 	if err != nil {
@@ -86,3 +107,20 @@ func (s *cache) Delete(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+// IncrementWithExpiry adds one to the counter of the key and returns the new
+// count; the key expires after the expiry from its first increment.
+func (s *cache) IncrementWithExpiry(ctx context.Context, key string, expiry time.Duration) (int64, error) {
+	count, err := s.Client.Incr(ctx, key).Result()
+	if err != nil {
+		s.Logger.Error("cache increment failed", slog.Any("error", err))
+		return 0, err
+	}
+	if count == 1 {
+		if err := s.Client.Expire(ctx, key, expiry).Err(); err != nil {
+			s.Logger.Error("cache expire failed", slog.Any("error", err))
+			return 0, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,285 @@
+package pdfbuilder
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	c "github.com/LuchaComics/cps-backend/config"
+	"github.com/LuchaComics/cps-backend/config/constants"
+)
+
+type PDFBuilderResponseDTO struct {
+	FileName string `json:"file_name"`
+	FilePath string `json:"file_path"`
+	Content  []byte `json:"content"`
+}
+
+// CertificateRenderer interface for generating the certificate document of a
+// submission for one particular service type.
+type CertificateRenderer interface {
+	Render(m *s_d.ComicSubmission) (*PDFBuilderResponseDTO, error)
+}
+
+// CertificateRendererRegistry interface for looking up the renderer to use
+// for the service type of a submission.
+type CertificateRendererRegistry interface {
+	Register(serviceType int8, renderer CertificateRenderer)
+	IsSupported(serviceType int8) bool
+	Render(m *s_d.ComicSubmission) (*PDFBuilderResponseDTO, error)
+}
+
+type certificateRendererRegistry struct {
+	Logger    *slog.Logger
+	Renderers map[int8]CertificateRenderer
+}
+
+// NewCertificateRendererRegistry returns the registry with a renderer for
+// every service type we offer. To support a new service type, add its
+// template and layout file to the `PDFBuilder` config and register it here.
+func NewCertificateRendererRegistry(cfg *c.Conf, logger *slog.Logger) CertificateRendererRegistry {
+	pb := cfg.PDFBuilder
+	reg := &certificateRendererRegistry{
+		Logger:    logger,
+		Renderers: map[int8]CertificateRenderer{},
+	}
+	domainName := cfg.AppServer.DomainName
+	reg.Register(s_d.ServiceTypePreScreening, newLayoutRenderer("cbff", pb.CBFFTemplatePath, pb.CBFFLayoutPath, pb.DataDirectoryPath, domainName, pb.DebugOverlay, logger))
+	reg.Register(s_d.ServiceTypePedigree, newLayoutRenderer("pc", pb.PCTemplatePath, pb.PCLayoutPath, pb.DataDirectoryPath, domainName, pb.DebugOverlay, logger))
+	reg.Register(s_d.ServiceTypeCPSCapsule, newLayoutRenderer("cc", pb.CCTemplatePath, pb.CCLayoutPath, pb.DataDirectoryPath, domainName, pb.DebugOverlay, logger))
+	reg.Register(s_d.ServiceTypeCPSCapsuleIndieMintGem, newLayoutRenderer("ccimg", pb.CCIMGTemplatePath, pb.CCIMGLayoutPath, pb.DataDirectoryPath, domainName, pb.DebugOverlay, logger))
+	reg.Register(s_d.ServiceTypeCPSCapsuleSignatureCollection, newLayoutRenderer("ccsc", pb.CCSCTemplatePath, pb.CCSCLayoutPath, pb.DataDirectoryPath, domainName, pb.DebugOverlay, logger))
+	reg.Register(s_d.ServiceTypeCPSCapsuleYouGrade, newLayoutRenderer("ccug", pb.CCUGTemplatePath, pb.CCUGLayoutPath, pb.DataDirectoryPath, domainName, pb.DebugOverlay, logger))
+	return reg
+}
+
+func (reg *certificateRendererRegistry) Register(serviceType int8, renderer CertificateRenderer) {
+	reg.Renderers[serviceType] = renderer
+}
+
+func (reg *certificateRendererRegistry) IsSupported(serviceType int8) bool {
+	_, ok := reg.Renderers[serviceType]
+	return ok
+}
+
+// Render generates the certificate with the renderer registered for the
+// service type of the submission.
+func (reg *certificateRendererRegistry) Render(m *s_d.ComicSubmission) (*PDFBuilderResponseDTO, error) {
+	renderer, ok := reg.Renderers[m.ServiceType]
+	if !ok {
+		reg.Logger.Error("unsupported service type", slog.Any("service_type", m.ServiceType))
+		return nil, fmt.Errorf("unsupported service type: %v", m.ServiceType)
+	}
+	reg.Logger.Debug("beginning to generate pdf", slog.Any("service_type", m.ServiceType), slog.String("cpsrn", m.CPSRN))
+	res, err := renderer.Render(m)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, fmt.Errorf("no response from pdf renderer for service type: %v", m.ServiceType)
+	}
+	reg.Logger.Debug("finished generating pdf", slog.Any("service_type", m.ServiceType), slog.String("cpsrn", m.CPSRN))
+	return res, nil
+}
+
+// certificateRequest holds the submission values in the form our templates
+// print them, ex: the display name of the publisher.
+type certificateRequest struct {
+	CPSRN                              string
+	DomainName                         string
+	Filename                           string
+	SubmissionDate                     time.Time
+	Item                               string
+	SeriesTitle                        string
+	IssueVol                           string
+	IssueNo                            string
+	IssueCoverYear                     int64
+	IssueCoverMonth                    int8
+	PublisherName                      string
+	SpecialNotes                       string
+	GradingNotes                       string
+	CreasesFinding                     string
+	TearsFinding                       string
+	MissingPartsFinding                string
+	StainsFinding                      string
+	DistortionFinding                  string
+	PaperQualityFinding                string
+	SpineFinding                       string
+	CoverFinding                       string
+	ShowsSignsOfTamperingOrRestoration bool
+	GradingScale                       int8
+	OverallLetterGrade                 string
+	IsOverallLetterGradeNearMintPlus   bool
+	IsCpsIndieMintGem                  bool
+	OverallNumberGrade                 float64
+	CpsPercentageGrade                 float64
+	UserFirstName                      string
+	UserLastName                       string
+	UserOrganizationName               string
+	Signatures                         []*s_d.SubmissionSignature
+	PrimaryLabelDetails                int8
+	PrimaryLabelDetailsOther           string
+}
+
+func newCertificateRequest(m *s_d.ComicSubmission, domainName string) *certificateRequest {
+	// Look up the publisher names and get the correct display name or get the other.
+	var publisherNameDisplay string = constants.SubmissionPublisherNames[m.PublisherName]
+	if m.PublisherName == constants.SubmissionPublisherNameOther {
+		publisherNameDisplay = m.PublisherNameOther
+	}
+
+	// Signatures - Update `special notes` for the PDF.
+	var specialNotes = m.SpecialNotes
+	if len(m.Signatures) > 0 {
+		var str string
+		for _, s := range m.Signatures {
+			str += fmt.Sprintf("Signature of %v %v authenticated by CPS.", s.Role, s.Name)
+		}
+		specialNotes = fmt.Sprintf("%v %v", str, m.SpecialNotes)
+	}
+
+	return &certificateRequest{
+		CPSRN:                              m.CPSRN,
+		DomainName:                         domainName,
+		Filename:                           fmt.Sprintf("%v.pdf", m.ID.Hex()),
+		SubmissionDate:                     m.SubmissionDate,
+		Item:                               m.Item,
+		SeriesTitle:                        m.SeriesTitle,
+		IssueVol:                           m.IssueVol,
+		IssueNo:                            m.IssueNo,
+		IssueCoverYear:                     m.IssueCoverYear,
+		IssueCoverMonth:                    m.IssueCoverMonth,
+		PublisherName:                      publisherNameDisplay,
+		SpecialNotes:                       specialNotes,
+		GradingNotes:                       m.GradingNotes,
+		CreasesFinding:                     m.CreasesFinding,
+		TearsFinding:                       m.TearsFinding,
+		MissingPartsFinding:                m.MissingPartsFinding,
+		StainsFinding:                      m.StainsFinding,
+		DistortionFinding:                  m.DistortionFinding,
+		PaperQualityFinding:                m.PaperQualityFinding,
+		SpineFinding:                       m.SpineFinding,
+		CoverFinding:                       m.CoverFinding,
+		ShowsSignsOfTamperingOrRestoration: m.ShowsSignsOfTamperingOrRestoration == s_d.YesItShowsSignsOfTamperingOrRestoration,
+		GradingScale:                       m.GradingScale,
+		OverallLetterGrade:                 m.OverallLetterGrade,
+		IsOverallLetterGradeNearMintPlus:   m.IsOverallLetterGradeNearMintPlus,
+		IsCpsIndieMintGem:                  m.IsCpsIndieMintGem,
+		OverallNumberGrade:                 m.OverallNumberGrade,
+		CpsPercentageGrade:                 m.CpsPercentageGrade,
+		UserFirstName:                      m.UserFirstName,
+		UserLastName:                       m.UserLastName,
+		UserOrganizationName:               m.OrganizationName,
+		Signatures:                         m.Signatures,
+		PrimaryLabelDetails:                m.PrimaryLabelDetails,
+		PrimaryLabelDetailsOther:           m.PrimaryLabelDetailsOther,
+	}
+}
+
+// values returns the text for every key a layout field can bind to.
+func (r *certificateRequest) values() map[string]string {
+	issueCoverMonth := "-" // No cover month.
+	if r.IssueCoverMonth >= 1 && r.IssueCoverMonth <= 12 {
+		issueCoverMonth = time.Month(int(r.IssueCoverMonth)).String()
+	}
+
+	issueCoverYear := "-" // No cover year.
+	if r.IssueCoverYear == 2 {
+		issueCoverYear = "1899 or before"
+	} else if r.IssueCoverYear > 2 {
+		issueCoverYear = fmt.Sprintf("%v", r.IssueCoverYear)
+	}
+
+	issueCoverDate := "-"
+	if issueCoverMonth != "-" && r.IssueCoverYear == 2 {
+		issueCoverDate = issueCoverYear
+	} else if issueCoverMonth != "-" && r.IssueCoverYear > 2 {
+		issueCoverDate = fmt.Sprintf("%v %v", issueCoverMonth, issueCoverYear)
+	}
+
+	primaryLabelDetails := constants.SubmissionPrimaryLabelDetails[r.PrimaryLabelDetails]
+	if r.PrimaryLabelDetails == s_d.PrimaryLabelDetailsOther {
+		primaryLabelDetails = r.PrimaryLabelDetailsOther
+	}
+
+	var overallGrade string
+	switch r.GradingScale {
+	case s_d.GradingScaleLetter:
+		overallGrade = strings.ToUpper(r.OverallLetterGrade)
+	case s_d.GradingScaleNumber:
+		overallGrade = fmt.Sprintf("%v", r.OverallNumberGrade)
+	case s_d.GradingScaleCPSPercentage:
+		overallGrade = fmt.Sprintf("%v%%", r.CpsPercentageGrade)
+	}
+
+	// Used to center the percentage since it can be one to three digits.
+	cpsPercentageGradeDigits := "3"
+	if r.CpsPercentageGrade <= 9 {
+		cpsPercentageGradeDigits = "1"
+	} else if r.CpsPercentageGrade <= 99 {
+		cpsPercentageGradeDigits = "2"
+	}
+
+	// Anyone can scan or type the address printed on the certificate to look
+	// up the submission in our public registry.
+	var verificationURL, verificationShortURL string
+	if r.CPSRN != "" {
+		verificationShortURL = fmt.Sprintf("%v/cpsrn/%v", r.DomainName, r.CPSRN)
+		verificationURL = "https://" + verificationShortURL
+	}
+
+	v := map[string]string{
+		"cpsrn":                     r.CPSRN,
+		"verification_url":          verificationURL,
+		"verification_short_url":    verificationShortURL,
+		"submission_date_day":       fmt.Sprintf("%v", r.SubmissionDate.Day()),
+		"submission_date_month":     fmt.Sprintf("%v", int(r.SubmissionDate.Month())),
+		"submission_date_year":      fmt.Sprintf("%v", r.SubmissionDate.Year()),
+		"item":                      r.Item,
+		"series_title":              r.SeriesTitle,
+		"series_title_and_issue_no": fmt.Sprintf("%v %v", r.SeriesTitle, r.IssueNo),
+		"issue_vol":                 r.IssueVol,
+		"issue_no":                  r.IssueNo,
+		"issue_cover_month":         issueCoverMonth,
+		"issue_cover_year":          issueCoverYear,
+		"issue_cover_date":          issueCoverDate,
+		"publisher_name":            r.PublisherName,
+		"special_notes":             r.SpecialNotes,
+		"grading_notes":             r.GradingNotes,
+		"creases_finding":           r.CreasesFinding,
+		"tears_finding":             r.TearsFinding,
+		"missing_parts_finding":     r.MissingPartsFinding,
+		"stains_finding":            r.StainsFinding,
+		"distortion_finding":        r.DistortionFinding,
+		"paper_quality_finding":     r.PaperQualityFinding,
+		"spine_finding":             r.SpineFinding,
+		"cover_finding":             r.CoverFinding,
+		"shows_signs_of_tampering_or_restoration": fmt.Sprintf("%v", r.ShowsSignsOfTamperingOrRestoration),
+		"grading_scale":                          fmt.Sprintf("%v", r.GradingScale),
+		"overall_grade":                          overallGrade,
+		"overall_letter_grade":                   r.OverallLetterGrade,
+		"overall_letter_grade_description":       constants.SubmissionOverallLetterGrades[r.OverallLetterGrade],
+		"is_overall_letter_grade_near_mint_plus": fmt.Sprintf("%v", r.IsOverallLetterGradeNearMintPlus),
+		"is_cps_indie_mint_gem":                  fmt.Sprintf("%v", r.IsCpsIndieMintGem),
+		"overall_number_grade":                   fmt.Sprintf("%v", r.OverallNumberGrade),
+		"cps_percentage_grade":                   fmt.Sprintf("%v%%", r.CpsPercentageGrade),
+		"cps_percentage_grade_digits":            cpsPercentageGradeDigits,
+		"user_first_name":                        r.UserFirstName,
+		"user_last_name":                         r.UserLastName,
+		"user_organization_name":                 r.UserOrganizationName,
+		"primary_label_details":                  primaryLabelDetails,
+	}
+
+	// The templates have room for up to three authenticated signatures.
+	for i := 0; i < 3; i++ {
+		var line string
+		if i < len(r.Signatures) {
+			line = fmt.Sprintf("Signature of %v %v authenticated by CPS.", r.Signatures[i].Role, r.Signatures[i].Name)
+		}
+		v[fmt.Sprintf("signature_%v", i+1)] = line
+	}
+	return v
+}
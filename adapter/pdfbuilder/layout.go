@@ -0,0 +1,433 @@
+package pdfbuilder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/utils/qrcode"
+)
+
+// Layout represents the contents of a layout file which describes how the
+// values of a submission get drawn on top of a certificate template. Every
+// template in the `PDFBuilder` config ships with one so the artwork can be
+// changed without having to change any code.
+type Layout struct {
+	Orientation string         `json:"orientation"` // "P" for portrait or "L" for landscape.
+	PageSize    string         `json:"page_size"`   // Ex: "A4" or "A3".
+	Width       float64        `json:"width"`       // Width in millimetres to draw the template at.
+	Height      float64        `json:"height"`      // Height in millimetres to draw the template at.
+	Fields      []*LayoutField `json:"fields"`
+}
+
+// LayoutField binds a value of the submission to a position on the page.
+type LayoutField struct {
+	// Name of the field which is used in error messages and the debug overlay.
+	Name string `json:"name"`
+
+	// Source is the key of the submission value to draw, see the
+	// `certificateRequest.values` function for all the available keys.
+	Source string `json:"source,omitempty"`
+
+	// Text is drawn when there is no `Source`, ex: a label like "Volume:".
+	Text string `json:"text,omitempty"`
+
+	// Format is either "upper" or "lower" to change the case of the text.
+	Format string `json:"format,omitempty"`
+
+	// When restricts the field to only be drawn when each of the submission
+	// values is one of the listed values.
+	When map[string][]string `json:"when,omitempty"`
+
+	X     float64    `json:"x"`
+	Y     float64    `json:"y"`
+	Font  LayoutFont `json:"font"`
+	Color []int      `json:"color,omitempty"` // Red, green and blue; defaults to black.
+
+	// Rotate is the angle in degrees to rotate the text counter-clockwise
+	// around the x/y position. Rotated text is drawn on its baseline.
+	Rotate float64 `json:"rotate,omitempty"`
+
+	// Align is either "L", "C" or "R" within the `MaxWidth`, or within the
+	// right margin of the page if there is no `MaxWidth`.
+	Align string `json:"align,omitempty"`
+
+	// MaxWidth in millimetres; text which does not fit gets its font size
+	// reduced unless the field wraps.
+	MaxWidth float64 `json:"max_width,omitempty"`
+
+	// MaxLength is the number of characters after which we return an error.
+	MaxLength int `json:"max_length,omitempty"`
+
+	Wrap *LayoutWrap `json:"wrap,omitempty"`
+
+	// ChooseBy is the key of the submission value used to pick one of the
+	// `Choices`; defaults to the `Source`.
+	ChooseBy string `json:"choose_by,omitempty"`
+
+	// Choices are the positions (and optionally the text) to draw at for a
+	// particular value, ex: the checkbox for the "VF" finding. The matching
+	// is case insensitive and "*" is used when nothing else matches.
+	Choices map[string]*LayoutChoice `json:"choices,omitempty"`
+
+	// Required will return an error if there is nothing to draw.
+	Required bool `json:"required,omitempty"`
+
+	// QRCode draws the text as a QR Code instead, with the x/y position as
+	// the top left corner of the code.
+	QRCode *LayoutQRCode `json:"qr_code,omitempty"`
+}
+
+type LayoutFont struct {
+	Family string  `json:"family"` // Ex: "Helvetica" or "Courier".
+	Style  string  `json:"style"`  // Ex: "" for regular, "B" for bold.
+	Size   float64 `json:"size"`   // Points.
+}
+
+// LayoutWrap describes how to break the text into multiple lines.
+type LayoutWrap struct {
+	MaxChars   int     `json:"max_chars,omitempty"` // Break after this many characters, else use `MaxWidth` of the field.
+	MaxLines   int     `json:"max_lines"`           // Lines past this are not drawn.
+	LineHeight float64 `json:"line_height"`         // Millimetres between each line.
+}
+
+// LayoutQRCode describes how to draw a QR Code. A white quiet zone of four
+// modules is drawn around the code so it scans on top of any artwork.
+type LayoutQRCode struct {
+	Size  float64 `json:"size"`            // Width and height in millimetres, excluding the quiet zone.
+	Level string  `json:"level,omitempty"` // Error correction of "L", "M", "Q" or "H"; defaults to "M".
+}
+
+// qrCodeLevels maps the error correction levels a layout can use.
+var qrCodeLevels = map[string]qrcode.Level{
+	"":  qrcode.Medium,
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.Quartile,
+	"H": qrcode.High,
+}
+
+// qrCodeQuietZone is the number of light modules around a QR Code which the
+// specification requires for scanners to find it.
+const qrCodeQuietZone = 4
+
+type LayoutChoice struct {
+	X    float64 `json:"x"`
+	Y    float64 `json:"y"`
+	Text string  `json:"text,omitempty"` // Replaces the text of the field if set.
+}
+
+// LoadLayout reads and validates the layout file.
+func LoadLayout(filePath string) (*Layout, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unknown keys are almost always a typo so we want to know about them.
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.DisallowUnknownFields()
+
+	var l Layout
+	if err := dec.Decode(&l); err != nil {
+		return nil, fmt.Errorf("layout %v: %v", filePath, err)
+	}
+	if err := l.Validate(); err != nil {
+		return nil, fmt.Errorf("layout %v: %v", filePath, err)
+	}
+	return &l, nil
+}
+
+// Validate returns an error if the layout cannot be drawn.
+func (l *Layout) Validate() error {
+	if l.Orientation != "P" && l.Orientation != "L" {
+		return errors.New("orientation must be `P` or `L`")
+	}
+	if l.PageSize == "" {
+		return errors.New("missing page size")
+	}
+	if l.Width <= 0 || l.Height <= 0 {
+		return errors.New("width and height must be greater then zero")
+	}
+
+	// Every key we reference must exist or the field would always be empty.
+	values := newCertificateRequest(&s_d.ComicSubmission{}, "").values()
+	isKnown := func(key string) bool {
+		_, ok := values[key]
+		return ok
+	}
+
+	for i, f := range l.Fields {
+		if f.Name == "" {
+			return fmt.Errorf("field #%v is missing a name", i)
+		}
+		if f.Source == "" && f.Text == "" && len(f.Choices) == 0 {
+			return fmt.Errorf("field %v is missing a source or text", f.Name)
+		}
+		if f.Source != "" && !isKnown(f.Source) {
+			return fmt.Errorf("field %v has unknown source %v", f.Name, f.Source)
+		}
+		if f.ChooseBy != "" && !isKnown(f.ChooseBy) {
+			return fmt.Errorf("field %v has unknown choose by %v", f.Name, f.ChooseBy)
+		}
+		for key := range f.When {
+			if !isKnown(key) {
+				return fmt.Errorf("field %v has unknown when %v", f.Name, key)
+			}
+		}
+		if f.Format != "" && f.Format != "upper" && f.Format != "lower" {
+			return fmt.Errorf("field %v has unsupported format %v", f.Name, f.Format)
+		}
+		if f.Align != "" && f.Align != "L" && f.Align != "C" && f.Align != "R" {
+			return fmt.Errorf("field %v has unsupported align %v", f.Name, f.Align)
+		}
+		if f.QRCode == nil && (f.Font.Family == "" || f.Font.Size <= 0) {
+			return fmt.Errorf("field %v is missing a font family or size", f.Name)
+		}
+		if len(f.Color) != 0 && len(f.Color) != 3 {
+			return fmt.Errorf("field %v color must be red, green and blue", f.Name)
+		}
+		if f.Wrap != nil {
+			if f.Rotate != 0 {
+				return fmt.Errorf("field %v cannot both wrap and rotate", f.Name)
+			}
+			if f.Wrap.MaxLines <= 0 || f.Wrap.LineHeight <= 0 {
+				return fmt.Errorf("field %v wrap is missing max lines or line height", f.Name)
+			}
+			if f.Wrap.MaxChars <= 0 && f.MaxWidth <= 0 {
+				return fmt.Errorf("field %v wrap is missing max chars or max width", f.Name)
+			}
+		}
+		if f.QRCode != nil {
+			if f.Wrap != nil || f.Rotate != 0 {
+				return fmt.Errorf("field %v cannot wrap or rotate a qr code", f.Name)
+			}
+			if f.QRCode.Size <= 0 {
+				return fmt.Errorf("field %v qr code size must be greater then zero", f.Name)
+			}
+			if _, ok := qrCodeLevels[f.QRCode.Level]; !ok {
+				return fmt.Errorf("field %v has unsupported qr code level %v", f.Name, f.QRCode.Level)
+			}
+		}
+	}
+	return nil
+}
+
+// Draw draws every field of the layout with the values onto the current page.
+// If `debug` is true then a bounding box is drawn around every field so the
+// positions can be calibrated against the template artwork.
+func (l *Layout) Draw(pdf *gofpdf.Fpdf, values map[string]string, debug bool) error {
+	for _, f := range l.Fields {
+		if err := f.draw(pdf, values, debug); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *LayoutField) draw(pdf *gofpdf.Fpdf, values map[string]string, debug bool) error {
+	for key, allowed := range f.When {
+		if !containsString(allowed, values[key]) {
+			return nil
+		}
+	}
+
+	text := f.Text
+	if f.Source != "" {
+		text = values[f.Source]
+	}
+	if f.MaxLength > 0 && len(text) > f.MaxLength {
+		return fmt.Errorf("%v length over %v", f.Name, f.MaxLength)
+	}
+
+	x, y := f.X, f.Y
+	if len(f.Choices) > 0 {
+		chooseBy := f.ChooseBy
+		if chooseBy == "" {
+			chooseBy = f.Source
+		}
+		value := strings.ToLower(values[chooseBy])
+		c, ok := f.Choices[value]
+		if !ok {
+			c, ok = f.Choices["*"]
+		}
+		if debug {
+			f.drawDebugChoices(pdf)
+		}
+		if !ok {
+			if f.Required {
+				return fmt.Errorf("missing value for %v with %v", f.Name, values[chooseBy])
+			}
+			return nil
+		}
+		x, y = c.X, c.Y
+		if c.Text != "" {
+			text = c.Text
+		}
+	}
+
+	switch f.Format {
+	case "upper":
+		text = strings.ToUpper(text)
+	case "lower":
+		text = strings.ToLower(text)
+	}
+	if text == "" {
+		if f.Required {
+			return fmt.Errorf("missing value for %v", f.Name)
+		}
+		return nil
+	}
+
+	if f.QRCode != nil {
+		return f.drawQRCode(pdf, x, y, text, debug)
+	}
+
+	pdf.SetFont(f.Font.Family, f.Font.Style, f.Font.Size)
+	if len(f.Color) == 3 {
+		pdf.SetTextColor(f.Color[0], f.Color[1], f.Color[2])
+		defer pdf.SetTextColor(0, 0, 0)
+	}
+
+	if f.Rotate != 0 {
+		pdf.TransformBegin()
+		pdf.TransformRotate(f.Rotate, x, y)
+		pdf.Text(x, y, text)
+		if debug {
+			_, fontHeight := pdf.GetFontSize()
+			f.drawDebugBox(pdf, x, y-fontHeight*0.75, pdf.GetStringWidth(text), fontHeight)
+		}
+		pdf.TransformEnd()
+		return nil
+	}
+
+	lines := []string{text}
+	var lineHeight float64
+	if f.Wrap != nil {
+		lineHeight = f.Wrap.LineHeight
+		if f.Wrap.MaxChars > 0 {
+			lines = splitText(text, f.Wrap.MaxChars)
+		} else {
+			lines = pdf.SplitText(text, f.MaxWidth)
+		}
+		if len(lines) > f.Wrap.MaxLines {
+			lines = lines[:f.Wrap.MaxLines]
+		}
+	} else if f.MaxWidth > 0 {
+		// Shrink the text until it fits.
+		size := f.Font.Size
+		for size > 1 && pdf.GetStringWidth(text) > f.MaxWidth {
+			size -= 0.5
+			pdf.SetFontSize(size)
+		}
+	}
+
+	for i, line := range lines {
+		pdf.SetXY(x, y+float64(i)*lineHeight)
+		pdf.CellFormat(f.MaxWidth, 0, line, "", 0, f.Align, false, 0, "")
+	}
+
+	if debug {
+		_, fontHeight := pdf.GetFontSize()
+		width := f.MaxWidth
+		if width == 0 && f.Align != "" {
+			pageWidth, _ := pdf.GetPageSize()
+			_, _, rightMargin, _ := pdf.GetMargins()
+			width = pageWidth - rightMargin - x
+		}
+		if width == 0 {
+			for _, line := range lines {
+				if w := pdf.GetStringWidth(line); w > width {
+					width = w
+				}
+			}
+		}
+		height := fontHeight + float64(len(lines)-1)*lineHeight
+		f.drawDebugBox(pdf, x, y-fontHeight/2, width, height)
+	}
+	return nil
+}
+
+// drawQRCode draws the text as a QR Code in the color of the field.
+func (f *LayoutField) drawQRCode(pdf *gofpdf.Fpdf, x, y float64, text string, debug bool) error {
+	if err := drawQRCode(pdf, x, y, f.QRCode.Size, text, qrCodeLevels[f.QRCode.Level], f.Color); err != nil {
+		return fmt.Errorf("%v qr code: %v", f.Name, err)
+	}
+	if debug {
+		f.drawDebugBox(pdf, x, y, f.QRCode.Size, f.QRCode.Size)
+	}
+	return nil
+}
+
+// drawQRCode draws the text as a QR Code of the size, surrounded by a white
+// quiet zone, with its top left corner at x and y.
+func drawQRCode(pdf *gofpdf.Fpdf, x, y, size float64, text string, level qrcode.Level, color []int) error {
+	q, err := qrcode.Encode(text, level)
+	if err != nil {
+		return err
+	}
+	module := size / float64(q.Size)
+	quietZone := module * qrCodeQuietZone
+
+	pdf.SetFillColor(255, 255, 255)
+	pdf.Rect(x-quietZone, y-quietZone, size+quietZone*2, size+quietZone*2, "F")
+
+	if len(color) == 3 {
+		pdf.SetFillColor(color[0], color[1], color[2])
+	} else {
+		pdf.SetFillColor(0, 0, 0)
+	}
+	for row := 0; row < q.Size; row++ {
+		// Draw every run of dark modules as one rectangle so the viewer does
+		// not render hairline gaps between the modules.
+		for col := 0; col < q.Size; col++ {
+			if !q.Black(col, row) {
+				continue
+			}
+			start := col
+			for col+1 < q.Size && q.Black(col+1, row) {
+				col++
+			}
+			pdf.Rect(x+float64(start)*module, y+float64(row)*module, float64(col-start+1)*module, module, "F")
+		}
+	}
+	pdf.SetFillColor(255, 255, 255)
+	return nil
+}
+
+// drawDebugBox draws the bounding box and the name of the field.
+func (f *LayoutField) drawDebugBox(pdf *gofpdf.Fpdf, x, y, w, h float64) {
+	pdf.SetDrawColor(255, 0, 0)
+	pdf.SetLineWidth(0.2)
+	pdf.Rect(x, y, w, h, "D")
+
+	pdf.SetFont("Helvetica", "", 4)
+	pdf.SetTextColor(255, 0, 0)
+	pdf.Text(x, y-0.5, f.Name)
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// drawDebugChoices draws a small marker at every choice so the positions of
+// the options which were not picked can be calibrated as well.
+func (f *LayoutField) drawDebugChoices(pdf *gofpdf.Fpdf) {
+	pdf.SetDrawColor(0, 0, 255)
+	pdf.SetLineWidth(0.1)
+	for _, c := range f.Choices {
+		pdf.Rect(c.X, c.Y-1, 2, 2, "D")
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
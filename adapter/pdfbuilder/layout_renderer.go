@@ -0,0 +1,109 @@
+package pdfbuilder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/jung-kurt/gofpdf/contrib/gofpdi"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+)
+
+// layoutRenderer generates a certificate by drawing the layout on top of the
+// first page of the template.
+type layoutRenderer struct {
+	Name                string
+	PDFTemplateFilePath string
+	Layout              *Layout
+	DataDirectoryPath   string
+	DomainName          string // Used in the verification url printed on the certificate.
+	DebugOverlay        bool
+	Logger              *slog.Logger
+}
+
+func newLayoutRenderer(name string, templateFilePath string, layoutFilePath string, dataDirectoryPath string, domainName string, debugOverlay bool, logger *slog.Logger) CertificateRenderer {
+	// Defensive code: Make sure we have access to the file before proceeding any further with the code.
+	logger.Debug("pdf renderer initializing...", slog.String("name", name))
+	_, err := os.Stat(templateFilePath)
+	if os.IsNotExist(err) {
+		log.Fatal(errors.New("file does not exist"))
+	}
+
+	// Likewise a broken layout file must stop the app on startup and not when
+	// we are generating a certificate for a customer.
+	layout, err := LoadLayout(layoutFilePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return &layoutRenderer{
+		Name:                name,
+		PDFTemplateFilePath: templateFilePath,
+		Layout:              layout,
+		DataDirectoryPath:   dataDirectoryPath,
+		DomainName:          domainName,
+		DebugOverlay:        debugOverlay,
+		Logger:              logger,
+	}
+}
+
+func (bdr *layoutRenderer) Render(m *s_d.ComicSubmission) (*PDFBuilderResponseDTO, error) {
+	r := newCertificateRequest(m, bdr.DomainName)
+
+	var err error
+
+	// Open our PDF template and create clone it for the PDF certificate we will be building with.
+	pdf := gofpdf.New(bdr.Layout.Orientation, "mm", bdr.Layout.PageSize, "")
+	tpl1 := gofpdi.ImportPage(pdf, bdr.PDFTemplateFilePath, 1, "/MediaBox")
+
+	pdf.AddPage()
+
+	// Draw imported template onto page
+	gofpdi.UseImportedTemplate(pdf, tpl1, 0, 0, bdr.Layout.Width, bdr.Layout.Height)
+
+	if err := bdr.Layout.Draw(pdf, r.values(), bdr.DebugOverlay); err != nil {
+		bdr.Logger.Error("draw layout error", slog.String("name", bdr.Name), slog.Any("error", err))
+		return nil, err
+	}
+
+	////
+	//// Generate the file and save it to the file.
+	////
+
+	fileName := fmt.Sprintf("%s.pdf", r.CPSRN)
+	filePath := fmt.Sprintf("%s/%s", bdr.DataDirectoryPath, fileName)
+
+	err = pdf.OutputFileAndClose(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	////
+	//// Open the file and read all the binary data.
+	////
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	bin, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	////
+	//// Return the generate certificate.
+	////
+
+	return &PDFBuilderResponseDTO{
+		FileName: fileName,
+		FilePath: filePath,
+		Content:  bin,
+	}, err
+}
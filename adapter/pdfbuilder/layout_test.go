@@ -0,0 +1,143 @@
+package pdfbuilder
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+)
+
+func sampleSubmission() *s_d.ComicSubmission {
+	return &s_d.ComicSubmission{
+		CPSRN:                              "788346-26649-1-1001-2",
+		SeriesTitle:                        "Winter World",
+		IssueVol:                           "Vol 1",
+		IssueNo:                            "#1",
+		IssueCoverYear:                     1999,
+		IssueCoverMonth:                    3,
+		SpecialNotes:                       "Some special notes",
+		GradingNotes:                       "Some grading notes",
+		CreasesFinding:                     "VF",
+		TearsFinding:                       "FN",
+		MissingPartsFinding:                "PR",
+		StainsFinding:                      "NM",
+		DistortionFinding:                  "NM",
+		PaperQualityFinding:                "VF",
+		SpineFinding:                       "FN",
+		CoverFinding:                       "VG",
+		ShowsSignsOfTamperingOrRestoration: s_d.YesItShowsSignsOfTamperingOrRestoration,
+		GradingScale:                       s_d.GradingScaleLetter,
+		OverallLetterGrade:                 "nm",
+		IsOverallLetterGradeNearMintPlus:   true,
+		PrimaryLabelDetails:                s_d.PrimaryLabelDetailsRegularEdition,
+	}
+}
+
+func TestStaticLayouts(t *testing.T) {
+	files, err := filepath.Glob("../../static/*.layout.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 6 {
+		t.Fatalf("expected 6 layout files but got %v", len(files))
+	}
+
+	values := newCertificateRequest(sampleSubmission(), "cpsapp.ca").values()
+	for _, file := range files {
+		l, err := LoadLayout(file)
+		if err != nil {
+			t.Errorf("received an error %v", err)
+			continue
+		}
+		// Every certificate must link back to our public registry.
+		var hasQRCode bool
+		for _, f := range l.Fields {
+			if f.QRCode != nil && f.Source == "verification_url" {
+				hasQRCode = true
+			}
+		}
+		if !hasQRCode {
+			t.Errorf("%v: missing the verification qr code", file)
+		}
+
+		for _, debug := range []bool{false, true} {
+			pdf := gofpdf.New(l.Orientation, "mm", l.PageSize, "")
+			pdf.AddPage()
+			if err := l.Draw(pdf, values, debug); err != nil {
+				t.Errorf("%v: received an error %v", file, err)
+			}
+		}
+	}
+}
+
+func TestLayoutRequiredChoice(t *testing.T) {
+	l, err := LoadLayout("../../static/CBFF.layout.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := sampleSubmission()
+	m.CreasesFinding = ""
+	pdf := gofpdf.New(l.Orientation, "mm", l.PageSize, "")
+	pdf.AddPage()
+	err = l.Draw(pdf, newCertificateRequest(m, "cpsapp.ca").values(), false)
+	if err == nil || !strings.Contains(err.Error(), "crease finding") {
+		t.Errorf("expected missing crease finding error but got %v", err)
+	}
+}
+
+func TestLayoutValidate(t *testing.T) {
+	l := &Layout{
+		Orientation: "P",
+		PageSize:    "A4",
+		Width:       210,
+		Height:      300,
+		Fields: []*LayoutField{
+			{Name: "typo", Source: "serie_title", Font: LayoutFont{Family: "Helvetica", Size: 12}},
+		},
+	}
+	if err := l.Validate(); err == nil {
+		t.Error("expected unknown source error")
+	}
+}
+
+func TestVerificationURL(t *testing.T) {
+	values := newCertificateRequest(sampleSubmission(), "cpsapp.ca").values()
+
+	expected := "https://cpsapp.ca/cpsrn/788346-26649-1-1001-2"
+	if actual := values["verification_url"]; actual != expected {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+	expected = "cpsapp.ca/cpsrn/788346-26649-1-1001-2"
+	if actual := values["verification_short_url"]; actual != expected {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestLayoutQRCode(t *testing.T) {
+	l := &Layout{
+		Orientation: "P",
+		PageSize:    "A4",
+		Width:       210,
+		Height:      300,
+		Fields: []*LayoutField{
+			{Name: "qr", Source: "verification_url", X: 10, Y: 10, QRCode: &LayoutQRCode{Size: 20, Level: "Z"}},
+		},
+	}
+	if err := l.Validate(); err == nil {
+		t.Error("expected unsupported qr code level error")
+	}
+
+	l.Fields[0].QRCode.Level = "H"
+	if err := l.Validate(); err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	pdf := gofpdf.New(l.Orientation, "mm", l.PageSize, "")
+	pdf.AddPage()
+	if err := l.Draw(pdf, newCertificateRequest(sampleSubmission(), "cpsapp.ca").values(), true); err != nil {
+		t.Errorf("received an error %v", err)
+	}
+}
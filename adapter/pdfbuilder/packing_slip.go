@@ -0,0 +1,217 @@
+package pdfbuilder
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+	c "github.com/LuchaComics/cps-backend/config"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/qrcode"
+)
+
+// PackingSlipRenderer interface for generating the packing slip which is
+// shipped back with an order, listing the certificate of every submission.
+type PackingSlipRenderer interface {
+	Render(o *o_d.Order, submissions []*s_d.ComicSubmission) (*PDFBuilderResponseDTO, error)
+}
+
+type packingSlipRenderer struct {
+	DataDirectoryPath string
+	DomainName        string
+	Logger            *slog.Logger
+}
+
+func NewPackingSlipRenderer(cfg *c.Conf, logger *slog.Logger) PackingSlipRenderer {
+	return &packingSlipRenderer{
+		DataDirectoryPath: cfg.PDFBuilder.DataDirectoryPath,
+		DomainName:        cfg.AppServer.DomainName,
+		Logger:            logger,
+	}
+}
+
+// packingSlipColumn is one column of the table of submissions.
+type packingSlipColumn struct {
+	Title string
+	Width float64
+	Align string
+}
+
+const (
+	packingSlipMargin    = 12.0
+	packingSlipRowHeight = 16.0
+	packingSlipQRSize    = 12.0
+)
+
+var packingSlipColumns = []packingSlipColumn{
+	{"#", 8, "L"},
+	{"CPSRN", 34, "L"},
+	{"Item", 52, "L"},
+	{"Service", 28, "L"},
+	{"Grade", 14, "C"},
+	{"Status", 18, "L"},
+	{"Declared", 20, "R"},
+	{"Verify", 17.9, "C"},
+}
+
+func (bdr *packingSlipRenderer) Render(o *o_d.Order, submissions []*s_d.ComicSubmission) (*PDFBuilderResponseDTO, error) {
+	// The lines are printed in the order they were received with.
+	byID := make(map[string]*s_d.ComicSubmission, len(submissions))
+	for _, m := range submissions {
+		byID[m.ID.Hex()] = m
+	}
+
+	pdf := gofpdf.New("P", "mm", "Letter", "")
+	pdf.SetMargins(packingSlipMargin, packingSlipMargin, packingSlipMargin)
+	pdf.SetAutoPageBreak(false, packingSlipMargin)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-packingSlipMargin)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Order %v - Page %v of {nb}", o.ID.Hex(), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	bdr.drawHeader(pdf, o)
+	bdr.drawTableHeader(pdf)
+
+	_, pageHeight := pdf.GetPageSize()
+	for i, line := range o.Lines {
+		if pdf.GetY()+packingSlipRowHeight > pageHeight-packingSlipMargin*2 {
+			pdf.AddPage()
+			bdr.drawTableHeader(pdf)
+		}
+		if err := bdr.drawLine(pdf, i, line, byID[line.SubmissionID.Hex()]); err != nil {
+			bdr.Logger.Error("draw packing slip line error", slog.Any("order_id", o.ID), slog.Any("error", err))
+			return nil, err
+		}
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, fmt.Sprintf("%v submissions - Declared value total: $%.2f", len(o.Lines), o.DeclaredValueTotal), "", 1, "R", false, 0, "")
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	////
+	//// Generate the file and save it to the file.
+	////
+
+	fileName := fmt.Sprintf("%s-packing-slip.pdf", o.ID.Hex())
+	filePath := fmt.Sprintf("%s/%s", bdr.DataDirectoryPath, fileName)
+	if err := pdf.OutputFileAndClose(filePath); err != nil {
+		return nil, err
+	}
+	bin, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return &PDFBuilderResponseDTO{
+		FileName: fileName,
+		FilePath: filePath,
+		Content:  bin,
+	}, nil
+}
+
+// drawHeader draws the title, the order details and the shipping address.
+func (bdr *packingSlipRenderer) drawHeader(pdf *gofpdf.Fpdf, o *o_d.Order) {
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "CPS Packing Slip", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 5, fmt.Sprintf("Order: %v", o.ID.Hex()), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, fmt.Sprintf("Received: %v", o.CreatedAt.Format("2006-01-02")), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, fmt.Sprintf("Printed: %v", time.Now().Format("2006-01-02")), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 5, fmt.Sprintf("From: %v", o.OrganizationName), "", 1, "L", false, 0, "")
+
+	if s := o.Shipping; s != nil {
+		pdf.Ln(2)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(0, 5, "Ship to", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		address := []string{
+			s.Name,
+			s.AddressLine1,
+			s.AddressLine2,
+			strings.TrimSpace(fmt.Sprintf("%v %v %v", s.City, s.Region, s.PostalCode)),
+			s.Country,
+			s.Phone,
+		}
+		for _, text := range address {
+			if text != "" {
+				pdf.CellFormat(0, 4.5, text, "", 1, "L", false, 0, "")
+			}
+		}
+		if s.Carrier != "" || s.TrackingNumber != "" {
+			pdf.CellFormat(0, 4.5, strings.TrimSpace(fmt.Sprintf("Via: %v %v", s.Carrier, s.TrackingNumber)), "", 1, "L", false, 0, "")
+		}
+	}
+	pdf.Ln(4)
+}
+
+func (bdr *packingSlipRenderer) drawTableHeader(pdf *gofpdf.Fpdf) {
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetFillColor(230, 230, 230)
+	for _, col := range packingSlipColumns {
+		pdf.CellFormat(col.Width, 6, col.Title, "1", 0, col.Align, true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFillColor(255, 255, 255)
+}
+
+// drawLine draws one submission of the order with the QR Code of its
+// certificate so every slab can be checked against the registry on arrival.
+func (bdr *packingSlipRenderer) drawLine(pdf *gofpdf.Fpdf, i int, line *o_d.OrderLine, m *s_d.ComicSubmission) error {
+	grade, status := "-", constants.SubmissionStatuses[line.Status]
+	if m != nil {
+		grade = newCertificateRequest(m, bdr.DomainName).values()["overall_grade"]
+		status = constants.SubmissionStatuses[m.Status]
+	}
+	values := []string{
+		fmt.Sprintf("%v", i+1),
+		line.CPSRN,
+		line.Item,
+		constants.SubmissionServiceTypes[line.ServiceType],
+		grade,
+		status,
+		fmt.Sprintf("$%.2f", line.DeclaredValue),
+		"",
+	}
+
+	pdf.SetFont("Helvetica", "", 7)
+	x, y := pdf.GetXY()
+	for j, col := range packingSlipColumns {
+		text := values[j]
+		for text != "" && pdf.GetStringWidth(text) > col.Width-2 {
+			runes := []rune(text)
+			text = string(runes[:len(runes)-1]) // Truncate to fit in the cell.
+		}
+		pdf.CellFormat(col.Width, packingSlipRowHeight, text, "1", 0, col.Align, false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	if line.CPSRN == "" {
+		return nil
+	}
+	url := fmt.Sprintf("https://%v/cpsrn/%v", bdr.DomainName, line.CPSRN)
+	last := packingSlipColumns[len(packingSlipColumns)-1]
+	qrX := x + packingSlipTableWidth() - last.Width + (last.Width-packingSlipQRSize)/2
+	qrY := y + (packingSlipRowHeight-packingSlipQRSize)/2
+	return drawQRCode(pdf, qrX, qrY, packingSlipQRSize, url, qrcode.Low, nil)
+}
+
+func packingSlipTableWidth() float64 {
+	var w float64
+	for _, col := range packingSlipColumns {
+		w += col.Width
+	}
+	return w
+}
@@ -0,0 +1,50 @@
+package pdfbuilder
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+)
+
+func TestPackingSlipRender(t *testing.T) {
+	o := &o_d.Order{
+		ID:               primitive.NewObjectID(),
+		OrganizationName: "Lucha Comics",
+		Shipping:         &o_d.ShippingDetails{Name: "Bart", AddressLine1: "1 Main St", City: "London", Country: "Canada"},
+	}
+
+	// Enough lines to need more than one page.
+	var submissions []*s_d.ComicSubmission
+	for i := 0; i < 30; i++ {
+		m := sampleSubmission()
+		m.ID = primitive.NewObjectID()
+		m.CPSRN = fmt.Sprintf("788346-26649-1-%v-2", 1000+i)
+		m.Status = s_d.StatusGraded
+		submissions = append(submissions, m)
+		o.Lines = append(o.Lines, &o_d.OrderLine{SubmissionID: m.ID, CPSRN: m.CPSRN, Item: m.Item, ServiceType: m.ServiceType, DeclaredValue: 10})
+		o.DeclaredValueTotal += 10
+	}
+
+	bdr := &packingSlipRenderer{
+		DataDirectoryPath: t.TempDir(),
+		DomainName:        "cpsapp.ca",
+		Logger:            slog.New(slog.NewTextHandler(os.Stderr)),
+	}
+	res, err := bdr.Render(o, submissions)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !bytes.HasPrefix(res.Content, []byte("%PDF")) {
+		t.Error("expected a pdf document")
+	}
+	if !bytes.Contains(res.Content, []byte("/Count 3")) {
+		t.Error("expected the packing slip to have three pages")
+	}
+}
@@ -29,10 +29,3 @@ func splitText(text string, maxChars int) []string {
 
 	return lines
 }
-
-func getElementAtIndex(slice []string, index int) (string, bool) {
-	if index < 0 || index >= len(slice) {
-		return "", false
-	}
-	return slice[index], true
-}
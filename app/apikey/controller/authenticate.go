@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// lastUsedAtPrecision limits how often using a key writes to our database.
+const lastUsedAtPrecision = time.Minute
+
+// Authenticate returns the active key matching the `Authorization: ApiKey`
+// header of a request or a `401 Unauthorized` error.
+func (c *APIKeyControllerImpl) Authenticate(ctx context.Context, key string) (*apikey_s.APIKey, error) {
+	invalid := httperror.NewForSingleField(http.StatusUnauthorized, "message", "invalid api key")
+
+	prefix, secret, ok := parseKey(key)
+	if !ok {
+		return nil, invalid
+	}
+
+	m, err := c.APIKeyStorer.GetByPrefix(ctx, prefix)
+	if err != nil {
+		c.Logger.Error("database get by prefix error", slog.Any("error", err))
+		return nil, err
+	}
+	if m == nil || m.Status != apikey_s.APIKeyStatusActive {
+		return nil, invalid
+	}
+
+	match, err := c.Password.ComparePasswordAndHash(secret, m.HashedSecret)
+	if err != nil {
+		c.Logger.Error("password comparison error", slog.Any("error", err))
+		return nil, err
+	}
+	if !match {
+		c.Logger.Warn("api key secret mismatch", slog.Any("id", m.ID))
+		return nil, invalid
+	}
+
+	now := time.Now()
+	if m.IsExpired(now) {
+		return nil, httperror.NewForSingleField(http.StatusUnauthorized, "message", "api key expired")
+	}
+
+	// The key is recorded as the user who created it so it stops working
+	// once that user is archived or deleted.
+	creator, err := c.UserStorer.GetByID(ctx, m.CreatedByUserID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if creator == nil || creator.Status != user_s.UserStatusActive {
+		c.Logger.Warn("api key creator is not active", slog.Any("id", m.ID))
+		return nil, invalid
+	}
+
+	if now.Sub(m.LastUsedAt) > lastUsedAtPrecision {
+		if err := c.APIKeyStorer.UpdateLastUsedAtByID(ctx, m.ID, now); err != nil {
+			c.Logger.Warn("database update last used at error", slog.Any("error", err))
+		}
+		m.LastUsedAt = now
+	}
+	return m, nil
+}
@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
+	organization_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+	"github.com/LuchaComics/cps-backend/provider/password"
+)
+
+// APIKeyController Interface for the api keys of our organizations.
+type APIKeyController interface {
+	Create(ctx context.Context, req *APIKeyCreateRequestIDO) (*APIKeyCreateResponseIDO, error)
+	ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*apikey_s.APIKey, error)
+	RevokeByID(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) error
+	Authenticate(ctx context.Context, key string) (*apikey_s.APIKey, error)
+}
+
+type APIKeyControllerImpl struct {
+	Config             *config.Conf
+	Logger             *slog.Logger
+	Password           password.Provider
+	APIKeyStorer       apikey_s.APIKeyStorer
+	OrganizationStorer organization_s.OrganizationStorer
+	UserStorer         user_s.UserStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	passwordp password.Provider,
+	apikey_storer apikey_s.APIKeyStorer,
+	org_storer organization_s.OrganizationStorer,
+	usr_storer user_s.UserStorer,
+) APIKeyController {
+	s := &APIKeyControllerImpl{
+		Config:             appCfg,
+		Logger:             loggerp,
+		Password:           passwordp,
+		APIKeyStorer:       apikey_storer,
+		OrganizationStorer: org_storer,
+		UserStorer:         usr_storer,
+	}
+	s.Logger.Debug("api key controller initialized")
+	return s
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+type APIKeyCreateRequestIDO struct {
+	OrganizationID primitive.ObjectID `json:"organization_id"`
+	Name           string             `json:"name"`
+	Scopes         []string           `json:"scopes"`
+	ExpiresAt      time.Time          `json:"expires_at,omitempty"`
+}
+
+// APIKeyCreateResponseIDO holds the key which is never shown again.
+type APIKeyCreateResponseIDO struct {
+	APIKey *apikey_s.APIKey `json:"api_key"`
+	Key    string           `json:"key"`
+}
+
+func (c *APIKeyControllerImpl) Create(ctx context.Context, req *APIKeyCreateRequestIDO) (*APIKeyCreateResponseIDO, error) {
+	if err := checkManage(ctx, req.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	// Extract from our session the following data.
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+
+	org, err := c.OrganizationStorer.GetByID(ctx, req.OrganizationID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if org == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("organization_id", "organization does not exist")
+	}
+
+	key, prefix, secret, err := newKey()
+	if err != nil {
+		c.Logger.Error("generate api key error", slog.Any("error", err))
+		return nil, err
+	}
+	hashedSecret, err := c.Password.GenerateHashFromPassword(secret)
+	if err != nil {
+		c.Logger.Error("hashing error", slog.Any("error", err))
+		return nil, err
+	}
+
+	m := &apikey_s.APIKey{
+		ID:               primitive.NewObjectID(),
+		OrganizationID:   org.ID,
+		OrganizationName: org.Name,
+		Name:             req.Name,
+		Prefix:           prefix,
+		HashedSecret:     hashedSecret,
+		HashedSecretAlgo: c.Password.AlgorithmName(),
+		Scopes:           req.Scopes,
+		Status:           apikey_s.APIKeyStatusActive,
+		ExpiresAt:        req.ExpiresAt,
+		CreatedAt:        time.Now(),
+		CreatedByUserID:  userID,
+		CreatedByName:    userName,
+	}
+	if err := c.APIKeyStorer.Create(ctx, m); err != nil {
+		c.Logger.Error("database create error", slog.Any("error", err))
+		return nil, err
+	}
+
+	c.Logger.Info("api key created",
+		slog.Any("id", m.ID),
+		slog.Any("organization_id", m.OrganizationID),
+		slog.Any("scopes", m.Scopes))
+	return &APIKeyCreateResponseIDO{APIKey: m, Key: key}, nil
+}
+
+// checkManage returns a `403 Forbidden` error unless the logged in user may
+// manage the keys of the organization.
+func checkManage(ctx context.Context, organizationID primitive.ObjectID) error {
+	if err := permission.Check(ctx, permission.OrganizationManage); err != nil {
+		return err
+	}
+	return permission.CheckOrganization(ctx, organizationID)
+}
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// keyPrefix starts every key so it can be told apart in logs and secret
+// scanners, ex: `cps_1a2b3c4d5e6f7a8b_<secret>`.
+const keyPrefix = "cps_"
+
+// newKey returns a new key along with its public prefix, which is saved as is
+// to look the key up, and its secret, which is only saved hashed.
+func newKey() (key string, prefix string, secret string, err error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	prefix = hex.EncodeToString(b)
+
+	b = make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	secret = base64.RawURLEncoding.EncodeToString(b)
+
+	return keyPrefix + prefix + "_" + secret, prefix, secret, nil
+}
+
+// parseKey returns the prefix and secret of the key; false is returned if the
+// key is malformed.
+func parseKey(key string) (prefix string, secret string, ok bool) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return "", "", false
+	}
+	prefix, secret, ok = strings.Cut(strings.TrimPrefix(key, keyPrefix), "_")
+	if !ok || len(prefix) != 16 || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
@@ -0,0 +1,29 @@
+package controller
+
+import "testing"
+
+func TestNewKey(t *testing.T) {
+	key, prefix, secret, err := newKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedPrefix, parsedSecret, ok := parseKey(key)
+	if !ok || parsedPrefix != prefix || parsedSecret != secret {
+		t.Errorf("expected %v to parse into %v and %v but got %v and %v", key, prefix, secret, parsedPrefix, parsedSecret)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	for _, key := range []string{
+		"",
+		"1a2b3c4d5e6f7a8b_secret",
+		"cps_1a2b3c4d5e6f7a8b",
+		"cps_1a2b_secret",
+		"cps_1a2b3c4d5e6f7a8b_",
+	} {
+		if _, _, ok := parseKey(key); ok {
+			t.Errorf("expected %q to be malformed", key)
+		}
+	}
+}
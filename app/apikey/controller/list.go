@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
+)
+
+func (c *APIKeyControllerImpl) ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*apikey_s.APIKey, error) {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	m, err := c.APIKeyStorer.ListByOrganizationID(ctx, organizationID)
+	if err != nil {
+		c.Logger.Error("database list by organization id error", slog.Any("error", err))
+		return nil, err
+	}
+	return m, nil
+}
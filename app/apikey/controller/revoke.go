@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// RevokeByID stops the key from being accepted; the key is kept so the
+// retailer can see when it was revoked and by whom.
+func (c *APIKeyControllerImpl) RevokeByID(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) error {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return err
+	}
+
+	m, err := c.APIKeyStorer.GetByID(ctx, id)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if m == nil || m.OrganizationID != organizationID {
+		return httperror.NewForNotFoundWithSingleField("id", "api key does not exist")
+	}
+	if m.Status == apikey_s.APIKeyStatusRevoked {
+		return nil
+	}
+
+	m.Status = apikey_s.APIKeyStatusRevoked
+	m.RevokedAt = time.Now()
+	m.RevokedByUserID, _ = ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	if err := c.APIKeyStorer.UpdateByID(ctx, m); err != nil {
+		c.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+
+	c.Logger.Info("api key revoked", slog.Any("id", m.ID), slog.Any("organization_id", m.OrganizationID))
+	return nil
+}
@@ -0,0 +1,86 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+
+	c "github.com/LuchaComics/cps-backend/config"
+)
+
+const (
+	APIKeyStatusActive  = 1
+	APIKeyStatusRevoked = 2
+)
+
+// APIKey lets the point-of-sale system of a retailer call our API on behalf
+// of their organization. Only the hash of the secret is saved; the key is
+// shown once when it gets created.
+type APIKey struct {
+	ID               primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizationID   primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	OrganizationName string             `bson:"organization_name" json:"organization_name"`
+	Name             string             `bson:"name" json:"name"`
+	Prefix           string             `bson:"prefix" json:"prefix"` // Public part of the key used to look it up.
+	HashedSecret     string             `bson:"hashed_secret" json:"-"`
+	HashedSecretAlgo string             `bson:"hashed_secret_algorithm" json:"-"`
+	Scopes           []string           `bson:"scopes" json:"scopes"`
+	Status           int8               `bson:"status" json:"status"`
+	ExpiresAt        time.Time          `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsedAt       time.Time          `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID  primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id"`
+	CreatedByName    string             `bson:"created_by_name" json:"created_by_name"`
+	RevokedAt        time.Time          `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	RevokedByUserID  primitive.ObjectID `bson:"revoked_by_user_id,omitempty" json:"revoked_by_user_id,omitempty"`
+}
+
+// IsExpired returns true if the key has an expiry which passed.
+func (m *APIKey) IsExpired(now time.Time) bool {
+	return !m.ExpiresAt.IsZero() && !now.Before(m.ExpiresAt)
+}
+
+// APIKeyStorer Interface for the api keys of our organizations.
+type APIKeyStorer interface {
+	Create(ctx context.Context, m *APIKey) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*APIKey, error)
+	UpdateByID(ctx context.Context, m *APIKey) error
+	UpdateLastUsedAtByID(ctx context.Context, id primitive.ObjectID, lastUsedAt time.Time) error
+}
+
+type APIKeyStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) APIKeyStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("api_keys")
+
+	// The following few lines of code will create the index for our app for this
+	// colleciton.
+	if _, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "prefix", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &APIKeyStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
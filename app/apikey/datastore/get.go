@@ -0,0 +1,32 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slog"
+)
+
+func (impl APIKeyStorerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*APIKey, error) {
+	return impl.getOne(ctx, bson.M{"_id": id})
+}
+
+func (impl APIKeyStorerImpl) GetByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	return impl.getOne(ctx, bson.M{"prefix": prefix})
+}
+
+func (impl APIKeyStorerImpl) getOne(ctx context.Context, filter bson.M) (*APIKey, error) {
+	var result APIKey
+	err := impl.Collection.FindOne(ctx, filter).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get api key error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,31 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+// ListByOrganizationID returns every key of the organization, newest first,
+// including the revoked ones so the retailer can see their history.
+func (impl APIKeyStorerImpl) ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*APIKey, error) {
+	filter := bson.M{"organization_id": organizationID}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list api keys error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*APIKey{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database decode api keys error", slog.Any("error", err))
+		return nil, err
+	}
+	return results, nil
+}
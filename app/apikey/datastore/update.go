@@ -0,0 +1,34 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+)
+
+func (impl APIKeyStorerImpl) UpdateByID(ctx context.Context, m *APIKey) error {
+	filter := bson.M{"_id": m.ID}
+	update := bson.M{"$set": m}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// UpdateLastUsedAtByID only saves when the key was last used so a request made
+// with the key does not overwrite it being revoked in the meantime.
+func (impl APIKeyStorerImpl) UpdateLastUsedAtByID(ctx context.Context, id primitive.ObjectID, lastUsedAt time.Time) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"last_used_at": lastUsedAt}}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update last used at error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
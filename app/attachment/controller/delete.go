@@ -7,7 +7,7 @@ import (
 	"golang.org/x/exp/slog"
 
 	org_d "github.com/LuchaComics/cps-backend/app/attachment/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
@@ -18,7 +18,7 @@ func (impl *AttachmentControllerImpl) DeleteByID(ctx context.Context, id primiti
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot && userRole != user_d.UserRoleRetailer {
+	if !permission.IsAllowed(ctx, permission.AttachmentManage) {
 		impl.Logger.Error("authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
@@ -27,7 +27,6 @@ func (impl *AttachmentControllerImpl) DeleteByID(ctx context.Context, id primiti
 
 	// Update the database.
 	attachment, err := impl.GetByID(ctx, id)
-	attachment.Status = org_d.StatusArchived
 	if err != nil {
 		impl.Logger.Error("database get by id error", slog.Any("error", err))
 		return err
@@ -36,6 +35,7 @@ func (impl *AttachmentControllerImpl) DeleteByID(ctx context.Context, id primiti
 		impl.Logger.Error("database returns nothing from get by id")
 		return err
 	}
+	attachment.Status = org_d.StatusArchived
 	// // Security: Prevent deletion of root user(s).
 	// if attachment.Type == org_d.RootType {
 	// 	impl.Logger.Warn("root attachment cannot be deleted error")
@@ -56,7 +56,7 @@ func (impl *AttachmentControllerImpl) PermanentlyDeleteByID(ctx context.Context,
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot && userRole != user_d.UserRoleRetailer {
+	if !permission.IsAllowed(ctx, permission.AttachmentManage) {
 		impl.Logger.Error("authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
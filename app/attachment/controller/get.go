@@ -5,22 +5,15 @@ import (
 	"time"
 
 	domain "github.com/LuchaComics/cps-backend/app/attachment/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 )
 
 func (c *AttachmentControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Attachment, error) {
-	// // Extract from our session the following data.
-	// userAttachmentID := ctx.Value(constants.SessionUserAttachmentID).(primitive.ObjectID)
-	// userRole := ctx.Value(constants.SessionUserRole).(int8)
-	//
-	// If user is not administrator nor belongs to the attachment then error.
-	// if userRole != user_d.UserRoleRoot && id != userAttachmentID {
-	// 	c.Logger.Error("authenticated user is not staff role nor belongs to the attachment error",
-	// 		slog.Any("userRole", userRole),
-	// 		slog.Any("userAttachmentID", userAttachmentID))
-	// 	return nil, httperror.NewForForbiddenWithSingleField("message", "you do not belong to this attachment")
-	// }
+	if err := permission.Check(ctx, permission.AttachmentManage); err != nil {
+		return nil, err
+	}
 
 	// Retrieve from our database the record for the specific id.
 	m, err := c.AttachmentStorer.GetByID(ctx, id)
@@ -28,6 +21,14 @@ func (c *AttachmentControllerImpl) GetByID(ctx context.Context, id primitive.Obj
 		c.Logger.Error("database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, nil
+	}
+
+	// If user is not administrator nor belongs to the attachment then error.
+	if err := permission.CheckOrganization(ctx, m.OrganizationID); err != nil {
+		return nil, err
+	}
 
 	// Generate the URL.
 	fileURL, err := c.S3.GetPresignedURL(ctx, m.ObjectKey, 5*time.Minute)
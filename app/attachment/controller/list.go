@@ -5,7 +5,7 @@ import (
 	"time"
 
 	domain "github.com/LuchaComics/cps-backend/app/attachment/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,14 +13,16 @@ import (
 )
 
 func (c *AttachmentControllerImpl) ListByFilter(ctx context.Context, f *domain.AttachmentListFilter) (*domain.AttachmentListResult, error) {
+	if err := permission.Check(ctx, permission.AttachmentManage); err != nil {
+		return nil, err
+	}
+
 	// Extract from our session the following data.
-	orgID := ctx.Value(constants.SessionUserOrganizationID).(primitive.ObjectID)
 	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot {
-		f.OrganizationID = orgID // Force organization tenancy restrictions.
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		f.OrganizationID = organizationID // Force organization tenancy restrictions.
 	}
 
 	c.Logger.Debug("fetching attachments now...", slog.Any("userID", userID))
@@ -50,7 +52,7 @@ func (c *AttachmentControllerImpl) ListAsSelectOptionByFilter(ctx context.Contex
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot {
+	if !permission.IsAllowed(ctx, permission.OrganizationAll) {
 		c.Logger.Error("authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
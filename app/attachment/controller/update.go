@@ -11,7 +11,7 @@ import (
 
 	a_d "github.com/LuchaComics/cps-backend/app/attachment/datastore"
 	domain "github.com/LuchaComics/cps-backend/app/attachment/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
@@ -77,7 +77,7 @@ func (c *AttachmentControllerImpl) UpdateByID(ctx context.Context, req *Attachme
 	userName := ctx.Value(constants.SessionUserName).(string)
 
 	// If user is not administrator nor belongs to the attachment then error.
-	if userRole != user_d.UserRoleRoot && os.OrganizationID != userOrganizationID {
+	if permission.CheckOrganization(ctx, os.OrganizationID) != nil || !permission.IsAllowed(ctx, permission.AttachmentManage) {
 		c.Logger.Error("authenticated user is not staff role nor belongs to the attachment error",
 			slog.Any("userRole", userRole),
 			slog.Any("userOrganizationID", userOrganizationID))
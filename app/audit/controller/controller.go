@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+
+	audit_s "github.com/LuchaComics/cps-backend/app/audit/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+// AuditController Interface for reviewing the audit trail of our accounts.
+type AuditController interface {
+	ListByFilter(ctx context.Context, f *audit_s.EventListFilter) (*audit_s.EventListResult, error)
+}
+
+type AuditControllerImpl struct {
+	Config      *config.Conf
+	Logger      *slog.Logger
+	EventStorer audit_s.EventStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	event_storer audit_s.EventStorer,
+) AuditController {
+	s := &AuditControllerImpl{
+		Config:      appCfg,
+		Logger:      loggerp,
+		EventStorer: event_storer,
+	}
+	s.Logger.Debug("audit controller initialization started...")
+	s.Logger.Debug("audit controller initialized")
+	return s
+}
+
+func (c *AuditControllerImpl) ListByFilter(ctx context.Context, f *audit_s.EventListFilter) (*audit_s.EventListResult, error) {
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return nil, err
+	}
+	m, err := c.EventStorer.ListByFilter(ctx, f)
+	if err != nil {
+		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		return nil, err
+	}
+	return m, nil
+}
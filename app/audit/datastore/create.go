@@ -0,0 +1,20 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+)
+
+func (impl EventStorerImpl) Create(ctx context.Context, m *Event) error {
+	if m.ID == primitive.NilObjectID {
+		m.ID = primitive.NewObjectID()
+	}
+
+	if _, err := impl.Collection.InsertOne(ctx, m); err != nil {
+		impl.Logger.Error("database failed create error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slog"
+
+	c "github.com/LuchaComics/cps-backend/config"
+)
+
+const (
+	EventTypeLoginLocked   = "login.locked"
+	EventTypeLoginUnlocked = "login.unlocked"
+)
+
+// Event records a security related action on an account for root to review.
+type Event struct {
+	ID              primitive.ObjectID `bson:"_id" json:"id"`
+	Type            string             `bson:"type" json:"type"`
+	UserID          primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Email           string             `bson:"email" json:"email"`
+	IPAddress       string             `bson:"ip_address" json:"ip_address"`
+	Message         string             `bson:"message" json:"message"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id,omitempty"` // Empty if done by the system.
+	CreatedByName   string             `bson:"created_by_name" json:"created_by_name"`
+}
+
+type EventListFilter struct {
+	// Pagination related.
+	Cursor   primitive.ObjectID
+	PageSize int64
+
+	// Filter related.
+	UserID primitive.ObjectID
+	Type   string
+}
+
+type EventListResult struct {
+	Results     []*Event           `json:"results"`
+	NextCursor  primitive.ObjectID `json:"next_cursor"`
+	HasNextPage bool               `json:"has_next_page"`
+}
+
+// EventStorer Interface for the audit trail.
+type EventStorer interface {
+	Create(ctx context.Context, m *Event) error
+	ListByFilter(ctx context.Context, f *EventListFilter) (*EventListResult, error)
+}
+
+type EventStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) EventStorer {
+	uc := client.Database(appCfg.DB.Name).Collection("audit_events")
+
+	// The following few lines of code will create the index for our app for this
+	// colleciton.
+	if _, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "type", Value: 1}, {Key: "_id", Value: -1}}},
+	}); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	return &EventStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+}
@@ -0,0 +1,65 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ListByFilter returns the events newest first.
+func (impl EventStorerImpl) ListByFilter(ctx context.Context, f *EventListFilter) (*EventListResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if !f.Cursor.IsZero() {
+		filter["_id"] = bson.M{"$lt": f.Cursor}
+	}
+	if !f.UserID.IsZero() {
+		filter["user_id"] = f.UserID
+	}
+	if f.Type != "" {
+		filter["type"] = f.Type
+	}
+
+	options := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(f.PageSize + 1)
+
+	cursor, err := impl.Collection.Find(ctx, filter, options)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Event{}
+	hasNextPage := false
+	for cursor.Next(ctx) {
+		if int64(len(results)) >= f.PageSize {
+			hasNextPage = true
+			break
+		}
+		document := &Event{}
+		if err := cursor.Decode(document); err != nil {
+			return nil, err
+		}
+		results = append(results, document)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	nextCursor := primitive.NilObjectID
+	if hasNextPage {
+		nextCursor = results[len(results)-1].ID
+	}
+
+	return &EventListResult{
+		Results:     results,
+		NextCursor:  nextCursor,
+		HasNextPage: hasNextPage,
+	}, nil
+}
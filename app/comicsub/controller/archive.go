@@ -2,14 +2,20 @@ package controller
 
 import (
 	"context"
-	"time"
+	"net/http"
 
 	domain "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 )
 
 func (c *ComicSubmissionControllerImpl) ArchiveByID(ctx context.Context, id primitive.ObjectID) (*domain.ComicSubmission, error) {
+	if err := permission.Check(ctx, permission.SubmissionDelete); err != nil {
+		return nil, err
+	}
+
 	// Fetch the original submission.
 	os, err := c.ComicSubmissionStorer.GetByID(ctx, id)
 	if err != nil {
@@ -19,16 +25,26 @@ func (c *ComicSubmissionControllerImpl) ArchiveByID(ctx context.Context, id prim
 	if os == nil {
 		return nil, nil
 	}
+	if err := permission.CheckOrganization(ctx, os.OrganizationID); err != nil {
+		return nil, err
+	}
 
 	// Modify our original submission.
-	os.ModifiedAt = time.Now()
-	os.Status = domain.StatusArchived
+	previousStatus := os.Status
+	t := newStatusTransition(ctx, os, domain.StatusArchived, "")
 
-	// Save to the database the modified submission.
-	if err := c.ComicSubmissionStorer.UpdateByID(ctx, os); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+	// Save to the database the status only.
+	ok, err := c.ComicSubmissionStorer.UpdateStatusByID(ctx, os, previousStatus)
+	if err != nil {
+		c.Logger.Error("database update status by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if !ok {
+		return nil, httperror.NewForSingleField(http.StatusConflict, "status", "submission status was changed by someone else, please try again")
+	}
 
+	for _, hook := range c.StatusTransitionHooks {
+		hook(ctx, os, t)
+	}
 	return os, nil
 }
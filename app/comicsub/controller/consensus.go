@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+// maxInspectorSignOffs is the number of inspectors our submission and
+// certificates have room for.
+const maxInspectorSignOffs = 3
+
+// letterGrades are the letter grades from worst to best.
+var letterGrades = []string{"pr", "fr", "gd", "vg", "fn", "vf", "nm"}
+
+func letterGradeRank(grade string) int {
+	grade = strings.ToLower(grade)
+	for i, g := range letterGrades {
+		if g == grade {
+			return i
+		}
+	}
+	return -1
+}
+
+// computeConsensus returns the grade every inspector would agree on:
+//
+//   - grades and findings use the median of the inspectors, the lower of the
+//     two middle values when the inspectors are split so we never overstate
+//     the condition nor print a value which is not on the scale,
+//   - signs of tampering or restoration are reported if any inspector saw them,
+//   - the remaining yes or no values need a majority.
+//
+// A field where the inspectors are further apart than the tolerance of the
+// config is listed in the disagreements so a supervisor can review it.
+func computeConsensus(cfg *config.Conf, gradingScale int8, gradingNotes string, signOffs []*s_d.InspectorSignOff) *s_d.GradingConsensus {
+	con := &s_d.GradingConsensus{
+		Grade: &s_d.GradeValues{
+			GradingScale: gradingScale,
+			GradingNotes: gradingNotes,
+		},
+		SignOffCount:     len(signOffs),
+		RequiredSignOffs: cfg.Grading.RequiredSignOffs,
+		Disagreements:    []string{},
+		ComputedAt:       time.Now(),
+	}
+	con.IsReached = len(signOffs) > 0 && len(signOffs) >= cfg.Grading.RequiredSignOffs
+	if len(signOffs) == 0 {
+		return con
+	}
+
+	letter := func(name string, value func(g *s_d.GradeValues) string) string {
+		ranks := []int{}
+		for _, s := range signOffs {
+			if r := letterGradeRank(value(s.Grade)); r >= 0 {
+				ranks = append(ranks, r)
+			}
+		}
+		if len(ranks) == 0 {
+			return ""
+		}
+		sort.Ints(ranks)
+		if ranks[len(ranks)-1]-ranks[0] > cfg.Grading.LetterTolerance {
+			con.Disagreements = append(con.Disagreements, name)
+		}
+		return letterGrades[ranks[(len(ranks)-1)/2]]
+	}
+	number := func(name string, tolerance float64, value func(g *s_d.GradeValues) float64) float64 {
+		values := make([]float64, 0, len(signOffs))
+		for _, s := range signOffs {
+			values = append(values, value(s.Grade))
+		}
+		sort.Float64s(values)
+		if values[len(values)-1]-values[0] > tolerance {
+			con.Disagreements = append(con.Disagreements, name)
+		}
+		return values[(len(values)-1)/2]
+	}
+	majority := func(value func(g *s_d.GradeValues) bool) bool {
+		var yes int
+		for _, s := range signOffs {
+			if value(s.Grade) {
+				yes++
+			}
+		}
+		return yes*2 > len(signOffs)
+	}
+
+	g := con.Grade
+	g.CreasesFinding = letter("creases_finding", func(g *s_d.GradeValues) string { return g.CreasesFinding })
+	g.TearsFinding = letter("tears_finding", func(g *s_d.GradeValues) string { return g.TearsFinding })
+	g.MissingPartsFinding = letter("missing_parts_finding", func(g *s_d.GradeValues) string { return g.MissingPartsFinding })
+	g.StainsFinding = letter("stains_finding", func(g *s_d.GradeValues) string { return g.StainsFinding })
+	g.DistortionFinding = letter("distortion_finding", func(g *s_d.GradeValues) string { return g.DistortionFinding })
+	g.PaperQualityFinding = letter("paper_quality_finding", func(g *s_d.GradeValues) string { return g.PaperQualityFinding })
+	g.SpineFinding = letter("spine_finding", func(g *s_d.GradeValues) string { return g.SpineFinding })
+	g.CoverFinding = letter("cover_finding", func(g *s_d.GradeValues) string { return g.CoverFinding })
+
+	switch gradingScale {
+	case s_d.GradingScaleLetter:
+		g.OverallLetterGrade = letter("overall_letter_grade", func(g *s_d.GradeValues) string { return g.OverallLetterGrade })
+		g.IsOverallLetterGradeNearMintPlus = g.OverallLetterGrade == "nm" && majority(func(g *s_d.GradeValues) bool { return g.IsOverallLetterGradeNearMintPlus })
+	case s_d.GradingScaleNumber:
+		g.OverallNumberGrade = number("overall_number_grade", cfg.Grading.NumberTolerance, func(g *s_d.GradeValues) float64 { return g.OverallNumberGrade })
+	case s_d.GradingScaleCPSPercentage:
+		g.CpsPercentageGrade = number("cps_percentage_grade", cfg.Grading.PercentageTolerance, func(g *s_d.GradeValues) float64 { return g.CpsPercentageGrade })
+	}
+
+	g.ShowsSignsOfTamperingOrRestoration = s_d.NoItDoesNotShowsSignsOfTamperingOrRestoration
+	var tampered int
+	for _, s := range signOffs {
+		if s.Grade.ShowsSignsOfTamperingOrRestoration == s_d.YesItShowsSignsOfTamperingOrRestoration {
+			tampered++
+		}
+	}
+	if tampered > 0 {
+		g.ShowsSignsOfTamperingOrRestoration = s_d.YesItShowsSignsOfTamperingOrRestoration
+		if tampered < len(signOffs) {
+			con.Disagreements = append(con.Disagreements, "shows_signs_of_tampering_or_restoration")
+		}
+	}
+	g.IsCpsIndieMintGem = majority(func(g *s_d.GradeValues) bool { return g.IsCpsIndieMintGem })
+	return con
+}
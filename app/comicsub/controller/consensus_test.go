@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+func testGradingConfig(requiredSignOffs int) *config.Conf {
+	cfg := &config.Conf{}
+	cfg.Grading.RequiredSignOffs = requiredSignOffs
+	cfg.Grading.LetterTolerance = 1
+	cfg.Grading.NumberTolerance = 1
+	cfg.Grading.PercentageTolerance = 10
+	return cfg
+}
+
+func signOffWith(grade s_d.GradeValues) *s_d.InspectorSignOff {
+	for _, f := range []*string{&grade.CreasesFinding, &grade.TearsFinding, &grade.MissingPartsFinding, &grade.StainsFinding,
+		&grade.DistortionFinding, &grade.PaperQualityFinding, &grade.SpineFinding, &grade.CoverFinding} {
+		if *f == "" {
+			*f = "vf"
+		}
+	}
+	if grade.ShowsSignsOfTamperingOrRestoration == 0 {
+		grade.ShowsSignsOfTamperingOrRestoration = s_d.NoItDoesNotShowsSignsOfTamperingOrRestoration
+	}
+	return &s_d.InspectorSignOff{Grade: &grade}
+}
+
+func TestConsensusNumberMedian(t *testing.T) {
+	con := computeConsensus(testGradingConfig(3), s_d.GradingScaleNumber, "", []*s_d.InspectorSignOff{
+		signOffWith(s_d.GradeValues{OverallNumberGrade: 9.8}),
+		signOffWith(s_d.GradeValues{OverallNumberGrade: 9.0}),
+		signOffWith(s_d.GradeValues{OverallNumberGrade: 9.4}),
+	})
+	if con.Grade.OverallNumberGrade != 9.4 {
+		t.Errorf("expected median 9.4 but got %v", con.Grade.OverallNumberGrade)
+	}
+	if !con.IsReached {
+		t.Error("expected consensus to be reached")
+	}
+	if len(con.Disagreements) != 0 {
+		t.Errorf("expected no disagreements but got %v", con.Disagreements)
+	}
+	if con.Grade.CreasesFinding != "vf" {
+		t.Errorf("expected vf creases finding but got %v", con.Grade.CreasesFinding)
+	}
+}
+
+func TestConsensusNumberSplitStaysOnScale(t *testing.T) {
+	con := computeConsensus(testGradingConfig(2), s_d.GradingScaleNumber, "", []*s_d.InspectorSignOff{
+		signOffWith(s_d.GradeValues{OverallNumberGrade: 9.0}),
+		signOffWith(s_d.GradeValues{OverallNumberGrade: 8.5}),
+	})
+	if con.Grade.OverallNumberGrade != 8.5 {
+		t.Errorf("expected lower median 8.5 but got %v", con.Grade.OverallNumberGrade)
+	}
+}
+
+func TestConsensusPercentageDisagreement(t *testing.T) {
+	con := computeConsensus(testGradingConfig(2), s_d.GradingScaleCPSPercentage, "", []*s_d.InspectorSignOff{
+		signOffWith(s_d.GradeValues{CpsPercentageGrade: 90}),
+		signOffWith(s_d.GradeValues{CpsPercentageGrade: 70}),
+	})
+	if con.Grade.CpsPercentageGrade != 70 {
+		t.Errorf("expected lower median 70 but got %v", con.Grade.CpsPercentageGrade)
+	}
+	if !reflect.DeepEqual(con.Disagreements, []string{"cps_percentage_grade"}) {
+		t.Errorf("expected percentage disagreement but got %v", con.Disagreements)
+	}
+}
+
+func TestConsensusLetterRoundsDown(t *testing.T) {
+	con := computeConsensus(testGradingConfig(2), s_d.GradingScaleLetter, "Notes", []*s_d.InspectorSignOff{
+		signOffWith(s_d.GradeValues{OverallLetterGrade: "NM", IsOverallLetterGradeNearMintPlus: true, CoverFinding: "nm"}),
+		signOffWith(s_d.GradeValues{OverallLetterGrade: "vf", CoverFinding: "pr", ShowsSignsOfTamperingOrRestoration: s_d.YesItShowsSignsOfTamperingOrRestoration}),
+	})
+	g := con.Grade
+	if g.OverallLetterGrade != "vf" {
+		t.Errorf("expected vf but got %v", g.OverallLetterGrade)
+	}
+	if g.IsOverallLetterGradeNearMintPlus {
+		t.Error("expected no near mint plus")
+	}
+	if g.CoverFinding != "pr" {
+		t.Errorf("expected pr cover finding but got %v", g.CoverFinding)
+	}
+	if g.ShowsSignsOfTamperingOrRestoration != s_d.YesItShowsSignsOfTamperingOrRestoration {
+		t.Error("expected signs of tampering")
+	}
+	if g.GradingNotes != "Notes" {
+		t.Errorf("expected the grading notes of the submission but got %v", g.GradingNotes)
+	}
+	expected := []string{"cover_finding", "shows_signs_of_tampering_or_restoration"}
+	if !reflect.DeepEqual(con.Disagreements, expected) {
+		t.Errorf("expected %v but got %v", expected, con.Disagreements)
+	}
+}
+
+func TestConsensusRequiredSignOffs(t *testing.T) {
+	con := computeConsensus(testGradingConfig(2), s_d.GradingScaleNumber, "", []*s_d.InspectorSignOff{
+		signOffWith(s_d.GradeValues{OverallNumberGrade: 9.8}),
+	})
+	if con.IsReached {
+		t.Error("expected consensus to not be reached with one of two sign-offs")
+	}
+	if con := computeConsensus(testGradingConfig(0), s_d.GradingScaleNumber, "", nil); con.IsReached {
+		t.Error("expected consensus to not be reached without sign-offs")
+	}
+}
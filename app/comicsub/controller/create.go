@@ -2,19 +2,19 @@ package controller
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
-	"github.com/LuchaComics/cps-backend/adapter/pdfbuilder"
 	domain "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
 	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 type ComicSubmissionCreateRequestIDO struct {
@@ -49,7 +49,6 @@ type ComicSubmissionCreateRequestIDO struct {
 	CpsPercentageGrade                 float64                       `bson:"cps_percentage_grade" json:"cps_percentage_grade"`
 	IsOverallLetterGradeNearMintPlus   bool                          `bson:"is_overall_letter_grade_near_mint_plus" json:"is_overall_letter_grade_near_mint_plus"`
 	CollectibleType                    int8                          `bson:"collectible_type" json:"collectible_type"`
-	Status                             int8                          `bson:"status" json:"status"`
 	Signatures                         []*domain.SubmissionSignature `bson:"signatures" json:"signatures,omitempty"`
 }
 
@@ -86,12 +85,15 @@ func comicSubmissionFromCreate(req *ComicSubmissionCreateRequestIDO) *s_d.ComicS
 		CpsPercentageGrade:                 req.CpsPercentageGrade,
 		IsOverallLetterGradeNearMintPlus:   req.IsOverallLetterGradeNearMintPlus,
 		CollectibleType:                    req.CollectibleType,
-		Status:                             req.Status,
 		Signatures:                         req.Signatures,
 	}
 }
 
 func (c *ComicSubmissionControllerImpl) Create(ctx context.Context, req *ComicSubmissionCreateRequestIDO) (*s_d.ComicSubmission, error) {
+	if err := permission.Check(ctx, permission.SubmissionCreate); err != nil {
+		return nil, err
+	}
+
 	// DEVELOPERS NOTE:
 	// Every submission creation is dependent on the `role` of the logged in
 	// user in our system so we need to extract it right away.
@@ -103,49 +105,33 @@ func (c *ComicSubmissionControllerImpl) Create(ctx context.Context, req *ComicSu
 
 	m := comicSubmissionFromCreate(req) // Convert into our data-structure.
 
+	// Reject service types we cannot generate a certificate for before we
+	// use up a `CPS Registry Number`.
+	if !c.CertificateRenderers.IsSupported(m.ServiceType) {
+		return nil, httperror.NewForBadRequestWithSingleField("service_type", fmt.Sprintf("unsupported service type: %v", m.ServiceType))
+	}
+
 	// DEVELOPERS NOTE:
-	// Every submission needs to have a unique `CPS Registry Number` (CPRN)
-	// generated. The following needs to happen to generate the unique CPRN:
-	// 1. Make the `Create` function be `atomic` and thus lock this function.
-	// 2. Count total submissions in system.
-	// 3. Generate CPRN.
-	// 4. Apply the CPRN to the submission.
-	// 5. Unlock this `Create` function to be usable again by other calls.
-	c.Logger.Debug("applying mutex")
-	c.Kmutex.Lock("CPS-BACKEND-SUBMISSION-INSERTION") // Step 1
-	defer func() {
-		c.Kmutex.Unlock("CPS-BACKEND-SUBMISSION-INSERTION") // Step 5
-		c.Logger.Debug("removing mutex")
-	}()
-	f := &domain.ComicSubmissionListFilter{CreatedByUserRole: userRole} // Part of ID requires count of staff or retailer.
-	total, err := c.ComicSubmissionStorer.CountByFilter(ctx, f)         // Step 2
+	// Every submission needs to have a unique `CPS Registry Number` (CPRN).
+	// The allocator atomically reserves the next number for the role inside
+	// the database so concurrent requests, even across multiple instances of
+	// this server, never receive the same number.
+	cpsrn, err := c.CPSRNAllocator.Allocate(ctx, registryRole(userRole))
 	if err != nil {
-		c.Logger.Error("count all submissions error", slog.Any("error", err))
+		c.Logger.Error("allocate cpsrn error", slog.Any("error", err))
 		return nil, err
 	}
-	m.CPSRN = c.CPSRN.GenerateNumber(userRole, total) // Step 3 & 4
+	m.CPSRN = cpsrn
 	c.Logger.Debug("Generated CPSRN",
 		slog.String("CPSRN", m.CPSRN),
-		slog.Int64("Role", int64(userRole)),
-		slog.Int64("total", total))
-
-	// Auto-assign the user-if
-	m.UserFirstName = ctx.Value(constants.SessionUserFirstName).(string)
-	m.UserLastName = ctx.Value(constants.SessionUserLastName).(string)
+		slog.Int64("Role", int64(userRole)))
 
 	// DEVELOPERS NOTE:
-	// Every submission creation is dependent on the `role` of the logged in
-	// user in our system; however, the root administrator has the ability to
-	// assign whatever organization you want.
-	switch userRole {
-	case u_d.UserRoleRetailer:
-		c.Logger.Debug("retailer assigning their organization")
-		m.OrganizationID = ctx.Value(constants.SessionUserOrganizationID).(primitive.ObjectID)
-	case u_d.UserRoleRoot:
-		c.Logger.Debug("admin picking custom organization")
-	default:
-		c.Logger.Error("unsupported role", slog.Any("role", userRole))
-		return nil, fmt.Errorf("unsupported role via: %v", userRole)
+	// Users limited to their organization always submit into it; however, our
+	// staff has the ability to assign whatever organization you want.
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		c.Logger.Debug("assigning the organization of the user")
+		m.OrganizationID = organizationID
 	}
 
 	// Lookup the organization.
@@ -161,16 +147,7 @@ func (c *ComicSubmissionControllerImpl) Create(ctx context.Context, req *ComicSu
 	m.OrganizationID = org.ID
 	m.OrganizationName = org.Name
 
-	// Add defaults.
-	m.ID = primitive.NewObjectID()
-	m.CreatedByUserID = ctx.Value(constants.SessionUserID).(primitive.ObjectID)
-	m.CreatedByUserRole = userRole
-	m.CreatedAt = time.Now()
-	m.ModifiedByUserID = ctx.Value(constants.SessionUserID).(primitive.ObjectID)
-	m.ModifiedByUserRole = userRole
-	m.ModifiedAt = time.Now()
-	m.SubmissionDate = time.Now()
-	m.Item = fmt.Sprintf("%v, %v, %v", m.SeriesTitle, m.IssueVol, m.IssueNo)
+	setCreateDefaults(ctx, m, userRole)
 
 	// Attach a copy of the customer to our record.
 	customerUser, err := c.UserStorer.GetByID(ctx, m.UserID)
@@ -188,295 +165,59 @@ func (c *ComicSubmissionControllerImpl) Create(ctx context.Context, req *ComicSu
 		return nil, err
 	}
 
-	// Look up the publisher names and get the correct display name or get the other.
-	var publisherNameDisplay string = constants.SubmissionPublisherNames[m.PublisherName]
-	if m.PublisherName == constants.SubmissionPublisherNameOther {
-		publisherNameDisplay = m.PublisherNameOther
+	// The certificate is generated in the background by our job workers so
+	// the request does not have to wait on the PDF builder, S3 and Mailgun.
+	if _, err := c.enqueueJob(ctx, job_s.TypeSendComicSubmissionCreatedEmails, m.ID); err != nil {
+		c.Logger.Error("enqueue emails job error", slog.Any("error", err))
+		return nil, err
 	}
-
-	//
-	// Generate the PDF file based on the `service type`.
-	//
-
-	pdfResponse := &pdfbuilder.PDFBuilderResponseDTO{}
-
-	switch m.ServiceType {
-	case s_d.ServiceTypePreScreening:
-		c.Logger.Debug("beginning to generate `pre-screening` pdf")
-		// The next following lines of code will create the PDF file gnerator
-		// request to be submitted into our PDF file generator to generate the data.
-		r := &pdfbuilder.CBFFBuilderRequestDTO{
-			CPSRN:                              m.CPSRN,
-			Filename:                           fmt.Sprintf("%v.pdf", m.ID.Hex()),
-			SubmissionDate:                     time.Now(),
-			SeriesTitle:                        m.SeriesTitle,
-			IssueVol:                           m.IssueVol,
-			IssueNo:                            m.IssueNo,
-			IssueCoverYear:                     m.IssueCoverYear,
-			IssueCoverMonth:                    m.IssueCoverMonth,
-			PublisherName:                      publisherNameDisplay,
-			SpecialNotes:                       m.SpecialNotes,
-			GradingNotes:                       m.GradingNotes,
-			CreasesFinding:                     m.CreasesFinding,
-			TearsFinding:                       m.TearsFinding,
-			MissingPartsFinding:                m.MissingPartsFinding,
-			StainsFinding:                      m.StainsFinding,
-			DistortionFinding:                  m.DistortionFinding,
-			PaperQualityFinding:                m.PaperQualityFinding,
-			SpineFinding:                       m.SpineFinding,
-			CoverFinding:                       m.CoverFinding,
-			ShowsSignsOfTamperingOrRestoration: m.ShowsSignsOfTamperingOrRestoration == 1,
-			GradingScale:                       m.GradingScale,
-			OverallLetterGrade:                 m.OverallLetterGrade,
-			OverallNumberGrade:                 m.OverallNumberGrade,
-			CpsPercentageGrade:                 m.CpsPercentageGrade,
-			UserFirstName:                      m.UserFirstName,
-			UserLastName:                       m.UserLastName,
-			UserOrganizationName:               m.OrganizationName,
-			Signatures:                         m.Signatures,
-			PrimaryLabelDetails:                m.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:           m.PrimaryLabelDetailsOther,
-		}
-		pdfResponse, err = c.CBFFBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `pre-screening` pdf")
-	case s_d.ServiceTypePedigree:
-		c.Logger.Debug("beginning to generate `pedigree` pdf")
-		// The next following lines of code will create the PDF file gnerator
-		// request to be submitted into our PDF file generator to generate the data.
-		r := &pdfbuilder.PCBuilderRequestDTO{
-			CPSRN:                              m.CPSRN,
-			Filename:                           fmt.Sprintf("%v.pdf", m.ID.Hex()),
-			SubmissionDate:                     time.Now(),
-			SeriesTitle:                        m.SeriesTitle,
-			IssueVol:                           m.IssueVol,
-			IssueNo:                            m.IssueNo,
-			IssueCoverYear:                     m.IssueCoverYear,
-			IssueCoverMonth:                    m.IssueCoverMonth,
-			PublisherName:                      publisherNameDisplay,
-			SpecialNotes:                       m.SpecialNotes,
-			GradingNotes:                       m.GradingNotes,
-			CreasesFinding:                     m.CreasesFinding,
-			TearsFinding:                       m.TearsFinding,
-			MissingPartsFinding:                m.MissingPartsFinding,
-			StainsFinding:                      m.StainsFinding,
-			DistortionFinding:                  m.DistortionFinding,
-			PaperQualityFinding:                m.PaperQualityFinding,
-			SpineFinding:                       m.SpineFinding,
-			CoverFinding:                       m.CoverFinding,
-			ShowsSignsOfTamperingOrRestoration: m.ShowsSignsOfTamperingOrRestoration == 1,
-			GradingScale:                       m.GradingScale,
-			OverallLetterGrade:                 m.OverallLetterGrade,
-			OverallNumberGrade:                 m.OverallNumberGrade,
-			CpsPercentageGrade:                 m.CpsPercentageGrade,
-			UserFirstName:                      m.UserFirstName,
-			UserLastName:                       m.UserLastName,
-			UserOrganizationName:               m.OrganizationName,
-			Signatures:                         m.Signatures,
-			PrimaryLabelDetails:                m.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:           m.PrimaryLabelDetailsOther,
-		}
-		pdfResponse, err = c.PCBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `pedigree` pdf")
-	case s_d.ServiceTypeCPSCapsule:
-		c.Logger.Debug("beginning to generate `cc` pdf")
-		r := &pdfbuilder.CCBuilderRequestDTO{
-			CPSRN:                            m.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", m.ID.Hex()),
-			SeriesTitle:                      m.SeriesTitle,
-			IssueVol:                         m.IssueVol,
-			IssueNo:                          m.IssueNo,
-			IssueCoverYear:                   m.IssueCoverYear,
-			IssueCoverMonth:                  m.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     m.SpecialNotes,
-			PrimaryLabelDetails:              m.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         m.PrimaryLabelDetailsOther,
-			GradingScale:                     m.GradingScale,
-			OverallLetterGrade:               m.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: m.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               m.OverallNumberGrade,
-			CpsPercentageGrade:               m.CpsPercentageGrade,
-			UserFirstName:                    m.UserFirstName,
-			UserLastName:                     m.UserLastName,
-			UserOrganizationName:             m.OrganizationName,
-			Signatures:                       m.Signatures,
-		}
-		pdfResponse, err = c.CCBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `cc` pdf")
-	case s_d.ServiceTypeCPSCapsuleSignatureCollection:
-		c.Logger.Debug("beginning to generate `ccsc` pdf")
-		r := &pdfbuilder.CCSCBuilderRequestDTO{
-			CPSRN:                            m.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", m.ID.Hex()),
-			SeriesTitle:                      m.SeriesTitle,
-			IssueVol:                         m.IssueVol,
-			IssueNo:                          m.IssueNo,
-			IssueCoverYear:                   m.IssueCoverYear,
-			IssueCoverMonth:                  m.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     m.SpecialNotes,
-			PrimaryLabelDetails:              m.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         m.PrimaryLabelDetailsOther,
-			GradingScale:                     m.GradingScale,
-			OverallLetterGrade:               m.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: m.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               m.OverallNumberGrade,
-			CpsPercentageGrade:               m.CpsPercentageGrade,
-			UserFirstName:                    m.UserFirstName,
-			UserLastName:                     m.UserLastName,
-			UserOrganizationName:             m.OrganizationName,
-			Signatures:                       m.Signatures,
-		}
-		pdfResponse, err = c.CCSCBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `ccsc` pdf")
-	case s_d.ServiceTypeCPSCapsuleIndieMintGem:
-		c.Logger.Debug("beginning to generate `ccimg` pdf")
-		r := &pdfbuilder.CCIMGBuilderRequestDTO{
-			CPSRN:                            m.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", m.ID.Hex()),
-			SeriesTitle:                      m.SeriesTitle,
-			IssueVol:                         m.IssueVol,
-			IssueNo:                          m.IssueNo,
-			IssueCoverYear:                   m.IssueCoverYear,
-			IssueCoverMonth:                  m.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     m.SpecialNotes,
-			PrimaryLabelDetails:              m.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         m.PrimaryLabelDetailsOther,
-			GradingScale:                     m.GradingScale,
-			OverallLetterGrade:               m.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: m.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               m.OverallNumberGrade,
-			CpsPercentageGrade:               m.CpsPercentageGrade,
-			UserFirstName:                    m.UserFirstName,
-			UserLastName:                     m.UserLastName,
-			UserOrganizationName:             m.OrganizationName,
-			Signatures:                       m.Signatures,
-		}
-		pdfResponse, err = c.CCIMGBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `ccimg` pdf")
-	case s_d.ServiceTypeCPSCapsuleYouGrade:
-		c.Logger.Debug("beginning to generate `ccug` pdf")
-		r := &pdfbuilder.CCUGBuilderRequestDTO{
-			CPSRN:                            m.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", m.ID.Hex()),
-			SeriesTitle:                      m.SeriesTitle,
-			IssueVol:                         m.IssueVol,
-			IssueNo:                          m.IssueNo,
-			IssueCoverYear:                   m.IssueCoverYear,
-			IssueCoverMonth:                  m.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     m.SpecialNotes,
-			PrimaryLabelDetails:              m.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         m.PrimaryLabelDetailsOther,
-			GradingScale:                     m.GradingScale,
-			OverallLetterGrade:               m.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: m.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               m.OverallNumberGrade,
-			CpsPercentageGrade:               m.CpsPercentageGrade,
-			UserFirstName:                    m.UserFirstName,
-			UserLastName:                     m.UserLastName,
-			UserOrganizationName:             m.OrganizationName,
-			Signatures:                       m.Signatures,
-		}
-		pdfResponse, err = c.CCUGBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `ccug` pdf")
-	default:
-		panic("UNSUPPORTED")
+	if err := c.enqueuePDF(ctx, m); err != nil {
+		c.Logger.Error("enqueue pdf job error", slog.Any("error", err))
+		return nil, err
 	}
 
-	// The next few lines will upload our PDF to our remote storage. Once the
-	// file is saved remotely, we will have a connection to it through a "key"
-	// unique reference to the uploaded file.
-	path := fmt.Sprintf("uploads/%v", pdfResponse.FileName)
-
-	c.Logger.Debug("S3 will upload...",
-		slog.String("path", path))
-
-	err = c.S3.UploadContent(ctx, path, pdfResponse.Content)
-	if err != nil {
-		c.Logger.Error("s3 upload error", slog.Any("error", err))
-		return nil, err
+	for _, hook := range c.CreatedHooks {
+		hook(ctx, m)
 	}
+	return m, nil
+}
 
-	c.Logger.Debug("S3 uploaded with success",
-		slog.String("path", path))
+// CreatedHook is notified after a submission was saved for the first time.
+type CreatedHook func(ctx context.Context, m *s_d.ComicSubmission)
 
-	// The following will save the S3 key of our file upload into our record.
-	m.FileUploadS3ObjectKey = path
-	m.ModifiedAt = time.Now()
+// AddCreatedHook registers a hook to be notified after every submission
+// created, including the submissions of an order.
+func (c *ComicSubmissionControllerImpl) AddCreatedHook(hook CreatedHook) {
+	c.CreatedHooks = append(c.CreatedHooks, hook)
+}
 
-	if err := c.ComicSubmissionStorer.UpdateByID(ctx, m); err != nil {
-		c.Logger.Error("database update error", slog.Any("error", err))
-		return nil, err
-	}
+// setCreateDefaults fills in the values of a new submission which are set by
+// our system and not by the user.
+func setCreateDefaults(ctx context.Context, m *s_d.ComicSubmission, userRole int8) {
+	m.ID = primitive.NewObjectID()
+	m.CreatedByUserID = ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	m.CreatedByUserRole = userRole
+	m.CreatedAt = time.Now()
+	m.ModifiedByUserID = ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	m.ModifiedByUserRole = userRole
+	m.ModifiedAt = time.Now()
+	m.SubmissionDate = time.Now()
+	m.Item = fmt.Sprintf("%v, %v, %v", m.SeriesTitle, m.IssueVol, m.IssueNo)
 
-	// The following will generate a pre-signed URL so user can download the file.
-	downloadableURL, err := c.S3.GetDownloadablePresignedURL(ctx, m.FileUploadS3ObjectKey, time.Minute*15)
-	if err != nil {
-		c.Logger.Error("s3 presign error", slog.Any("error", err))
-		return nil, err
-	}
-	m.FileUploadDownloadableFileURL = downloadableURL
+	// Auto-assign the user-if
+	m.UserFirstName = ctx.Value(constants.SessionUserFirstName).(string)
+	m.UserLastName = ctx.Value(constants.SessionUserLastName).(string)
 
-	// Removing local file from the directory and don't do anything if we have errors.
-	if err := os.Remove(pdfResponse.FilePath); err != nil {
-		c.Logger.Warn("removing local file error", slog.Any("error", err))
-		// Just continue even if we get an error...
-	}
+	// Every submission enters our state machine as received.
+	newStatusTransition(ctx, m, s_d.StatusReceived, "")
+}
 
-	// The following code will send the email notifications to the correct individuals.
-	if err := c.sendNewComicSubmissionEmails(m); err != nil {
-		c.Logger.Error("database update error", slog.Any("error", err))
-		// Do not return error, just keep it in the server logs.
+// registryRole returns the role whose `CPS Registry Number` sequence the
+// submissions of the user are numbered from; our staff, who are not limited
+// to one organization, share the sequence of the root administrator.
+func registryRole(userRole int8) int8 {
+	if permission.Has(userRole, permission.OrganizationAll) {
+		return u_d.UserRoleRoot
 	}
-	return m, nil
+	return userRole
 }
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// ComicSubmissionCreateManyRequestIDO creates the submissions of an order
+// for one organization and customer in one go.
+type ComicSubmissionCreateManyRequestIDO struct {
+	OrderID        primitive.ObjectID
+	OrganizationID primitive.ObjectID
+	UserID         primitive.ObjectID
+	Lines          []*ComicSubmissionCreateRequestIDO
+}
+
+// CreateMany creates a submission for every line of the request. Every line
+// is checked before any `CPS Registry Number` is allocated so one bad line
+// does not use up numbers; the errors are keyed by line, ex:
+// `lines[3].service_type`.
+func (c *ComicSubmissionControllerImpl) CreateMany(ctx context.Context, req *ComicSubmissionCreateManyRequestIDO) ([]*s_d.ComicSubmission, error) {
+	if err := permission.Check(ctx, permission.SubmissionCreate); err != nil {
+		return nil, err
+	}
+	userRole, ok := ctx.Value(constants.SessionUserRole).(int8)
+	if !ok {
+		c.Logger.Error("user role not extracted from session")
+		return nil, fmt.Errorf("user role not extracted from session for submission with user role: %v", userRole)
+	}
+	if len(req.Lines) == 0 {
+		return nil, httperror.NewForBadRequestWithSingleField("lines", "missing value")
+	}
+
+	e := make(map[string]string)
+	for i, line := range req.Lines {
+		if !c.CertificateRenderers.IsSupported(line.ServiceType) {
+			e[fmt.Sprintf("lines[%d].service_type", i)] = fmt.Sprintf("unsupported service type: %v", line.ServiceType)
+		}
+	}
+	if len(e) != 0 {
+		return nil, httperror.NewForBadRequest(&e)
+	}
+
+	// Lookup the organization and the customer every line belongs to.
+	org, err := c.OrganizationStorer.GetByID(ctx, req.OrganizationID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if org == nil {
+		return nil, httperror.NewForBadRequestWithSingleField("organization_id", "organization does not exist")
+	}
+	var customer *s_d.SubmissionUser
+	if !req.UserID.IsZero() {
+		u, err := c.UserStorer.GetByID(ctx, req.UserID)
+		if err != nil {
+			c.Logger.Error("database get customer by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if u == nil {
+			return nil, httperror.NewForBadRequestWithSingleField("user_id", "customer does not exist")
+		}
+		customer = userToSubmissionUserCopy(u)
+	}
+
+	// DEVELOPERS NOTE:
+	// Reserve the numbers of all the lines at once so the submissions of an
+	// order are numbered consecutively.
+	cpsrns, err := c.CPSRNAllocator.AllocateMany(ctx, registryRole(userRole), len(req.Lines))
+	if err != nil {
+		c.Logger.Error("allocate many cpsrn error", slog.Any("error", err))
+		return nil, err
+	}
+
+	ms := make([]*s_d.ComicSubmission, len(req.Lines))
+	for i, line := range req.Lines {
+		m := comicSubmissionFromCreate(line)
+		setCreateDefaults(ctx, m, userRole)
+		m.CPSRN = cpsrns[i]
+		m.OrderID = req.OrderID
+		m.OrganizationID = org.ID
+		m.OrganizationName = org.Name
+		m.UserID = req.UserID
+		m.User = customer
+		ms[i] = m
+	}
+
+	// Save to our database.
+	if err := c.ComicSubmissionStorer.CreateMany(ctx, ms); err != nil {
+		c.Logger.Error("database create many error", slog.Any("error", err))
+		return nil, err
+	}
+	c.Logger.Debug("created submissions",
+		slog.Any("order_id", req.OrderID),
+		slog.Int("count", len(ms)))
+
+	for _, m := range ms {
+		if err := c.enqueuePDF(ctx, m); err != nil {
+			c.Logger.Error("enqueue pdf job error", slog.Any("error", err))
+			return nil, err
+		}
+	}
+	for _, m := range ms {
+		for _, hook := range c.CreatedHooks {
+			hook(ctx, m)
+		}
+	}
+	return ms, nil
+}
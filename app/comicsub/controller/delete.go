@@ -5,9 +5,15 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
 )
 
 func (impl *ComicSubmissionControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	if err := permission.Check(ctx, permission.SubmissionDelete); err != nil {
+		return err
+	}
+
 	// STEP 1: Lookup the record or error.
 	submission, err := impl.GetByID(ctx, id)
 	if err != nil {
@@ -134,3 +134,63 @@ func (impl *ComicSubmissionControllerImpl) sendRetailerNewComicSubmissionEmail(r
 		slog.Any("organization-id", m.OrganizationID))
 	return nil
 }
+
+func (impl *ComicSubmissionControllerImpl) sendStatusChangedEmails(ctx context.Context, m *s_d.ComicSubmission, t *s_d.StatusTransition) error {
+	impl.Logger.Debug("sending status changed to all retailer staff",
+		slog.Any("submission-id", m.ID),
+		slog.Any("organization-id", m.OrganizationID))
+
+	response, err := impl.UserStorer.ListAllRetailerStaffForOrganizationID(ctx, m.OrganizationID)
+	if err != nil {
+		impl.Logger.Error("database list all retailer error", slog.Any("error", err))
+		return err
+	}
+	emails := make([]string, 0, len(response.Results)+1)
+	for _, u := range response.Results {
+		emails = append(emails, u.Email)
+	}
+	if m.User != nil && m.User.Email != "" {
+		emails = append(emails, m.User.Email)
+	}
+
+	fp := path.Join("templates", "submission_status_changed.html")
+	tmpl, err := template.ParseFiles(fp)
+	if err != nil {
+		impl.Logger.Error("parsing error", slog.Any("error", err))
+		return err
+	}
+
+	var processed bytes.Buffer
+
+	// Render the HTML template with our data.
+	data := struct {
+		Item       string
+		CPSRN      string
+		Status     string
+		Reason     string
+		DetailLink string
+	}{
+		Item:       m.Item,
+		CPSRN:      m.CPSRN,
+		Status:     statusLabel(t.To),
+		Reason:     t.Reason,
+		DetailLink: fmt.Sprintf("https://%v/submission/%v", impl.Emailer.GetDomainName(), m.ID.Hex()),
+	}
+	if err := tmpl.Execute(&processed, data); err != nil {
+		impl.Logger.Error("template execution error", slog.Any("error", err))
+		return err
+	}
+	body := processed.String() // DEVELOPERS NOTE: Convert our long sequence of data into a string.
+
+	subject := fmt.Sprintf("Submission %v is %v", m.CPSRN, statusLabel(t.To))
+	for _, email := range emails {
+		if err := impl.Emailer.Send(ctx, impl.Emailer.GetSenderEmail(), subject, email, body); err != nil {
+			impl.Logger.Error("sending error", slog.Any("error", err))
+			return err
+		}
+	}
+	impl.Logger.Debug("sent `Submission Status Changed` emails",
+		slog.Int("count", len(emails)),
+		slog.Any("submission-id", m.ID))
+	return nil
+}
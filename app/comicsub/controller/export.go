@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+
+	domain "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+)
+
+// ExportByFilter calls the function with every submission of the filter
+// while they are read from the database, scoped to the organization of the
+// logged in user like `ListByFilter`.
+func (c *ComicSubmissionControllerImpl) ExportByFilter(ctx context.Context, f *domain.ComicSubmissionListFilter, fn func(m *domain.ComicSubmission) error) error {
+	if err := permission.Check(ctx, permission.SubmissionRead); err != nil {
+		return err
+	}
+
+	// Apply filtering based on tenancy if the user is limited to their organization.
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		f.OrganizationID = organizationID
+	}
+
+	if err := c.ComicSubmissionStorer.ExportByFilter(ctx, f, fn); err != nil {
+		c.Logger.Error("database export by filter error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
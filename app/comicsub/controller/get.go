@@ -2,22 +2,34 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
 	domain "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 func (c *ComicSubmissionControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.ComicSubmission, error) {
+	if err := permission.Check(ctx, permission.SubmissionRead); err != nil {
+		return nil, err
+	}
+
 	// Retrieve from our database the record for the specific id.
 	m, err := c.ComicSubmissionStorer.GetByID(ctx, id)
 	if err != nil {
 		c.Logger.Error("database get by id error", slog.Any("error", err))
 		return nil, err
 	}
+	if m == nil {
+		return nil, nil
+	}
+	if err := permission.CheckOrganization(ctx, m.OrganizationID); err != nil {
+		return nil, err
+	}
 
 	// The following will generate a pre-signed URL so user can download the file.
 	downloadableURL, err := c.S3.GetDownloadablePresignedURL(ctx, m.FileUploadS3ObjectKey, time.Minute*15)
@@ -33,6 +45,13 @@ func (c *ComicSubmissionControllerImpl) GetByID(ctx context.Context, id primitiv
 }
 
 func (c *ComicSubmissionControllerImpl) GetByCPSRN(ctx context.Context, cpsrn string) (*domain.ComicSubmission, error) {
+	// Reject malformed numbers and typos before hitting the database.
+	cpsrn = strings.TrimSpace(cpsrn)
+	if err := c.CPSRN.Validate(cpsrn); err != nil {
+		c.Logger.Warn("submission registry lookup with invalid cpsrn", slog.String("cpsrn", cpsrn), slog.Any("error", err))
+		return nil, httperror.NewForBadRequestWithSingleField("cpsrn", err.Error())
+	}
+
 	// Retrieve from our database the record for the specific cspn.
 	m, err := c.ComicSubmissionStorer.GetByCPSRN(ctx, cpsrn)
 	if err != nil {
@@ -44,5 +63,8 @@ func (c *ComicSubmissionControllerImpl) GetByCPSRN(ctx context.Context, cpsrn st
 		return nil, httperror.NewForBadRequestWithSingleField("message", "registry entry does not exist")
 	}
 
+	// Let the public know if the grade was changed after the certificate was issued.
+	m.RegistrySignatureVerified = c.verifyRegistryRecord(m)
+
 	return m, err
 }
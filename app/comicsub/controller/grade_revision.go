@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// ListGradeRevisionsBySubmissionID returns the grade history of the
+// submission, newest first, with a link to download every superseded
+// certificate.
+func (c *ComicSubmissionControllerImpl) ListGradeRevisionsBySubmissionID(ctx context.Context, submissionID primitive.ObjectID) ([]*s_d.GradeRevision, error) {
+	if _, err := c.getPermittedSubmission(ctx, submissionID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := c.ComicSubmissionStorer.ListGradeRevisionsBySubmissionID(ctx, submissionID)
+	if err != nil {
+		c.Logger.Error("database list grade revisions error", slog.Any("error", err))
+		return nil, err
+	}
+	for _, r := range revisions {
+		if r.SupersededFileUploadS3ObjectKey == "" {
+			continue // The certificate was never generated.
+		}
+		r.SupersededDownloadableFileURL, err = c.S3.GetDownloadablePresignedURL(ctx, r.SupersededFileUploadS3ObjectKey, time.Minute*15)
+		if err != nil {
+			c.Logger.Warn("s3 presign error", slog.Any("error", err))
+		}
+	}
+	return revisions, nil
+}
+
+// GetGradeRevisionCertificateURL returns a link to download the certificate
+// which was superseded by the grade revision.
+func (c *ComicSubmissionControllerImpl) GetGradeRevisionCertificateURL(ctx context.Context, submissionID primitive.ObjectID, revisionID primitive.ObjectID) (string, error) {
+	if _, err := c.getPermittedSubmission(ctx, submissionID); err != nil {
+		return "", err
+	}
+
+	r, err := c.ComicSubmissionStorer.GetGradeRevisionByID(ctx, revisionID)
+	if err != nil {
+		c.Logger.Error("database get grade revision error", slog.Any("error", err))
+		return "", err
+	}
+	if r == nil || r.SubmissionID != submissionID {
+		return "", httperror.NewForNotFoundWithSingleField("revision_id", fmt.Sprintf("grade revision does not exist for ID: %v", revisionID))
+	}
+	if r.SupersededFileUploadS3ObjectKey == "" {
+		return "", httperror.NewForNotFoundWithSingleField("revision_id", "no certificate was issued before this grade revision")
+	}
+
+	url, err := c.S3.GetDownloadablePresignedURL(ctx, r.SupersededFileUploadS3ObjectKey, time.Minute*15)
+	if err != nil {
+		c.Logger.Error("s3 presign error", slog.Any("error", err))
+		return "", err
+	}
+	return url, nil
+}
+
+// getPermittedSubmission returns the submission if the logged in user is
+// allowed to work with it.
+func (c *ComicSubmissionControllerImpl) getPermittedSubmission(ctx context.Context, id primitive.ObjectID) (*s_d.ComicSubmission, error) {
+	m, err := c.ComicSubmissionStorer.GetByID(ctx, id)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if m == nil {
+		return nil, httperror.NewForBadRequestWithSingleField("id", fmt.Sprintf("submission does not exist for ID: %v", id))
+	}
+
+	// Users limited to their organization may only work with its submissions.
+	if err := permission.CheckOrganization(ctx, m.OrganizationID); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// supersededCertificate returns the certificate a re-grade supersedes, which
+// is the last certificate we issued whatever the job of the next certificate
+// is up to. It is empty if no certificate was issued or an earlier re-grade
+// already recorded it, ex: while the job of that re-grade is still pending.
+func (c *ComicSubmissionControllerImpl) supersededCertificate(ctx context.Context, m *s_d.ComicSubmission) (string, error) {
+	if m.FileUploadS3ObjectKey == "" {
+		return "", nil
+	}
+	isSuperseded, err := c.ComicSubmissionStorer.IsFileSupersededByGradeRevision(ctx, m.FileUploadS3ObjectKey)
+	if err != nil {
+		c.Logger.Error("database is file superseded error", slog.Any("error", err))
+		return "", err
+	}
+	if isSuperseded {
+		return "", nil
+	}
+	return m.FileUploadS3ObjectKey, nil
+}
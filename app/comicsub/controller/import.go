@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// MaxImportRows is the most submissions a single spreadsheet may contain.
+const MaxImportRows = 500
+
+// ComicSubmissionImportRowIDO is one row of an imported spreadsheet along with
+// the errors found on it and the submission it created.
+type ComicSubmissionImportRowIDO struct {
+	Row        int                              `json:"row"` // Line number inside the spreadsheet, the header is line 1.
+	Request    *ComicSubmissionCreateRequestIDO `json:"-"`
+	Errors     map[string]string                `json:"errors,omitempty"`
+	Submission *s_d.ComicSubmission             `json:"submission,omitempty"`
+}
+
+// ComicSubmissionImportRequestIDO holds the rows of a spreadsheet which were
+// already mapped and validated by the http handler.
+type ComicSubmissionImportRequestIDO struct {
+	IsDryRun bool
+	Rows     []*ComicSubmissionImportRowIDO
+}
+
+type ComicSubmissionImportResponseIDO struct {
+	IsDryRun     bool                           `json:"is_dry_run"`
+	TotalCount   int                            `json:"total_count"`
+	ValidCount   int                            `json:"valid_count"`
+	InvalidCount int                            `json:"invalid_count"`
+	CreatedCount int                            `json:"created_count"`
+	Rows         []*ComicSubmissionImportRowIDO `json:"rows"`
+}
+
+// Import creates a submission for every valid row of the spreadsheet through
+// `Create` so every row gets its `CPS Registry Number` and certificate. Rows
+// with errors are skipped and reported back; during a dry run nothing is
+// created and only the errors are returned.
+func (c *ComicSubmissionControllerImpl) Import(ctx context.Context, req *ComicSubmissionImportRequestIDO) (*ComicSubmissionImportResponseIDO, error) {
+	if err := permission.Check(ctx, permission.SubmissionCreate); err != nil {
+		return nil, err
+	}
+	organizationID, isScoped := permission.OrganizationScope(ctx)
+	if len(req.Rows) == 0 {
+		return nil, httperror.NewForBadRequestWithSingleField("file", "spreadsheet has no rows")
+	}
+	if len(req.Rows) > MaxImportRows {
+		return nil, httperror.NewForBadRequestWithSingleField("file", fmt.Sprintf("spreadsheet has over %v rows", MaxImportRows))
+	}
+
+	// Check what `Create` would reject for every row before anything is
+	// created so a dry run reports every error.
+	orgExists := make(map[primitive.ObjectID]bool)
+	for _, row := range req.Rows {
+		if len(row.Errors) != 0 {
+			continue
+		}
+		if isScoped {
+			row.Request.OrganizationID = organizationID
+		}
+		if !c.CertificateRenderers.IsSupported(row.Request.ServiceType) {
+			row.addError("service_type", fmt.Sprintf("unsupported service type: %v", row.Request.ServiceType))
+		}
+		exists, ok := orgExists[row.Request.OrganizationID]
+		if !ok {
+			org, err := c.OrganizationStorer.GetByID(ctx, row.Request.OrganizationID)
+			if err != nil {
+				c.Logger.Error("database get by id error", slog.Any("error", err))
+				return nil, err
+			}
+			exists = org != nil
+			orgExists[row.Request.OrganizationID] = exists
+		}
+		if !exists {
+			row.addError("organization_id", "organization does not exist")
+		}
+	}
+
+	res := &ComicSubmissionImportResponseIDO{
+		IsDryRun:   req.IsDryRun,
+		TotalCount: len(req.Rows),
+		Rows:       req.Rows,
+	}
+	for _, row := range req.Rows {
+		if len(row.Errors) == 0 {
+			res.ValidCount++
+		}
+	}
+	res.InvalidCount = res.TotalCount - res.ValidCount
+	if req.IsDryRun {
+		return res, nil
+	}
+
+	for _, row := range req.Rows {
+		if len(row.Errors) != 0 {
+			continue
+		}
+		m, err := c.Create(ctx, row.Request)
+		if err != nil {
+			// Keep going so one failed row does not stop the rest of the import.
+			c.Logger.Error("import create error", slog.Int("row", row.Row), slog.Any("error", err))
+			var httpErr httperror.HTTPError
+			if errors.As(err, &httpErr) && httpErr.Errors != nil {
+				for field, message := range *httpErr.Errors {
+					row.addError(field, message)
+				}
+			} else {
+				row.addError("non_field_error", "submission could not be created")
+			}
+			continue
+		}
+		row.Submission = m
+		res.CreatedCount++
+	}
+	c.Logger.Debug("imported submissions",
+		slog.Int("total", res.TotalCount),
+		slog.Int("created", res.CreatedCount))
+	return res, nil
+}
+
+func (row *ComicSubmissionImportRowIDO) addError(field string, message string) {
+	if row.Errors == nil {
+		row.Errors = make(map[string]string)
+	}
+	row.Errors[field] = message
+}
@@ -4,20 +4,23 @@ import (
 	"context"
 
 	domain "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 )
 
 func (c *ComicSubmissionControllerImpl) ListByFilter(ctx context.Context, f *domain.ComicSubmissionListFilter) (*domain.ComicSubmissionListResult, error) {
+	if err := permission.Check(ctx, permission.SubmissionRead); err != nil {
+		return nil, err
+	}
+
 	// Extract from our session the following data.
-	organizationID := ctx.Value(constants.SessionUserOrganizationID).(primitive.ObjectID)
 	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
-	// Apply filtering based on tenancy if the user is not a system administrator.
-	if userRole != user_d.UserRoleRoot {
+	// Apply filtering based on tenancy if the user is limited to their organization.
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
 		f.OrganizationID = organizationID
 		c.Logger.Debug("applying security policy to filters",
 			slog.Any("organization_id", organizationID),
@@ -45,13 +48,16 @@ func (c *ComicSubmissionControllerImpl) ListByFilter(ctx context.Context, f *dom
 }
 
 func (c *ComicSubmissionControllerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *domain.ComicSubmissionListFilter) ([]*domain.ComicSubmissionAsSelectOption, error) {
+	if err := permission.Check(ctx, permission.SubmissionRead); err != nil {
+		return nil, err
+	}
+
 	// Extract from our session the following data.
-	organizationID := ctx.Value(constants.SessionUserOrganizationID).(primitive.ObjectID)
 	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
-	// Apply filtering based on tenancy if the user is not a system administrator.
-	if userRole != user_d.UserRoleRoot {
+	// Apply filtering based on tenancy if the user is limited to their organization.
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
 		f.OrganizationID = organizationID
 		c.Logger.Debug("applying security policy to filters",
 			slog.Any("organization_id", organizationID),
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/adapter/pdfbuilder"
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// RegeneratePDF schedules the certificate of the submission to be generated
+// again, ex: after a previous attempt failed.
+func (c *ComicSubmissionControllerImpl) RegeneratePDF(ctx context.Context, id primitive.ObjectID) (*s_d.ComicSubmission, error) {
+	m, err := c.ComicSubmissionStorer.GetByID(ctx, id)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if m == nil {
+		c.Logger.Warn("submission does not exist error", slog.Any("id", id))
+		return nil, httperror.NewForBadRequestWithSingleField("id", fmt.Sprintf("submission does not exist for ID: %v", id))
+	}
+
+	// Users limited to their organization may only work with its submissions.
+	if err := permission.CheckOrganization(ctx, m.OrganizationID); err != nil {
+		return nil, err
+	}
+
+	// Do nothing if the certificate is already waiting to be generated.
+	if m.PDFStatus == s_d.PDFStatusPending {
+		return m, nil
+	}
+
+	if err := c.enqueuePDF(ctx, m); err != nil {
+		c.Logger.Error("enqueue pdf job error", slog.Any("error", err))
+		return nil, err
+	}
+	return m, nil
+}
+
+// GenerateCertificatePDF is the job handler which generates the certificate
+// of the submission and uploads it to our remote storage.
+func (c *ComicSubmissionControllerImpl) GenerateCertificatePDF(ctx context.Context, j *job_s.Job) error {
+	m, err := c.ComicSubmissionStorer.GetByID(ctx, j.ReferenceID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if m == nil {
+		c.Logger.Warn("submission does not exist, skipping pdf job", slog.Any("id", j.ReferenceID))
+		return nil
+	}
+
+	// A newer job was scheduled since this one, ex: the submission was
+	// updated twice in a row, so let that job generate the latest values. No
+	// job is scheduled while the certificate awaits sign-offs.
+	if m.PDFJobID != j.ID {
+		c.Logger.Debug("pdf job superseded", slog.Any("job_id", j.ID), slog.Any("latest_job_id", m.PDFJobID))
+		return nil
+	}
+
+	// Every write of the job is conditional on it still being the latest job
+	// as the submission can be re-graded while we generate its certificate.
+	m.PDFStatus = s_d.PDFStatusGenerating
+	ok, err := c.ComicSubmissionStorer.UpdatePDFByID(ctx, m, j.ID)
+	if err != nil {
+		c.Logger.Error("database update pdf error", slog.Any("error", err))
+		return err
+	}
+	if !ok {
+		c.Logger.Debug("pdf job superseded", slog.Any("job_id", j.ID))
+		return nil
+	}
+
+	pdfResponse, err := c.generatePDF(m)
+	if err != nil {
+		return c.pdfJobFailed(ctx, m, j, err)
+	}
+
+	// The next few lines will upload our PDF to our remote storage. Once the
+	// file is saved remotely, we will have a connection to it through a "key"
+	// unique reference to the uploaded file. Every certificate gets its own
+	// key so a re-grade never overwrites the certificate it supersedes.
+	path := fmt.Sprintf("uploads/%v/%v", j.ID.Hex(), pdfResponse.FileName)
+
+	c.Logger.Debug("S3 will upload...",
+		slog.String("path", path))
+
+	if err := c.S3.UploadContent(ctx, path, pdfResponse.Content); err != nil {
+		c.Logger.Error("s3 upload error", slog.Any("error", err))
+		return c.pdfJobFailed(ctx, m, j, err)
+	}
+
+	c.Logger.Debug("S3 uploaded with success",
+		slog.String("path", path))
+
+	// The following will save the S3 key of our file upload into our record
+	// along with the signature of the grade the certificate was issued with.
+	previousKey := m.FileUploadS3ObjectKey
+	m.FileUploadS3ObjectKey = path
+	c.signRegistryRecord(m)
+	m.PDFStatus = s_d.PDFStatusReady
+	m.PDFError = ""
+	m.ModifiedAt = time.Now()
+	ok, err = c.ComicSubmissionStorer.UpdatePDFByID(ctx, m, j.ID)
+	if err != nil {
+		c.Logger.Error("database update pdf error", slog.Any("error", err))
+		return err
+	}
+	if !ok {
+		// The certificate has the values we read before a newer job was
+		// scheduled so it is deleted instead of the current certificate.
+		c.Logger.Debug("pdf job superseded while generating", slog.Any("job_id", j.ID))
+		if err := c.S3.DeleteByKeys(ctx, []string{path}); err != nil {
+			c.Logger.Warn("s3 delete by keys error", slog.Any("error", err))
+		}
+		c.removeLocalFile(pdfResponse.FilePath)
+		return nil
+	}
+
+	for _, hook := range c.CertificateIssuedHooks {
+		hook(ctx, m)
+	}
+
+	// Delete previous record from remote storage unless it is part of the
+	// grade history of the submission.
+	if previousKey != "" && previousKey != path {
+		isSuperseded, err := c.ComicSubmissionStorer.IsFileSupersededByGradeRevision(ctx, previousKey)
+		if err != nil {
+			c.Logger.Warn("database is file superseded error", slog.Any("error", err))
+			isSuperseded = true // Keep the file when in doubt.
+		}
+		if !isSuperseded {
+			if err := c.S3.DeleteByKeys(ctx, []string{previousKey}); err != nil {
+				c.Logger.Warn("s3 delete by keys error", slog.Any("error", err))
+				// Do not return an error, simply continue this function as there might
+				// be a case were the file was removed on the s3 bucket by ourselves
+				// or some other reason.
+			}
+		}
+	}
+
+	c.removeLocalFile(pdfResponse.FilePath)
+	return nil
+}
+
+// removeLocalFile removes the generated file from the directory and doesn't
+// do anything if we have errors.
+func (c *ComicSubmissionControllerImpl) removeLocalFile(filePath string) {
+	if err := os.Remove(filePath); err != nil {
+		c.Logger.Warn("removing local file error", slog.Any("error", err))
+		// Just continue even if we get an error...
+	}
+}
+
+// SendCreatedEmails is the job handler which notifies the staff about a newly
+// created submission.
+func (c *ComicSubmissionControllerImpl) SendCreatedEmails(ctx context.Context, j *job_s.Job) error {
+	m, err := c.ComicSubmissionStorer.GetByID(ctx, j.ReferenceID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if m == nil {
+		c.Logger.Warn("submission does not exist, skipping emails job", slog.Any("id", j.ReferenceID))
+		return nil
+	}
+	return c.sendNewComicSubmissionEmails(m)
+}
+
+// CertificateIssuedHook is notified after the certificate of a submission was
+// generated and uploaded, ex: to roll it up into the packing slip of its order.
+type CertificateIssuedHook func(ctx context.Context, m *s_d.ComicSubmission)
+
+// AddCertificateIssuedHook registers a hook to be notified after every
+// certificate we issue.
+func (c *ComicSubmissionControllerImpl) AddCertificateIssuedHook(hook CertificateIssuedHook) {
+	c.CertificateIssuedHooks = append(c.CertificateIssuedHooks, hook)
+}
+
+func (c *ComicSubmissionControllerImpl) enqueueJob(ctx context.Context, jobType string, referenceID primitive.ObjectID) (*job_s.Job, error) {
+	j := &job_s.Job{
+		Type:        jobType,
+		ReferenceID: referenceID,
+		MaxAttempts: c.Config.JobQueue.MaxAttempts,
+	}
+	if err := c.JobStorer.Create(ctx, j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// enqueuePDF schedules the certificate of the submission to be generated.
+func (c *ComicSubmissionControllerImpl) enqueuePDF(ctx context.Context, m *s_d.ComicSubmission) error {
+	// The certificate can only be issued once enough inspectors signed off.
+	if len(m.InspectorSignOffs) < c.Config.Grading.RequiredSignOffs {
+		m.PDFStatus = s_d.PDFStatusAwaitingSignOffs
+		m.PDFJobID = primitive.NilObjectID
+		m.PDFError = ""
+		return c.ComicSubmissionStorer.UpdatePDFJobByID(ctx, m)
+	}
+
+	j, err := c.enqueueJob(ctx, job_s.TypeGenerateComicSubmissionPDF, m.ID)
+	if err != nil {
+		return err
+	}
+	m.PDFStatus = s_d.PDFStatusPending
+	m.PDFJobID = j.ID
+	m.PDFError = ""
+	return c.ComicSubmissionStorer.UpdatePDFJobByID(ctx, m)
+}
+
+// pdfJobFailed records the error on the submission and returns it so the job
+// gets retried; once out of attempts the certificate is marked as failed.
+func (c *ComicSubmissionControllerImpl) pdfJobFailed(ctx context.Context, m *s_d.ComicSubmission, j *job_s.Job, jobErr error) error {
+	c.Logger.Error("generate pdf error", slog.Any("error", jobErr), slog.Int("attempts", j.Attempts))
+	m.PDFError = jobErr.Error()
+	m.PDFStatus = s_d.PDFStatusPending
+	if j.Attempts >= j.MaxAttempts {
+		m.PDFStatus = s_d.PDFStatusFailed
+	}
+	if _, err := c.ComicSubmissionStorer.UpdatePDFByID(ctx, m, j.ID); err != nil {
+		c.Logger.Error("database update pdf error", slog.Any("error", err))
+	}
+	return jobErr
+}
+
+// generatePDF generates the certificate file with the renderer registered
+// for the `service type` of the submission.
+func (c *ComicSubmissionControllerImpl) generatePDF(m *s_d.ComicSubmission) (*pdfbuilder.PDFBuilderResponseDTO, error) {
+	pdfResponse, err := c.CertificateRenderers.Render(m)
+	if err != nil {
+		c.Logger.Error("generate pdf error", slog.Any("error", err))
+		return nil, err
+	}
+	return pdfResponse, nil
+}
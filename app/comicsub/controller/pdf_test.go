@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/adapter/pdfbuilder"
+	s3_storage "github.com/LuchaComics/cps-backend/adapter/storage/s3"
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	organization_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/provider/signing"
+)
+
+// fakeSubmissionStorer keeps a single submission and mimics the conditional
+// updates of our datastore.
+type fakeSubmissionStorer struct {
+	s_d.ComicSubmissionStorer
+	m         *s_d.ComicSubmission
+	revisions []*s_d.GradeRevision
+}
+
+func (s *fakeSubmissionStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*s_d.ComicSubmission, error) {
+	m := *s.m
+	return &m, nil
+}
+
+func (s *fakeSubmissionStorer) UpdateGradingByID(ctx context.Context, m *s_d.ComicSubmission, previousModifiedAt time.Time) (bool, error) {
+	if !s.m.ModifiedAt.Equal(previousModifiedAt) {
+		return false, nil
+	}
+	saved := *m
+	saved.PDFStatus, saved.PDFJobID, saved.PDFError = s.m.PDFStatus, s.m.PDFJobID, s.m.PDFError
+	saved.FileUploadS3ObjectKey = s.m.FileUploadS3ObjectKey
+	saved.RegistrySignature, saved.RegistrySignatureKeyID, saved.RegistrySignedAt = s.m.RegistrySignature, s.m.RegistrySignatureKeyID, s.m.RegistrySignedAt
+	s.m = &saved
+	return true, nil
+}
+
+func (s *fakeSubmissionStorer) UpdatePDFJobByID(ctx context.Context, m *s_d.ComicSubmission) error {
+	s.m.PDFStatus, s.m.PDFJobID, s.m.PDFError = m.PDFStatus, m.PDFJobID, m.PDFError
+	return nil
+}
+
+func (s *fakeSubmissionStorer) UpdatePDFByID(ctx context.Context, m *s_d.ComicSubmission, jobID primitive.ObjectID) (bool, error) {
+	if s.m.PDFJobID != jobID {
+		return false, nil
+	}
+	s.m.PDFStatus, s.m.PDFError = m.PDFStatus, m.PDFError
+	s.m.FileUploadS3ObjectKey = m.FileUploadS3ObjectKey
+	s.m.RegistrySignature, s.m.RegistrySignatureKeyID, s.m.RegistrySignedAt = m.RegistrySignature, m.RegistrySignatureKeyID, m.RegistrySignedAt
+	return true, nil
+}
+
+func (s *fakeSubmissionStorer) UpdateUserByID(ctx context.Context, m *s_d.ComicSubmission) error {
+	s.m.UserID, s.m.User = m.UserID, m.User
+	return nil
+}
+
+func (s *fakeSubmissionStorer) CreateGradeRevision(ctx context.Context, r *s_d.GradeRevision) error {
+	s.revisions = append(s.revisions, r)
+	return nil
+}
+
+func (s *fakeSubmissionStorer) IsFileSupersededByGradeRevision(ctx context.Context, key string) (bool, error) {
+	for _, r := range s.revisions {
+		if r.SupersededFileUploadS3ObjectKey == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeOrganizationStorer struct {
+	organization_s.OrganizationStorer
+}
+
+func (s *fakeOrganizationStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*organization_s.Organization, error) {
+	return &organization_s.Organization{ID: id, Name: "Test"}, nil
+}
+
+type fakeJobStorer struct {
+	job_s.JobStorer
+	jobs []*job_s.Job
+}
+
+func (s *fakeJobStorer) Create(ctx context.Context, j *job_s.Job) error {
+	j.ID = primitive.NewObjectID()
+	s.jobs = append(s.jobs, j)
+	return nil
+}
+
+type fakeS3 struct {
+	s3_storage.S3Storager
+	deleted []string
+}
+
+func (s *fakeS3) UploadContent(ctx context.Context, objectKey string, content []byte) error {
+	return nil
+}
+
+func (s *fakeS3) DeleteByKeys(ctx context.Context, keys []string) error {
+	s.deleted = append(s.deleted, keys...)
+	return nil
+}
+
+type fakeRenderers struct {
+	pdfbuilder.CertificateRendererRegistry
+}
+
+func (r *fakeRenderers) IsSupported(serviceType int8) bool {
+	return true
+}
+
+func (r *fakeRenderers) Render(m *s_d.ComicSubmission) (*pdfbuilder.PDFBuilderResponseDTO, error) {
+	return &pdfbuilder.PDFBuilderResponseDTO{FileName: "certificate.pdf", FilePath: os.DevNull + "/certificate.pdf"}, nil
+}
+
+type fakeSigning struct {
+	signing.Provider
+}
+
+func (s *fakeSigning) Sign(message []byte) (string, string) {
+	return "signature", "key"
+}
+
+func newTestSubmissionController(m *s_d.ComicSubmission) (*ComicSubmissionControllerImpl, *fakeSubmissionStorer, *fakeJobStorer, *fakeS3) {
+	cfg := &config.Conf{}
+	cfg.JobQueue.MaxAttempts = 3
+	storer := &fakeSubmissionStorer{m: m}
+	jobs := &fakeJobStorer{}
+	s3 := &fakeS3{}
+	c := &ComicSubmissionControllerImpl{
+		Config:                cfg,
+		Logger:                slog.New(slog.NewTextHandler(os.Stderr)),
+		S3:                    s3,
+		Signing:               &fakeSigning{},
+		CertificateRenderers:  &fakeRenderers{},
+		ComicSubmissionStorer: storer,
+		OrganizationStorer:    &fakeOrganizationStorer{},
+		JobStorer:             jobs,
+	}
+	return c, storer, jobs, s3
+}
+
+func TestRegradeKeepsTheIssuedCertificate(t *testing.T) {
+	issuedKey := "uploads/issued/certificate.pdf"
+	m := &s_d.ComicSubmission{
+		ID:                    primitive.NewObjectID(),
+		OrganizationID:        primitive.NewObjectID(),
+		ServiceType:           s_d.ServiceTypePreScreening,
+		GradingScale:          s_d.GradingScaleNumber,
+		OverallNumberGrade:    9.0,
+		PDFStatus:             s_d.PDFStatusReady,
+		PDFJobID:              primitive.NewObjectID(),
+		FileUploadS3ObjectKey: issuedKey,
+		ModifiedAt:            time.Now().Add(-time.Hour),
+	}
+	c, storer, jobs, s3 := newTestSubmissionController(m)
+	ctx := context.WithValue(context.Background(), constants.SessionUserRole, int8(u_d.UserRoleRoot))
+	ctx = context.WithValue(ctx, constants.SessionUserID, primitive.NewObjectID())
+
+	req := &ComicSubmissionUpdateRequestIDO{
+		ID:                 m.ID,
+		OrganizationID:     m.OrganizationID,
+		ServiceType:        m.ServiceType,
+		GradingScale:       m.GradingScale,
+		OverallNumberGrade: m.OverallNumberGrade,
+		SpecialNotes:       "Edited.",
+	}
+
+	// An edit schedules a new certificate with the same grade...
+	if _, err := c.UpdateByID(ctx, req); err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	// ...and is re-graded before the job runs.
+	req.OverallNumberGrade = 8.5
+	req.GradeChangeReason = "Spine tick missed."
+	if _, err := c.UpdateByID(ctx, req); err != nil {
+		t.Fatalf("re-grade: %v", err)
+	}
+	if len(storer.revisions) != 1 || storer.revisions[0].SupersededFileUploadS3ObjectKey != issuedKey {
+		t.Fatalf("expected the re-grade to record %v but got %+v", issuedKey, storer.revisions)
+	}
+
+	// The job of the edit was superseded and the job of the re-grade keeps
+	// the certificate it replaces.
+	if len(jobs.jobs) != 2 {
+		t.Fatalf("expected 2 jobs but got %v", len(jobs.jobs))
+	}
+	for _, j := range jobs.jobs {
+		j.Attempts = 1
+		if err := c.GenerateCertificatePDF(ctx, j); err != nil {
+			t.Fatalf("job %v: %v", j.ID, err)
+		}
+	}
+	if storer.m.PDFStatus != s_d.PDFStatusReady || storer.m.FileUploadS3ObjectKey == issuedKey {
+		t.Errorf("expected a new certificate but got %v %v", storer.m.PDFStatus, storer.m.FileUploadS3ObjectKey)
+	}
+	for _, key := range s3.deleted {
+		if key == issuedKey {
+			t.Errorf("expected %v to be kept", issuedKey)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"encoding/json"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+)
+
+// registryRecordVersion must be incremented, with a new `registryRecord`
+// struct, whenever the signed fields change so old signatures still verify.
+const registryRecordVersion = 1
+
+// registryRecord is the canonical serialization of the grade-relevant fields
+// of a submission which we sign when the certificate is issued. The JSON
+// field order is the order of the struct so do not reorder the fields.
+type registryRecord struct {
+	Version                            int                        `json:"v"`
+	CPSRN                              string                     `json:"cpsrn"`
+	ServiceType                        int8                       `json:"service_type"`
+	SubmissionDate                     string                     `json:"submission_date"`
+	SeriesTitle                        string                     `json:"series_title"`
+	IssueVol                           string                     `json:"issue_vol"`
+	IssueNo                            string                     `json:"issue_no"`
+	IssueCoverYear                     int64                      `json:"issue_cover_year"`
+	IssueCoverMonth                    int8                       `json:"issue_cover_month"`
+	PublisherName                      int8                       `json:"publisher_name"`
+	PublisherNameOther                 string                     `json:"publisher_name_other"`
+	PrimaryLabelDetails                int8                       `json:"primary_label_details"`
+	PrimaryLabelDetailsOther           string                     `json:"primary_label_details_other"`
+	SpecialNotes                       string                     `json:"special_notes"`
+	GradingNotes                       string                     `json:"grading_notes"`
+	CreasesFinding                     string                     `json:"creases_finding"`
+	TearsFinding                       string                     `json:"tears_finding"`
+	MissingPartsFinding                string                     `json:"missing_parts_finding"`
+	StainsFinding                      string                     `json:"stains_finding"`
+	DistortionFinding                  string                     `json:"distortion_finding"`
+	PaperQualityFinding                string                     `json:"paper_quality_finding"`
+	SpineFinding                       string                     `json:"spine_finding"`
+	CoverFinding                       string                     `json:"cover_finding"`
+	ShowsSignsOfTamperingOrRestoration int8                       `json:"shows_signs_of_tampering_or_restoration"`
+	GradingScale                       int8                       `json:"grading_scale"`
+	OverallLetterGrade                 string                     `json:"overall_letter_grade"`
+	OverallNumberGrade                 float64                    `json:"overall_number_grade"`
+	CpsPercentageGrade                 float64                    `json:"cps_percentage_grade"`
+	IsOverallLetterGradeNearMintPlus   bool                       `json:"is_overall_letter_grade_near_mint_plus"`
+	IsCpsIndieMintGem                  bool                       `json:"is_cps_indie_mint_gem"`
+	Signatures                         []*s_d.SubmissionSignature `json:"signatures"`
+}
+
+// registryPayload returns the bytes we sign for the submission.
+func registryPayload(m *s_d.ComicSubmission) []byte {
+	signatures := m.Signatures
+	if signatures == nil {
+		signatures = []*s_d.SubmissionSignature{} // Same bytes for `null` and `[]` in our database.
+	}
+	b, _ := json.Marshal(&registryRecord{
+		Version:                            registryRecordVersion,
+		CPSRN:                              m.CPSRN,
+		ServiceType:                        m.ServiceType,
+		SubmissionDate:                     m.SubmissionDate.UTC().Format("2006-01-02"), // The database does not keep nanoseconds.
+		SeriesTitle:                        m.SeriesTitle,
+		IssueVol:                           m.IssueVol,
+		IssueNo:                            m.IssueNo,
+		IssueCoverYear:                     m.IssueCoverYear,
+		IssueCoverMonth:                    m.IssueCoverMonth,
+		PublisherName:                      m.PublisherName,
+		PublisherNameOther:                 m.PublisherNameOther,
+		PrimaryLabelDetails:                m.PrimaryLabelDetails,
+		PrimaryLabelDetailsOther:           m.PrimaryLabelDetailsOther,
+		SpecialNotes:                       m.SpecialNotes,
+		GradingNotes:                       m.GradingNotes,
+		CreasesFinding:                     m.CreasesFinding,
+		TearsFinding:                       m.TearsFinding,
+		MissingPartsFinding:                m.MissingPartsFinding,
+		StainsFinding:                      m.StainsFinding,
+		DistortionFinding:                  m.DistortionFinding,
+		PaperQualityFinding:                m.PaperQualityFinding,
+		SpineFinding:                       m.SpineFinding,
+		CoverFinding:                       m.CoverFinding,
+		ShowsSignsOfTamperingOrRestoration: m.ShowsSignsOfTamperingOrRestoration,
+		GradingScale:                       m.GradingScale,
+		OverallLetterGrade:                 m.OverallLetterGrade,
+		OverallNumberGrade:                 m.OverallNumberGrade,
+		CpsPercentageGrade:                 m.CpsPercentageGrade,
+		IsOverallLetterGradeNearMintPlus:   m.IsOverallLetterGradeNearMintPlus,
+		IsCpsIndieMintGem:                  m.IsCpsIndieMintGem,
+		Signatures:                         signatures,
+	})
+	return b
+}
+
+// signRegistryRecord signs the grade of the submission with our active key.
+func (c *ComicSubmissionControllerImpl) signRegistryRecord(m *s_d.ComicSubmission) {
+	m.RegistrySignature, m.RegistrySignatureKeyID = c.Signing.Sign(registryPayload(m))
+	m.RegistrySignedAt = time.Now()
+}
+
+// verifyRegistryRecord returns true if the grade of the submission is the
+// same as when the certificate was issued.
+func (c *ComicSubmissionControllerImpl) verifyRegistryRecord(m *s_d.ComicSubmission) bool {
+	if m.RegistrySignature == "" {
+		return false
+	}
+	verified := c.Signing.Verify(m.RegistrySignatureKeyID, registryPayload(m), m.RegistrySignature)
+	if !verified {
+		c.Logger.Warn("registry signature does not verify",
+			slog.String("cpsrn", m.CPSRN),
+			slog.String("key_id", m.RegistrySignatureKeyID))
+	}
+	return verified
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// ComicSubmissionSignOffRequestIDO is the grade one inspector gives the
+// submission; the grade fields are the same as the update request.
+type ComicSubmissionSignOffRequestIDO struct {
+	SubmissionID primitive.ObjectID `bson:"submission_id" json:"submission_id"`
+	Signature    string             `bson:"signature" json:"signature"`
+	s_d.GradeValues
+}
+
+// signOffAttempts is how many times we retry when another inspector signed
+// off at the same time.
+const signOffAttempts = 3
+
+// SignOff records the grade of the logged in inspector and recomputes the
+// consensus of the grading session. Once enough inspectors signed off, the
+// consensus becomes the grade of the submission and the certificate is issued.
+func (c *ComicSubmissionControllerImpl) SignOff(ctx context.Context, req *ComicSubmissionSignOffRequestIDO) (*s_d.ComicSubmission, error) {
+	if err := permission.Check(ctx, permission.SubmissionGrade); err != nil {
+		return nil, err
+	}
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userRole := ctx.Value(constants.SessionUserRole).(int8)
+
+	for attempt := 0; attempt < signOffAttempts; attempt++ {
+		m, err := c.getPermittedSubmission(ctx, req.SubmissionID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Every inspector must grade on the same scale or there is nothing
+		// to compute a consensus with.
+		if m.GradingScale == 0 {
+			m.GradingScale = req.GradingScale
+		}
+		if req.GradingScale != m.GradingScale {
+			return nil, httperror.NewForBadRequestWithSingleField("grading_scale", fmt.Sprintf("submission is graded on scale %v", m.GradingScale))
+		}
+
+		// Inspectors may change their own sign-off but not anyone else's.
+		signOff := &s_d.InspectorSignOff{
+			UserID:      userID,
+			FirstName:   ctx.Value(constants.SessionUserFirstName).(string),
+			LastName:    ctx.Value(constants.SessionUserLastName).(string),
+			CompanyName: ctx.Value(constants.SessionUserOrganizationName).(string),
+			Signature:   req.Signature,
+			SignedAt:    time.Now(),
+			Grade:       &req.GradeValues,
+		}
+		isReplaced := false
+		for i, s := range m.InspectorSignOffs {
+			if s.UserID == userID {
+				m.InspectorSignOffs[i] = signOff
+				isReplaced = true
+			}
+		}
+		if !isReplaced {
+			if len(m.InspectorSignOffs) >= maxInspectorSignOffs {
+				return nil, httperror.NewForBadRequestWithSingleField("message", fmt.Sprintf("submission was already signed off by %v inspectors", maxInspectorSignOffs))
+			}
+			m.InspectorSignOffs = append(m.InspectorSignOffs, signOff)
+		}
+		setInspectorFields(m)
+
+		wasReached := m.GradingConsensus != nil && m.GradingConsensus.IsReached
+		m.GradingConsensus = computeConsensus(c.Config, m.GradingScale, m.GradingNotes, m.InspectorSignOffs)
+
+		oldGrade := s_d.GradeValuesOf(m)
+		isRegraded := false
+		if m.GradingConsensus.IsReached {
+			isRegraded = *oldGrade != *m.GradingConsensus.Grade
+			s_d.SetGradeValues(m, m.GradingConsensus.Grade)
+		}
+
+		previousModifiedAt := m.ModifiedAt
+		m.ModifiedAt = time.Now()
+		m.ModifiedByUserID = userID
+		m.ModifiedByUserRole = userRole
+		ok, err := c.ComicSubmissionStorer.UpdateGradingByID(ctx, m, previousModifiedAt)
+		if err != nil {
+			c.Logger.Error("database update grading by id error", slog.Any("error", err))
+			return nil, err
+		}
+		if !ok {
+			c.Logger.Debug("submission modified during sign off, retrying", slog.Any("id", m.ID), slog.Int("attempt", attempt))
+			continue
+		}
+
+		if len(m.GradingConsensus.Disagreements) > 0 {
+			c.Logger.Warn("inspectors disagree on grade",
+				slog.Any("id", m.ID),
+				slog.Any("disagreements", m.GradingConsensus.Disagreements))
+		}
+
+		// Once a certificate was issued, a change of the consensus is a
+		// re-grade which must be kept in the grade history.
+		if isRegraded && m.FileUploadS3ObjectKey != "" {
+			supersededKey, err := c.supersededCertificate(ctx, m)
+			if err != nil {
+				return nil, err
+			}
+			revision := &s_d.GradeRevision{
+				ID:                              primitive.NewObjectID(),
+				SubmissionID:                    m.ID,
+				OrganizationID:                  m.OrganizationID,
+				CPSRN:                           m.CPSRN,
+				CreatedAt:                       m.ModifiedAt,
+				CreatedByUserID:                 userID,
+				CreatedByUserRole:               userRole,
+				CreatedByName:                   fmt.Sprintf("%v %v", signOff.FirstName, signOff.LastName),
+				Reason:                          fmt.Sprintf("Consensus of %v inspector sign-offs changed.", m.GradingConsensus.SignOffCount),
+				OldValues:                       oldGrade,
+				NewValues:                       m.GradingConsensus.Grade,
+				SupersededFileUploadS3ObjectKey: supersededKey,
+			}
+			if err := c.ComicSubmissionStorer.CreateGradeRevision(ctx, revision); err != nil {
+				c.Logger.Error("database create grade revision error", slog.Any("error", err))
+				return nil, err
+			}
+		}
+
+		// Issue the certificate the moment the consensus is reached and
+		// re-issue it whenever the consensus changes afterwards.
+		if m.GradingConsensus.IsReached && (!wasReached || isRegraded || m.PDFStatus == s_d.PDFStatusAwaitingSignOffs) {
+			if err := c.enqueuePDF(ctx, m); err != nil {
+				c.Logger.Error("enqueue pdf job error", slog.Any("error", err))
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	return nil, httperror.NewForSingleField(http.StatusConflict, "message", "submission is being signed off by another inspector, please try again")
+}
+
+// setInspectorFields copies the sign-offs into the inspector fields of the
+// submission which our certificates and reports use.
+func setInspectorFields(m *s_d.ComicSubmission) {
+	get := func(i int) *s_d.InspectorSignOff {
+		if i < len(m.InspectorSignOffs) {
+			return m.InspectorSignOffs[i]
+		}
+		return &s_d.InspectorSignOff{}
+	}
+
+	first, second, third := get(0), get(1), get(2)
+	m.InspectorSignature = first.Signature
+	m.InspectorDate = first.SignedAt
+	m.InspectorFirstName = first.FirstName
+	m.InspectorLastName = first.LastName
+	m.InspectorCompany = first.CompanyName
+	m.SecondInspectorSignature = second.Signature
+	m.SecondInspectorDate = second.SignedAt
+	m.SecondInspectorFirstName = second.FirstName
+	m.SecondInspectorLastName = second.LastName
+	m.SecondInspectorCompany = second.CompanyName
+	m.ThirdInspectorSignature = third.Signature
+	m.ThirdInspectorDate = third.SignedAt
+	m.ThirdInspectorFirstName = third.FirstName
+	m.ThirdInspectorLastName = third.LastName
+	m.ThirdInspectorCompany = third.CompanyName
+}
@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// ComicSubmissionTransitionRequestIDO moves the submission into a new status.
+type ComicSubmissionTransitionRequestIDO struct {
+	SubmissionID primitive.ObjectID `bson:"submission_id" json:"submission_id"`
+	Status       int8               `bson:"status" json:"status"`
+	Reason       string             `bson:"reason" json:"reason"`
+}
+
+// StatusTransitionHook is notified after a submission moved into a new
+// status, ex: to email the customer, including when it was archived.
+type StatusTransitionHook func(ctx context.Context, m *s_d.ComicSubmission, t *s_d.StatusTransition)
+
+// statusTransitionRule describes the permission needed to move a submission
+// along one edge of our state machine.
+type statusTransitionRule struct {
+	Permission       permission.Permission
+	IsReasonRequired bool
+}
+
+var (
+	gradeTransition    = statusTransitionRule{Permission: permission.SubmissionGrade}
+	regradeTransition  = statusTransitionRule{Permission: permission.SubmissionGrade, IsReasonRequired: true}
+	shipTransition     = statusTransitionRule{Permission: permission.SubmissionShip}
+	holdTransition     = statusTransitionRule{Permission: permission.SubmissionHold, IsReasonRequired: true}
+	resumeTransition   = statusTransitionRule{Permission: permission.SubmissionHold}
+	rejectTransition   = statusTransitionRule{Permission: permission.SubmissionReject, IsReasonRequired: true}
+	completeTransition = statusTransitionRule{Permission: permission.SubmissionUpdate}
+)
+
+// statusTransitions is our submission state machine: the statuses a
+// submission may move into from its current status. Leaving `on hold` is
+// handled separately as the submission resumes where it was put on hold.
+var statusTransitions = map[int8]map[int8]statusTransitionRule{
+	s_d.StatusReceived: {
+		s_d.StatusInGrading: gradeTransition,
+		s_d.StatusOnHold:    holdTransition,
+		s_d.StatusRejected:  rejectTransition,
+	},
+	s_d.StatusInGrading: {
+		s_d.StatusGraded:   gradeTransition,
+		s_d.StatusOnHold:   holdTransition,
+		s_d.StatusRejected: rejectTransition,
+	},
+	s_d.StatusGraded: {
+		s_d.StatusEncapsulated: shipTransition,
+		s_d.StatusInGrading:    regradeTransition,
+		s_d.StatusOnHold:       holdTransition,
+		s_d.StatusRejected:     rejectTransition,
+	},
+	s_d.StatusEncapsulated: {
+		s_d.StatusShipped: shipTransition,
+		s_d.StatusOnHold:  holdTransition,
+	},
+	s_d.StatusShipped: {
+		s_d.StatusCompleted: completeTransition,
+	},
+}
+
+func statusLabel(status int8) string {
+	if label, ok := constants.SubmissionStatuses[status]; ok {
+		return strings.ToLower(label)
+	}
+	return fmt.Sprintf("unknown (%v)", status)
+}
+
+// currentStatus returns the status of the submission inside our state
+// machine; submissions from before the state machine are treated as received.
+func currentStatus(m *s_d.ComicSubmission) int8 {
+	switch m.Status {
+	case 0, s_d.StatusPending, s_d.StatusActive, s_d.StatusError:
+		return s_d.StatusReceived
+	}
+	return m.Status
+}
+
+// statusBeforeHold returns the status the submission was in when it was put
+// on hold.
+func statusBeforeHold(m *s_d.ComicSubmission) int8 {
+	for i := len(m.StatusHistory) - 1; i >= 0; i-- {
+		if t := m.StatusHistory[i]; t.To == s_d.StatusOnHold {
+			return t.From
+		}
+	}
+	return s_d.StatusReceived
+}
+
+// checkTransition returns an error if the logged in user is not allowed to
+// move the submission into the status.
+func checkTransition(ctx context.Context, m *s_d.ComicSubmission, to int8, reason string) error {
+	from := currentStatus(m)
+
+	var rule statusTransitionRule
+	var ok bool
+	if from == s_d.StatusOnHold {
+		// Resume where the submission was put on hold, or reject it.
+		switch to {
+		case statusBeforeHold(m):
+			rule, ok = resumeTransition, true
+		case s_d.StatusRejected:
+			rule, ok = rejectTransition, true
+		}
+	} else {
+		rule, ok = statusTransitions[from][to]
+	}
+	if !ok {
+		return httperror.NewForBadRequestWithSingleField("status", fmt.Sprintf("cannot transition from %v to %v", statusLabel(from), statusLabel(to)))
+	}
+
+	if !permission.IsAllowed(ctx, rule.Permission) {
+		return httperror.NewForForbiddenWithSingleField("message", fmt.Sprintf("you do not have permission to transition to %v", statusLabel(to)))
+	}
+	if rule.IsReasonRequired && strings.TrimSpace(reason) == "" {
+		return httperror.NewForBadRequestWithSingleField("reason", "missing value")
+	}
+
+	// Guards which depend on the grading and the certificate.
+	switch to {
+	case s_d.StatusGraded:
+		if m.GradingScale == 0 || m.PDFStatus == s_d.PDFStatusAwaitingSignOffs {
+			return httperror.NewForBadRequestWithSingleField("status", "submission is waiting on the sign-off of the inspectors")
+		}
+	case s_d.StatusEncapsulated:
+		if m.PDFStatus != s_d.PDFStatusReady {
+			return httperror.NewForBadRequestWithSingleField("status", "certificate must be issued before the submission is encapsulated")
+		}
+	}
+	return nil
+}
+
+// newStatusTransition moves the submission into the status and appends the
+// transition to its history.
+func newStatusTransition(ctx context.Context, m *s_d.ComicSubmission, to int8, reason string) *s_d.StatusTransition {
+	t := &s_d.StatusTransition{
+		From:              m.Status,
+		To:                to,
+		Reason:            strings.TrimSpace(reason),
+		CreatedAt:         time.Now(),
+		CreatedByUserID:   ctx.Value(constants.SessionUserID).(primitive.ObjectID),
+		CreatedByUserRole: ctx.Value(constants.SessionUserRole).(int8),
+		CreatedByName:     ctx.Value(constants.SessionUserName).(string),
+	}
+	m.Status = to
+	m.StatusModifiedAt = t.CreatedAt
+	m.StatusHistory = append(m.StatusHistory, t)
+	m.ModifiedAt = t.CreatedAt
+	m.ModifiedByUserID = t.CreatedByUserID
+	m.ModifiedByUserRole = t.CreatedByUserRole
+	return t
+}
+
+// Transition moves the submission into a new status if our state machine
+// allows the logged in user to do so and notifies the registered hooks.
+func (c *ComicSubmissionControllerImpl) Transition(ctx context.Context, req *ComicSubmissionTransitionRequestIDO) (*s_d.ComicSubmission, error) {
+	m, err := c.getPermittedSubmission(ctx, req.SubmissionID)
+	if err != nil {
+		return nil, err
+	}
+	if m.Status == s_d.StatusArchived {
+		return nil, httperror.NewForBadRequestWithSingleField("status", "submission is archived")
+	}
+
+	if err := checkTransition(ctx, m, req.Status, req.Reason); err != nil {
+		return nil, err
+	}
+
+	previousStatus := m.Status
+	t := newStatusTransition(ctx, m, req.Status, req.Reason)
+	ok, err := c.ComicSubmissionStorer.UpdateStatusByID(ctx, m, previousStatus)
+	if err != nil {
+		c.Logger.Error("database update status by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if !ok {
+		return nil, httperror.NewForSingleField(http.StatusConflict, "status", "submission status was changed by someone else, please try again")
+	}
+
+	c.Logger.Debug("submission transitioned",
+		slog.Any("id", m.ID),
+		slog.String("from", statusLabel(t.From)),
+		slog.String("to", statusLabel(t.To)))
+
+	for _, hook := range c.StatusTransitionHooks {
+		hook(ctx, m, t)
+	}
+	return m, nil
+}
+
+// AddStatusTransitionHook registers a hook to be notified after every
+// transition of a submission.
+func (c *ComicSubmissionControllerImpl) AddStatusTransitionHook(hook StatusTransitionHook) {
+	c.StatusTransitionHooks = append(c.StatusTransitionHooks, hook)
+}
+
+// enqueueStatusChangedEmails is our default transition hook which notifies
+// the organization of the submission in the background.
+func (c *ComicSubmissionControllerImpl) enqueueStatusChangedEmails(ctx context.Context, m *s_d.ComicSubmission, t *s_d.StatusTransition) {
+	if t.To == s_d.StatusArchived {
+		return // Archiving is staff housekeeping; nobody is emailed.
+	}
+	if _, err := c.enqueueJob(ctx, job_s.TypeSendComicSubmissionStatusEmails, m.ID); err != nil {
+		// Do not fail the transition which was already saved.
+		c.Logger.Error("enqueue status emails job error", slog.Any("error", err))
+	}
+}
+
+// SendStatusChangedEmails is the job handler which notifies the retailer
+// staff and the customer about the latest transition of a submission.
+func (c *ComicSubmissionControllerImpl) SendStatusChangedEmails(ctx context.Context, j *job_s.Job) error {
+	m, err := c.ComicSubmissionStorer.GetByID(ctx, j.ReferenceID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if m == nil || len(m.StatusHistory) == 0 {
+		c.Logger.Warn("submission does not exist, skipping status emails job", slog.Any("id", j.ReferenceID))
+		return nil
+	}
+	return c.sendStatusChangedEmails(ctx, m, m.StatusHistory[len(m.StatusHistory)-1])
+}
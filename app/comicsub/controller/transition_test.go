@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+)
+
+func withRole(role int8) context.Context {
+	return context.WithValue(context.Background(), constants.SessionUserRole, role)
+}
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        *s_d.ComicSubmission
+		to       int8
+		role     int8
+		reason   string
+		expected bool
+	}{
+		{"legacy status is received", &s_d.ComicSubmission{Status: s_d.StatusPending}, s_d.StatusInGrading, u_d.UserRoleRoot, "", true},
+		{"retailer cannot grade", &s_d.ComicSubmission{Status: s_d.StatusReceived}, s_d.StatusInGrading, u_d.UserRoleRetailer, "", false},
+		{"cannot skip grading", &s_d.ComicSubmission{Status: s_d.StatusReceived}, s_d.StatusShipped, u_d.UserRoleRoot, "", false},
+		{"graded needs a grade", &s_d.ComicSubmission{Status: s_d.StatusInGrading}, s_d.StatusGraded, u_d.UserRoleRoot, "", false},
+		{"graded", &s_d.ComicSubmission{Status: s_d.StatusInGrading, GradingScale: s_d.GradingScaleNumber, PDFStatus: s_d.PDFStatusReady}, s_d.StatusGraded, u_d.UserRoleRoot, "", true},
+		{"graded awaits sign-offs", &s_d.ComicSubmission{Status: s_d.StatusInGrading, GradingScale: s_d.GradingScaleNumber, PDFStatus: s_d.PDFStatusAwaitingSignOffs}, s_d.StatusGraded, u_d.UserRoleRoot, "", false},
+		{"encapsulated needs certificate", &s_d.ComicSubmission{Status: s_d.StatusGraded, PDFStatus: s_d.PDFStatusFailed}, s_d.StatusEncapsulated, u_d.UserRoleRoot, "", false},
+		{"reject needs reason", &s_d.ComicSubmission{Status: s_d.StatusGraded}, s_d.StatusRejected, u_d.UserRoleRoot, " ", false},
+		{"reject", &s_d.ComicSubmission{Status: s_d.StatusGraded}, s_d.StatusRejected, u_d.UserRoleRoot, "Counterfeit.", true},
+		{"retailer completes", &s_d.ComicSubmission{Status: s_d.StatusShipped}, s_d.StatusCompleted, u_d.UserRoleRetailer, "", true},
+		{"grader grades", &s_d.ComicSubmission{Status: s_d.StatusReceived}, s_d.StatusInGrading, u_d.UserRoleGrader, "", true},
+		{"grader cannot ship", &s_d.ComicSubmission{Status: s_d.StatusEncapsulated}, s_d.StatusShipped, u_d.UserRoleGrader, "", false},
+		{"front desk ships", &s_d.ComicSubmission{Status: s_d.StatusEncapsulated}, s_d.StatusShipped, u_d.UserRoleFrontDesk, "", true},
+		{"front desk cannot grade", &s_d.ComicSubmission{Status: s_d.StatusReceived}, s_d.StatusInGrading, u_d.UserRoleFrontDesk, "", false},
+		{"customer cannot complete", &s_d.ComicSubmission{Status: s_d.StatusShipped}, s_d.StatusCompleted, u_d.UserRoleCustomer, "", false},
+		{"completed is final", &s_d.ComicSubmission{Status: s_d.StatusCompleted}, s_d.StatusShipped, u_d.UserRoleRoot, "", false},
+		{"rejected is final", &s_d.ComicSubmission{Status: s_d.StatusRejected}, s_d.StatusReceived, u_d.UserRoleRoot, "", false},
+	}
+	for _, test := range tests {
+		if err := checkTransition(withRole(test.role), test.m, test.to, test.reason); (err == nil) != test.expected {
+			t.Errorf("%v: expected allowed to be %v but got %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestCheckTransitionResumesFromHold(t *testing.T) {
+	m := &s_d.ComicSubmission{
+		Status: s_d.StatusOnHold,
+		StatusHistory: []*s_d.StatusTransition{
+			{From: 0, To: s_d.StatusReceived},
+			{From: s_d.StatusReceived, To: s_d.StatusInGrading},
+			{From: s_d.StatusInGrading, To: s_d.StatusOnHold, Reason: "Awaiting payment."},
+		},
+	}
+	if err := checkTransition(withRole(u_d.UserRoleRetailer), m, s_d.StatusInGrading, ""); err != nil {
+		t.Errorf("expected to resume in grading but got %v", err)
+	}
+	if err := checkTransition(withRole(u_d.UserRoleRoot), m, s_d.StatusReceived, ""); err == nil {
+		t.Error("expected to only resume where the submission was put on hold")
+	}
+	if err := checkTransition(withRole(u_d.UserRoleRoot), m, s_d.StatusRejected, "Never paid."); err != nil {
+		t.Errorf("expected to reject but got %v", err)
+	}
+}
+
+func TestCheckTransitionAPIKeyScopes(t *testing.T) {
+	m := &s_d.ComicSubmission{Status: s_d.StatusShipped}
+	ctx := context.WithValue(withRole(u_d.UserRoleRetailer), constants.SessionAPIKeyScopes, []permission.Permission{permission.SubmissionRead})
+	if err := checkTransition(ctx, m, s_d.StatusCompleted, ""); err == nil {
+		t.Error("expected a read-only api key to not complete the submission")
+	}
+	ctx = context.WithValue(withRole(u_d.UserRoleRetailer), constants.SessionAPIKeyScopes, []permission.Permission{permission.SubmissionUpdate})
+	if err := checkTransition(ctx, m, s_d.StatusCompleted, ""); err != nil {
+		t.Errorf("expected an api key with the scope to complete the submission but got %v", err)
+	}
+}
@@ -2,19 +2,18 @@ package controller
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	go_os "os"
+	"net/http"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
-	"github.com/LuchaComics/cps-backend/adapter/pdfbuilder"
 	domain "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
 	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
 	submission_s "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
-	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
@@ -52,8 +51,8 @@ type ComicSubmissionUpdateRequestIDO struct {
 	CpsPercentageGrade                 float64                       `bson:"cps_percentage_grade" json:"cps_percentage_grade"`
 	IsOverallLetterGradeNearMintPlus   bool                          `bson:"is_overall_letter_grade_near_mint_plus" json:"is_overall_letter_grade_near_mint_plus"`
 	CollectibleType                    int8                          `bson:"collectible_type" json:"collectible_type"`
-	Status                             int8                          `bson:"status" json:"status"`
 	Signatures                         []*domain.SubmissionSignature `bson:"signatures" json:"signatures,omitempty"`
+	GradeChangeReason                  string                        `bson:"grade_change_reason" json:"grade_change_reason"` // Required when the grade changes.
 }
 
 func comicSubmissionFromModify(req *ComicSubmissionUpdateRequestIDO) *s_d.ComicSubmission {
@@ -90,19 +89,23 @@ func comicSubmissionFromModify(req *ComicSubmissionUpdateRequestIDO) *s_d.ComicS
 		CpsPercentageGrade:                 req.CpsPercentageGrade,
 		IsOverallLetterGradeNearMintPlus:   req.IsOverallLetterGradeNearMintPlus,
 		CollectibleType:                    req.CollectibleType,
-		Status:                             req.Status,
 		Signatures:                         req.Signatures,
 	}
 }
 
 func (c *ComicSubmissionControllerImpl) UpdateByID(ctx context.Context, req *ComicSubmissionUpdateRequestIDO) (*domain.ComicSubmission, error) {
-	// DEVELOPERS NOTE:
-	// Every submission creation is dependent on the `role` of the logged in
-	// user in our system so we need to extract it right away.
+	if err := permission.Check(ctx, permission.SubmissionUpdate); err != nil {
+		return nil, err
+	}
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	ns := comicSubmissionFromModify(req) // Convert into our data-structure.
 
+	// Reject service types we cannot generate a certificate for.
+	if !c.CertificateRenderers.IsSupported(ns.ServiceType) {
+		return nil, httperror.NewForBadRequestWithSingleField("service_type", fmt.Sprintf("unsupported service type: %v", ns.ServiceType))
+	}
+
 	//
 	// Fetch submission.
 	//
@@ -118,23 +121,33 @@ func (c *ComicSubmissionControllerImpl) UpdateByID(ctx context.Context, req *Com
 		return nil, httperror.NewForBadRequestWithSingleField("id", fmt.Sprintf("submission does not exist for ID: %v", req.ID))
 	}
 
+	// Every change to the grade must be explained since it supersedes the
+	// certificate we issued to the customer.
+	oldGrade, newGrade := s_d.GradeValuesOf(os), s_d.GradeValuesOf(ns)
+	isRegraded := *oldGrade != *newGrade
+	if isRegraded {
+		// Only graders may change the grade, not everyone who may edit.
+		if err := permission.Check(ctx, permission.SubmissionGrade); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(req.GradeChangeReason) == "" {
+			return nil, httperror.NewForBadRequestWithSingleField("grade_change_reason", "missing value")
+		}
+	}
+
 	//
 	// Set organization.
 	//
 
 	// DEVELOPERS NOTE:
-	// Every submission creation is dependent on the `role` of the logged in
-	// user in our system; however, the root administrator has the ability to
-	// assign whatever organization you want.
-	switch userRole {
-	case u_d.UserRoleRetailer:
-		c.Logger.Debug("retailer assigning their organization")
-		os.OrganizationID = ctx.Value(constants.SessionUserOrganizationID).(primitive.ObjectID)
-	case u_d.UserRoleRoot:
-		c.Logger.Debug("admin picking custom organization")
-	default:
-		c.Logger.Error("unsupported role", slog.Any("role", userRole))
-		return nil, fmt.Errorf("unsupported role via: %v", userRole)
+	// Users limited to their organization may only update its submissions
+	// while our staff has the ability to assign whatever organization you want.
+	if err := permission.CheckOrganization(ctx, os.OrganizationID); err != nil {
+		return nil, err
+	}
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		c.Logger.Debug("assigning the organization of the user")
+		ns.OrganizationID = organizationID
 	}
 
 	// Lookup the organization.
@@ -155,8 +168,8 @@ func (c *ComicSubmissionControllerImpl) UpdateByID(ctx context.Context, req *Com
 	//
 
 	// Modify our original submission.
+	previousModifiedAt := os.ModifiedAt
 	os.ModifiedAt = time.Now()
-	// os.Status = ns.Status //BUGFIX - TODO WITH ROLES
 	os.ServiceType = ns.ServiceType
 	os.SubmissionDate = ns.SubmissionDate
 	os.Item = fmt.Sprintf("%v, %v, %v", ns.SeriesTitle, ns.IssueVol, ns.IssueNo)
@@ -194,346 +207,60 @@ func (c *ComicSubmissionControllerImpl) UpdateByID(ctx context.Context, req *Com
 	os.Item = fmt.Sprintf("%v, %v, %v", ns.SeriesTitle, ns.IssueVol, ns.IssueNo)
 	os.Signatures = ns.Signatures
 
-	// Save to the database the modified submission.
-	if err := c.ComicSubmissionStorer.UpdateByID(ctx, os); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+	// Save to the database the modified submission unless someone else, ex:
+	// another re-grade, modified it since we read it.
+	ok, err := c.ComicSubmissionStorer.UpdateGradingByID(ctx, os, previousModifiedAt)
+	if err != nil {
+		c.Logger.Error("database update grading by id error", slog.Any("error", err))
 		return nil, err
 	}
-
-	//
-	// Signatures - Update `special notes` for the PDF.
-	//
-
-	var modifiedSpecialNotes = ns.SpecialNotes
-	if len(ns.Signatures) > 0 {
-		var str string
-		for _, s := range ns.Signatures {
-			str += fmt.Sprintf("Signature of %v %v authenticated by CPS.", s.Role, s.Name)
-		}
-		os.SpecialNotes = fmt.Sprintf("%v %v", str, ns.SpecialNotes)
+	if !ok {
+		c.Logger.Debug("submission modified during update", slog.Any("id", os.ID))
+		return nil, httperror.NewForSingleField(http.StatusConflict, "message", "submission was modified by someone else, please reload and try again")
 	}
 
-	//
-	// Delete pdf file from s3
-	//
-
-	c.Logger.Debug("S3 will delete previous upload",
-		slog.String("path", os.FileUploadS3ObjectKey))
-
-	// Delete previous record from remote storage.
-	if err := c.S3.DeleteByKeys(ctx, []string{os.FileUploadS3ObjectKey}); err != nil {
-		c.Logger.Warn("s3 delete by keys error", slog.Any("error", err))
-		// Do not return an error, simply continue this function as there might
-		// be a case were the file was removed on the s3 bucket by ourselves
-		// or some other reason.
-	}
-
-	//
-	// Create new PDF file.
-	//
-
-	// Look up the publisher names and get the correct display name or get the other.
-	var publisherNameDisplay string = constants.SubmissionPublisherNames[ns.PublisherName]
-	if ns.PublisherName == constants.SubmissionPublisherNameOther {
-		publisherNameDisplay = ns.PublisherNameOther
-	}
-
-	pdfResponse := &pdfbuilder.PDFBuilderResponseDTO{}
-
-	// The next following lines of code will create the PDF file gnerator
-	// request to be submitted into our PDF file generator to generate the data.
-	switch os.ServiceType {
-	case s_d.ServiceTypePreScreening:
-		c.Logger.Debug("beginning to generate `pre-screening` pdf")
-		r := &pdfbuilder.CBFFBuilderRequestDTO{
-			CPSRN:                              os.CPSRN,
-			Filename:                           fmt.Sprintf("%v.pdf", os.ID.Hex()),
-			SubmissionDate:                     time.Now(),
-			SeriesTitle:                        os.SeriesTitle,
-			IssueVol:                           os.IssueVol,
-			IssueNo:                            os.IssueNo,
-			IssueCoverYear:                     os.IssueCoverYear,
-			IssueCoverMonth:                    os.IssueCoverMonth,
-			PublisherName:                      publisherNameDisplay,
-			SpecialNotes:                       modifiedSpecialNotes,
-			GradingNotes:                       os.GradingNotes,
-			CreasesFinding:                     os.CreasesFinding,
-			TearsFinding:                       os.TearsFinding,
-			MissingPartsFinding:                os.MissingPartsFinding,
-			StainsFinding:                      os.StainsFinding,
-			DistortionFinding:                  os.DistortionFinding,
-			PaperQualityFinding:                os.PaperQualityFinding,
-			SpineFinding:                       os.SpineFinding,
-			CoverFinding:                       os.CoverFinding,
-			ShowsSignsOfTamperingOrRestoration: os.ShowsSignsOfTamperingOrRestoration == 1,
-			GradingScale:                       os.GradingScale,
-			OverallLetterGrade:                 os.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus:   os.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:                 os.OverallNumberGrade,
-			CpsPercentageGrade:                 os.CpsPercentageGrade,
-			UserFirstName:                      os.UserFirstName,
-			UserLastName:                       os.UserLastName,
-			UserOrganizationName:               os.OrganizationName,
-			Signatures:                         os.Signatures,
-			PrimaryLabelDetails:                os.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:           os.PrimaryLabelDetailsOther,
-		}
-		pdfResponse, err = c.CBFFBuilder.GeneratePDF(r)
+	// Record the re-grade now that we know the values it replaced were the
+	// latest ones.
+	if isRegraded {
+		supersededKey, err := c.supersededCertificate(ctx, os)
 		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
 			return nil, err
 		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
+		revision := &s_d.GradeRevision{
+			ID:                              primitive.NewObjectID(),
+			SubmissionID:                    os.ID,
+			OrganizationID:                  os.OrganizationID,
+			CPSRN:                           os.CPSRN,
+			CreatedAt:                       os.ModifiedAt,
+			CreatedByUserID:                 ctx.Value(constants.SessionUserID).(primitive.ObjectID),
+			CreatedByUserRole:               userRole,
+			CreatedByName:                   fmt.Sprintf("%v %v", ctx.Value(constants.SessionUserFirstName), ctx.Value(constants.SessionUserLastName)),
+			Reason:                          strings.TrimSpace(req.GradeChangeReason),
+			OldValues:                       oldGrade,
+			NewValues:                       newGrade,
+			SupersededFileUploadS3ObjectKey: supersededKey,
 		}
-		c.Logger.Debug("finished generate `pre-screening` pdf")
-	case s_d.ServiceTypePedigree:
-		c.Logger.Debug("beginning to generate `pedigree` pdf")
-		r := &pdfbuilder.PCBuilderRequestDTO{
-			CPSRN:                              os.CPSRN,
-			Filename:                           fmt.Sprintf("%v.pdf", os.ID.Hex()),
-			SubmissionDate:                     time.Now(),
-			SeriesTitle:                        os.SeriesTitle,
-			IssueVol:                           os.IssueVol,
-			IssueNo:                            os.IssueNo,
-			IssueCoverYear:                     os.IssueCoverYear,
-			IssueCoverMonth:                    os.IssueCoverMonth,
-			PublisherName:                      publisherNameDisplay,
-			SpecialNotes:                       modifiedSpecialNotes,
-			GradingNotes:                       os.GradingNotes,
-			CreasesFinding:                     os.CreasesFinding,
-			TearsFinding:                       os.TearsFinding,
-			MissingPartsFinding:                os.MissingPartsFinding,
-			StainsFinding:                      os.StainsFinding,
-			DistortionFinding:                  os.DistortionFinding,
-			PaperQualityFinding:                os.PaperQualityFinding,
-			SpineFinding:                       os.SpineFinding,
-			CoverFinding:                       os.CoverFinding,
-			ShowsSignsOfTamperingOrRestoration: os.ShowsSignsOfTamperingOrRestoration == 1,
-			GradingScale:                       os.GradingScale,
-			OverallLetterGrade:                 os.OverallLetterGrade,
-			OverallNumberGrade:                 os.OverallNumberGrade,
-			CpsPercentageGrade:                 os.CpsPercentageGrade,
-			UserFirstName:                      os.UserFirstName,
-			UserLastName:                       os.UserLastName,
-			UserOrganizationName:               os.OrganizationName,
-			Signatures:                         os.Signatures,
-			PrimaryLabelDetails:                os.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:           os.PrimaryLabelDetailsOther,
-		}
-		pdfResponse, err = c.PCBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `pedigree` pdf")
-	case s_d.ServiceTypeCPSCapsule:
-		c.Logger.Debug("beginning to generate `cc` pdf")
-		r := &pdfbuilder.CCBuilderRequestDTO{
-			CPSRN:                            os.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", os.ID.Hex()),
-			SeriesTitle:                      os.SeriesTitle,
-			IssueVol:                         os.IssueVol,
-			IssueNo:                          os.IssueNo,
-			IssueCoverYear:                   os.IssueCoverYear,
-			IssueCoverMonth:                  os.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     modifiedSpecialNotes,
-			PrimaryLabelDetails:              os.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         os.PrimaryLabelDetailsOther,
-			GradingScale:                     os.GradingScale,
-			OverallLetterGrade:               os.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: os.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               os.OverallNumberGrade,
-			CpsPercentageGrade:               os.CpsPercentageGrade,
-			UserFirstName:                    os.UserFirstName,
-			UserLastName:                     os.UserLastName,
-			UserOrganizationName:             os.OrganizationName,
-			Signatures:                       os.Signatures,
-		}
-		pdfResponse, err = c.CCBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
+		if err := c.ComicSubmissionStorer.CreateGradeRevision(ctx, revision); err != nil {
+			c.Logger.Error("database create grade revision error", slog.Any("error", err))
 			return nil, err
 		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `cc` pdf")
-	case s_d.ServiceTypeCPSCapsuleSignatureCollection:
-		c.Logger.Debug("beginning to generate `ccsc` pdf")
-		r := &pdfbuilder.CCSCBuilderRequestDTO{
-			CPSRN:                            os.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", os.ID.Hex()),
-			SeriesTitle:                      os.SeriesTitle,
-			IssueVol:                         os.IssueVol,
-			IssueNo:                          os.IssueNo,
-			IssueCoverYear:                   os.IssueCoverYear,
-			IssueCoverMonth:                  os.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     modifiedSpecialNotes,
-			PrimaryLabelDetails:              os.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         os.PrimaryLabelDetailsOther,
-			GradingScale:                     os.GradingScale,
-			OverallLetterGrade:               os.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: os.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               os.OverallNumberGrade,
-			CpsPercentageGrade:               os.CpsPercentageGrade,
-			UserFirstName:                    os.UserFirstName,
-			UserLastName:                     os.UserLastName,
-			UserOrganizationName:             os.OrganizationName,
-			Signatures:                       os.Signatures,
-		}
-		pdfResponse, err = c.CCSCBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `ccsc` pdf")
-	case s_d.ServiceTypeCPSCapsuleIndieMintGem:
-		c.Logger.Debug("beginning to generate `ccimg` pdf")
-
-		// // FOR TESTING PURPOSES ONLY.
-		r := &pdfbuilder.CCIMGBuilderRequestDTO{
-			CPSRN:                            os.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", os.ID.Hex()),
-			SeriesTitle:                      os.SeriesTitle,
-			IssueVol:                         os.IssueVol,
-			IssueNo:                          os.IssueNo,
-			IssueCoverYear:                   os.IssueCoverYear,
-			IssueCoverMonth:                  os.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     modifiedSpecialNotes,
-			PrimaryLabelDetails:              os.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         os.PrimaryLabelDetailsOther,
-			GradingScale:                     os.GradingScale,
-			OverallLetterGrade:               os.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: os.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               os.OverallNumberGrade,
-			CpsPercentageGrade:               os.CpsPercentageGrade,
-			UserFirstName:                    os.UserFirstName,
-			UserLastName:                     os.UserLastName,
-			UserOrganizationName:             os.OrganizationName,
-			Signatures:                       os.Signatures,
-		}
-		pdfResponse, err = c.CCIMGBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `ccimg` pdf")
-	case s_d.ServiceTypeCPSCapsuleYouGrade:
-		c.Logger.Debug("beginning to generate `ccug` pdf")
-
-		// // FOR TESTING PURPOSES ONLY.
-		r := &pdfbuilder.CCUGBuilderRequestDTO{
-			CPSRN:                            os.CPSRN,
-			Filename:                         fmt.Sprintf("%v.pdf", os.ID.Hex()),
-			SeriesTitle:                      os.SeriesTitle,
-			IssueVol:                         os.IssueVol,
-			IssueNo:                          os.IssueNo,
-			IssueCoverYear:                   os.IssueCoverYear,
-			IssueCoverMonth:                  os.IssueCoverMonth,
-			PublisherName:                    publisherNameDisplay,
-			SpecialNotes:                     modifiedSpecialNotes,
-			PrimaryLabelDetails:              os.PrimaryLabelDetails,
-			PrimaryLabelDetailsOther:         os.PrimaryLabelDetailsOther,
-			GradingScale:                     os.GradingScale,
-			OverallLetterGrade:               os.OverallLetterGrade,
-			IsOverallLetterGradeNearMintPlus: os.IsOverallLetterGradeNearMintPlus,
-			OverallNumberGrade:               os.OverallNumberGrade,
-			CpsPercentageGrade:               os.CpsPercentageGrade,
-			UserFirstName:                    os.UserFirstName,
-			UserLastName:                     os.UserLastName,
-			UserOrganizationName:             os.OrganizationName,
-			Signatures:                       os.Signatures,
-		}
-		pdfResponse, err = c.CCUGBuilder.GeneratePDF(r)
-		if err != nil {
-			c.Logger.Error("generate pdf error", slog.Any("error", err))
-			return nil, err
-		}
-		if pdfResponse == nil {
-			c.Logger.Error("generate pdf error does not return a response")
-			return nil, errors.New("no response from pdf generator")
-		}
-		c.Logger.Debug("finished generate `ccug` pdf")
-	default:
-		panic("UNSUPPORTED")
-	}
-
-	// The next few lines will upload our PDF to our remote storage. Once the
-	// file is saved remotely, we will have a connection to it through a "key"
-	// unique reference to the uploaded file.
-	path := fmt.Sprintf("uploads/%v", pdfResponse.FileName)
-
-	c.Logger.Debug("S3 will upload...",
-		slog.String("path", path),
-		slog.String("filename", pdfResponse.FileName))
-
-	err = c.S3.UploadContent(ctx, path, pdfResponse.Content)
-	if err != nil {
-		c.Logger.Error("s3 upload error", slog.Any("error", err))
-		return nil, err
-	}
-
-	c.Logger.Debug("S3 uploaded with success",
-		slog.String("path", path))
-
-	//
-	// Update record with PDF file information with record.
-	//
-
-	// The following will save the S3 key of our file upload into our record.
-	os.FileUploadS3ObjectKey = path
-	os.ModifiedAt = time.Now()
-
-	if err := c.ComicSubmissionStorer.UpdateByID(ctx, os); err != nil {
-		c.Logger.Error("database update error", slog.Any("error", err))
-		return nil, err
 	}
 
-	// The following will generate a pre-signed URL so user can download the file.
-	downloadableURL, err := c.S3.GetDownloadablePresignedURL(ctx, os.FileUploadS3ObjectKey, time.Minute*15)
-	if err != nil {
-		c.Logger.Error("s3 presign error", slog.Any("error", err))
+	// Regenerate the certificate in the background with the new values.
+	if err := c.enqueuePDF(ctx, os); err != nil {
+		c.Logger.Error("enqueue pdf job error", slog.Any("error", err))
 		return nil, err
 	}
-	os.FileUploadDownloadableFileURL = downloadableURL
-
-	// Removing local file from the directory and don't do anything if we have errors.
-	if err := go_os.Remove(pdfResponse.FilePath); err != nil {
-		c.Logger.Warn("removing local file error", slog.Any("error", err))
-		// Just continue even if we get an error...
-	}
 
 	return os, nil
 }
 
 func (c *ComicSubmissionControllerImpl) CreateComment(ctx context.Context, submissionID primitive.ObjectID, content string) (*submission_s.ComicSubmission, error) {
 	// Fetch the original submission.
-	s, err := c.ComicSubmissionStorer.GetByID(ctx, submissionID)
+	s, err := c.getPermittedSubmission(ctx, submissionID)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
 		return nil, err
 	}
-	if s == nil {
-		return nil, nil
-	}
 
 	// Create our comment.
 	comment := &submission_s.SubmissionComment{
@@ -553,9 +280,9 @@ func (c *ComicSubmissionControllerImpl) CreateComment(ctx context.Context, submi
 	s.ModifiedAt = time.Now()
 	s.Comments = append(s.Comments, comment)
 
-	// Save to the database the modified submission.
-	if err := c.ComicSubmissionStorer.UpdateByID(ctx, s); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+	// Save to the database the comment only.
+	if err := c.ComicSubmissionStorer.CreateCommentByID(ctx, s, comment); err != nil {
+		c.Logger.Error("database create comment by id error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -564,33 +291,31 @@ func (c *ComicSubmissionControllerImpl) CreateComment(ctx context.Context, submi
 
 func (c *ComicSubmissionControllerImpl) SetUser(ctx context.Context, submissionID primitive.ObjectID, userID primitive.ObjectID) (*submission_s.ComicSubmission, error) {
 	// Fetch the original submission.
-	os, err := c.ComicSubmissionStorer.GetByID(ctx, submissionID)
+	os, err := c.getPermittedSubmission(ctx, submissionID)
 	if err != nil {
-		c.Logger.Error("database get by id error", slog.Any("error", err))
 		return nil, err
 	}
-	if os == nil {
-		return nil, nil
-	}
 
-	// Fetch the original submission.
+	// Fetch the customer, who must belong to the organization of the
+	// submission.
 	cust, err := c.UserStorer.GetByID(ctx, userID)
 	if err != nil {
 		c.Logger.Error("database get by id error", slog.Any("error", err))
 		return nil, err
 	}
-	if os == nil {
-		return nil, nil
+	if cust == nil || cust.OrganizationID != os.OrganizationID {
+		return nil, httperror.NewForBadRequestWithSingleField("user_id", fmt.Sprintf("user does not exist for ID: %v", userID))
 	}
 
 	// Modify our original submission.
+	os.ModifiedByUserID = ctx.Value(constants.SessionUserID).(primitive.ObjectID)
 	os.ModifiedAt = time.Now()
 	os.UserID = userID
 	os.User = userToSubmissionUserCopy(cust)
 
-	// Save to the database the modified submission.
-	if err := c.ComicSubmissionStorer.UpdateByID(ctx, os); err != nil {
-		c.Logger.Error("database update by id error", slog.Any("error", err))
+	// Save to the database the customer only.
+	if err := c.ComicSubmissionStorer.UpdateUserByID(ctx, os); err != nil {
+		c.Logger.Error("database update user by id error", slog.Any("error", err))
 		return nil, err
 	}
 
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+)
+
+type fakeUserStorer struct {
+	u_d.UserStorer
+	users []*u_d.User
+}
+
+func (s *fakeUserStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*u_d.User, error) {
+	for _, u := range s.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestSetUserKeepsToTheOrganization(t *testing.T) {
+	orgID, otherOrgID := primitive.NewObjectID(), primitive.NewObjectID()
+	m := &s_d.ComicSubmission{ID: primitive.NewObjectID(), OrganizationID: orgID}
+	customer := &u_d.User{ID: primitive.NewObjectID(), OrganizationID: orgID, Role: u_d.UserRoleCustomer}
+	stranger := &u_d.User{ID: primitive.NewObjectID(), OrganizationID: otherOrgID, Role: u_d.UserRoleCustomer}
+	c, storer, _, _ := newTestSubmissionController(m)
+	c.UserStorer = &fakeUserStorer{users: []*u_d.User{customer, stranger}}
+
+	ctx := context.WithValue(context.Background(), constants.SessionUserRole, int8(u_d.UserRoleRetailer))
+	ctx = context.WithValue(ctx, constants.SessionUserID, primitive.NewObjectID())
+	ctx = context.WithValue(ctx, constants.SessionUserOrganizationID, orgID)
+
+	// A customer of another organization cannot be given our submission...
+	if _, err := c.SetUser(ctx, m.ID, stranger.ID); err == nil {
+		t.Error("expected a user of another organization to be refused")
+	}
+	// ...nor can another organization give itself our customer.
+	otherCtx := context.WithValue(ctx, constants.SessionUserOrganizationID, otherOrgID)
+	if _, err := c.SetUser(otherCtx, m.ID, customer.ID); err == nil {
+		t.Error("expected the submission of another organization to be refused")
+	}
+	if !storer.m.UserID.IsZero() {
+		t.Fatalf("expected no user but got %v", storer.m.UserID)
+	}
+
+	if _, err := c.SetUser(ctx, m.ID, customer.ID); err != nil {
+		t.Fatal(err)
+	}
+	if storer.m.UserID != customer.ID {
+		t.Errorf("expected user %v but got %v", customer.ID, storer.m.UserID)
+	}
+}
+
+func TestRegradeNeedsTheGradePermission(t *testing.T) {
+	m := &s_d.ComicSubmission{
+		ID:                 primitive.NewObjectID(),
+		OrganizationID:     primitive.NewObjectID(),
+		ServiceType:        s_d.ServiceTypePreScreening,
+		GradingScale:       s_d.GradingScaleNumber,
+		OverallNumberGrade: 9.0,
+	}
+	c, storer, _, _ := newTestSubmissionController(m)
+	ctx := context.WithValue(context.Background(), constants.SessionUserRole, int8(u_d.UserRoleRetailer))
+	ctx = context.WithValue(ctx, constants.SessionUserID, primitive.NewObjectID())
+	ctx = context.WithValue(ctx, constants.SessionUserOrganizationID, m.OrganizationID)
+
+	// Retailers may edit their submissions but not grade them.
+	req := &ComicSubmissionUpdateRequestIDO{
+		ID:                 m.ID,
+		OrganizationID:     m.OrganizationID,
+		ServiceType:        m.ServiceType,
+		GradingScale:       m.GradingScale,
+		OverallNumberGrade: 9.8,
+		GradeChangeReason:  "Looks mint to me.",
+	}
+	if _, err := c.UpdateByID(ctx, req); err == nil {
+		t.Error("expected the re-grade to be refused")
+	}
+	if storer.m.OverallNumberGrade != 9.0 || len(storer.revisions) != 0 {
+		t.Errorf("expected the grade to be kept but got %v", storer.m.OverallNumberGrade)
+	}
+}
@@ -26,6 +26,9 @@ func (impl ComicSubmissionStorerImpl) CountByFilter(ctx context.Context, f *Comi
 	if f.OrganizationID != primitive.NilObjectID {
 		filter["organization_id"] = f.OrganizationID
 	}
+	if f.OrderID != primitive.NilObjectID {
+		filter["order_id"] = f.OrderID
+	}
 
 	if f.UserID != primitive.NilObjectID {
 		filter["user_id"] = f.UserID
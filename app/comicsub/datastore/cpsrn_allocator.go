@@ -0,0 +1,173 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+
+	c "github.com/LuchaComics/cps-backend/config"
+	"github.com/LuchaComics/cps-backend/provider/cpsrn"
+)
+
+const (
+	// cpsrnCounterSeedMigrationID is the unique identifier used to record in
+	// the `migrations` collection that the counters were seeded.
+	cpsrnCounterSeedMigrationID = "cpsrn_counters_seed"
+)
+
+// CPSRNCounter represents the per-role sequence document which keeps track of
+// how many `CPS Registry Numbers` have been handed out for that role.
+type CPSRNCounter struct {
+	RoleID     int8      `bson:"_id" json:"role_id"`
+	Sequence   int64     `bson:"sequence" json:"sequence"`
+	ModifiedAt time.Time `bson:"modified_at" json:"modified_at"`
+}
+
+// CPSRNAllocator Interface for atomically reserving `CPS Registry Numbers`.
+type CPSRNAllocator interface {
+	// Allocate reserves and returns the next `CPS Registry Number` for the
+	// role. A number is never handed out twice, not even after the submission
+	// it belonged to was permanently deleted.
+	Allocate(ctx context.Context, roleID int8) (string, error)
+
+	// AllocateMany reserves a block of `count` consecutive numbers for the
+	// role in one database round trip, ex: for the submissions of an order.
+	AllocateMany(ctx context.Context, roleID int8, count int) ([]string, error)
+}
+
+type CPSRNAllocatorImpl struct {
+	Logger                *slog.Logger
+	CPSRN                 cpsrn.Provider
+	Collection            *mongo.Collection
+	MigrationCollection   *mongo.Collection
+	SubmissionsCollection *mongo.Collection
+}
+
+func NewCPSRNAllocator(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client, cpsrnp cpsrn.Provider) CPSRNAllocator {
+	db := client.Database(appCfg.DB.Name)
+	impl := &CPSRNAllocatorImpl{
+		Logger:                loggerp,
+		CPSRN:                 cpsrnp,
+		Collection:            db.Collection("cpsrn_counters"),
+		MigrationCollection:   db.Collection("migrations"),
+		SubmissionsCollection: db.Collection("comic_submissions"),
+	}
+	if err := impl.seedCounters(context.Background()); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+	return impl
+}
+
+func (impl CPSRNAllocatorImpl) Allocate(ctx context.Context, roleID int8) (string, error) {
+	numbers, err := impl.AllocateMany(ctx, roleID, 1)
+	if err != nil {
+		return "", err
+	}
+	return numbers[0], nil
+}
+
+func (impl CPSRNAllocatorImpl) AllocateMany(ctx context.Context, roleID int8, count int) ([]string, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("cannot allocate %v cpsrn", count)
+	}
+	filter := bson.M{"_id": roleID}
+	update := bson.M{
+		"$inc": bson.M{"sequence": count},
+		"$set": bson.M{"modified_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counter CPSRNCounter
+	err := impl.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		// DEVELOPERS NOTE:
+		// Two concurrent upserts for a role without a counter document will
+		// race on the insert; the loser simply needs to try again to pick up
+		// the document which the winner created.
+		err = impl.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		impl.Logger.Error("database allocate cpsrn error", slog.Any("error", err))
+		return nil, err
+	}
+
+	// The `sequence` counts every number handed out for the role so the
+	// numbers we just reserved are the `count` ones before that.
+	numbers := make([]string, count)
+	first := counter.Sequence - int64(count)
+	for i := range numbers {
+		numbers[i] = impl.CPSRN.GenerateNumber(roleID, first+int64(i))
+	}
+	return numbers, nil
+}
+
+// seedCounters is a one-time migration which initializes the counters from
+// the `CPS Registry Numbers` already stored in the submissions collection.
+func (impl CPSRNAllocatorImpl) seedCounters(ctx context.Context) error {
+	count, err := impl.MigrationCollection.CountDocuments(ctx, bson.M{"_id": cpsrnCounterSeedMigrationID})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		impl.Logger.Debug("cpsrn counters already seeded")
+		return nil
+	}
+	impl.Logger.Info("cpsrn counters seeding started...")
+
+	opts := options.Find().SetProjection(bson.M{"cpsrn": 1, "created_by_user_role": 1})
+	cursor, err := impl.SubmissionsCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	// Find the highest sequence used per role. Do not rely on counting the
+	// documents because deleted submissions would make us re-use numbers.
+	highest := map[int8]int64{}
+	for cursor.Next(ctx) {
+		var row struct {
+			CPSRN             string `bson:"cpsrn"`
+			CreatedByUserRole int8   `bson:"created_by_user_role"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			return err
+		}
+		n, err := impl.CPSRN.Parse(row.CPSRN)
+		if err != nil {
+			impl.Logger.Warn("skipping unparsable cpsrn", slog.String("cpsrn", row.CPSRN), slog.Any("error", err))
+			continue
+		}
+		if current, ok := highest[row.CreatedByUserRole]; !ok || n.Sequence+1 > current {
+			highest[row.CreatedByUserRole] = n.Sequence + 1
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	for roleID, sequence := range highest {
+		// Use `$max` so a counter which is somehow already ahead never moves back.
+		update := bson.M{
+			"$max": bson.M{"sequence": sequence},
+			"$set": bson.M{"modified_at": time.Now()},
+		}
+		if _, err := impl.Collection.UpdateOne(ctx, bson.M{"_id": roleID}, update, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+		impl.Logger.Info("cpsrn counter seeded", slog.Int("role_id", int(roleID)), slog.Int64("sequence", sequence))
+	}
+
+	if _, err := impl.MigrationCollection.InsertOne(ctx, bson.M{"_id": cpsrnCounterSeedMigrationID, "created_at": time.Now()}); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	impl.Logger.Info("cpsrn counters seeding finished")
+	return nil
+}
@@ -0,0 +1,188 @@
+package datastore
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/provider/cpsrn"
+)
+
+func newTestAllocator(mt *mtest.T) *CPSRNAllocatorImpl {
+	// The mock deployment answers the commands in the order they are sent so
+	// every collection can share it.
+	return &CPSRNAllocatorImpl{
+		Logger:                slog.New(slog.NewTextHandler(os.Stderr)),
+		CPSRN:                 cpsrn.NewProvider(),
+		Collection:            mt.Coll,
+		MigrationCollection:   mt.Coll,
+		SubmissionsCollection: mt.Coll,
+	}
+}
+
+func counterResponse(roleID int8, sequence int64) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{
+		{Key: "_id", Value: roleID},
+		{Key: "sequence", Value: sequence},
+	}})
+}
+
+func TestAllocateMany(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+	p := cpsrn.NewProvider()
+
+	mt.Run("reserves the numbers before the sequence", func(mt *mtest.T) {
+		impl := newTestAllocator(mt)
+		mt.AddMockResponses(counterResponse(2, 12))
+
+		numbers, err := impl.AllocateMany(context.Background(), 2, 3)
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		expected := []string{p.GenerateNumber(2, 9), p.GenerateNumber(2, 10), p.GenerateNumber(2, 11)}
+		for i := range expected {
+			if numbers[i] != expected[i] {
+				t.Errorf("number %v: got %v but was expecting %v", i, numbers[i], expected[i])
+			}
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		if upsert, _ := cmd.Lookup("upsert").BooleanOK(); !upsert {
+			t.Error("counter is not upserted")
+		}
+		if inc := cmd.Lookup("update", "$inc", "sequence").AsInt64(); inc != 3 {
+			t.Errorf("got $inc %v but was expecting 3", inc)
+		}
+	})
+
+	mt.Run("first number of a role", func(mt *mtest.T) {
+		impl := newTestAllocator(mt)
+		mt.AddMockResponses(counterResponse(1, 1))
+
+		number, err := impl.Allocate(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		if expected := p.GenerateNumber(1, 0); number != expected {
+			t.Errorf("got %v but was expecting %v", number, expected)
+		}
+	})
+
+	mt.Run("retries once after losing the upsert race", func(mt *mtest.T) {
+		impl := newTestAllocator(mt)
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11000, Name: "DuplicateKey", Message: "E11000 duplicate key error"}),
+			counterResponse(1, 5),
+		)
+
+		number, err := impl.Allocate(context.Background(), 1)
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		if expected := p.GenerateNumber(1, 4); number != expected {
+			t.Errorf("got %v but was expecting %v", number, expected)
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 2 {
+			t.Errorf("sent %v commands but was expecting 2", n)
+		}
+	})
+
+	mt.Run("other errors are not retried", func(mt *mtest.T) {
+		impl := newTestAllocator(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 2, Name: "BadValue", Message: "bad value"}))
+
+		if _, err := impl.Allocate(context.Background(), 1); err == nil {
+			t.Error("expected an error")
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 1 {
+			t.Errorf("sent %v commands but was expecting 1", n)
+		}
+	})
+
+	mt.Run("rejects an empty block", func(mt *mtest.T) {
+		impl := newTestAllocator(mt)
+		if _, err := impl.AllocateMany(context.Background(), 1, 0); err == nil {
+			t.Error("expected an error")
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 0 {
+			t.Errorf("sent %v commands but was expecting none", n)
+		}
+	})
+}
+
+func TestSeedCounters(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+	p := cpsrn.NewProvider()
+	ns := "cps.comic_submissions"
+
+	mt.Run("already seeded", func(mt *mtest.T) {
+		impl := newTestAllocator(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
+
+		if err := impl.seedCounters(context.Background()); err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 1 {
+			t.Errorf("sent %v commands but was expecting 1", n)
+		}
+	})
+
+	mt.Run("seeds the highest sequence of every role", func(mt *mtest.T) {
+		impl := newTestAllocator(mt)
+		row := func(cpsrn string, roleID int8) bson.D {
+			return bson.D{{Key: "cpsrn", Value: cpsrn}, {Key: "created_by_user_role", Value: roleID}}
+		}
+		mt.AddMockResponses(
+			// Not seeded yet.
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				row(p.GenerateNumber(1, 7), 1),
+				row(p.GenerateNumber(1, 3), 1),
+				// Legacy number issued before check digits.
+				row("788346-26649-2-1041", 2),
+				row("not a cpsrn", 2),
+				row("", 3),
+			),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		if err := impl.seedCounters(context.Background()); err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+
+		events := mt.GetAllStartedEvents()
+		if len(events) != 5 {
+			t.Fatalf("sent %v commands but was expecting 5", len(events))
+		}
+		seeded := map[int32]int64{}
+		for _, e := range events[2:4] {
+			update := e.Command.Lookup("updates").Array().Index(0).Value().Document()
+			roleID := update.Lookup("q", "_id").Int32()
+			seeded[roleID] = update.Lookup("u", "$max", "sequence").AsInt64()
+			if upsert, _ := update.Lookup("upsert").BooleanOK(); !upsert {
+				t.Errorf("role %v: counter is not upserted", roleID)
+			}
+		}
+		// Role 3 only has an unparsable number so it has no counter.
+		expected := map[int32]int64{1: 8, 2: 41}
+		for roleID, sequence := range expected {
+			if seeded[roleID] != sequence {
+				t.Errorf("role %v: got sequence %v but was expecting %v", roleID, seeded[roleID], sequence)
+			}
+		}
+		if len(seeded) != len(expected) {
+			t.Errorf("got %v counters but was expecting %v", seeded, expected)
+		}
+		if id := events[4].Command.Lookup("documents").Array().Index(0).Value().Document().Lookup("_id").StringValue(); id != cpsrnCounterSeedMigrationID {
+			t.Errorf("got migration %v", id)
+		}
+	})
+}
@@ -25,6 +25,7 @@ func (impl ComicSubmissionStorerImpl) Create(ctx context.Context, u *ComicSubmis
 	// check for errors in the insertion
 	if err != nil {
 		impl.Logger.Error("database insert error", slog.Any("error", err))
+		return err
 	}
 
 	// display the id of the newly inserted object
@@ -32,3 +33,20 @@ func (impl ComicSubmissionStorerImpl) Create(ctx context.Context, u *ComicSubmis
 
 	return nil
 }
+
+// CreateMany inserts all the submissions in one database round trip, ex: the
+// submissions of an order.
+func (impl ComicSubmissionStorerImpl) CreateMany(ctx context.Context, ms []*ComicSubmission) error {
+	docs := make([]interface{}, len(ms))
+	for i, m := range ms {
+		if m.ID == primitive.NilObjectID {
+			m.ID = primitive.NewObjectID()
+		}
+		docs[i] = m
+	}
+	if _, err := impl.Collection.InsertMany(ctx, docs); err != nil {
+		impl.Logger.Error("database insert many error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -8,15 +8,24 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/exp/slog"
 
 	c "github.com/LuchaComics/cps-backend/config"
 )
 
 const (
-	StatusPending                                 = 1
-	StatusActive                                  = 2
-	StatusError                                   = 3
+	StatusPending                                 = 1 // Deprecated: legacy status, handled like `StatusReceived`.
+	StatusActive                                  = 2 // Deprecated: legacy status, handled like `StatusReceived`.
+	StatusError                                   = 3 // Deprecated: legacy status, handled like `StatusReceived`.
+	StatusReceived                                = 4
+	StatusInGrading                               = 5
+	StatusGraded                                  = 6
+	StatusEncapsulated                            = 7
+	StatusShipped                                 = 8
+	StatusCompleted                               = 9
+	StatusOnHold                                  = 10
+	StatusRejected                                = 11
 	StatusArchived                                = 100
 	ServiceTypePreScreening                       = 1
 	ServiceTypePedigree                           = 2
@@ -45,6 +54,11 @@ const (
 	PrimaryLabelDetailsFacsimile                  = 7
 	PrimaryLabelDetailsReprint                    = 8
 	PrimaryLabelDetailsOther                      = 1
+	PDFStatusPending                              = 1
+	PDFStatusGenerating                           = 2
+	PDFStatusReady                                = 3
+	PDFStatusFailed                               = 4
+	PDFStatusAwaitingSignOffs                     = 5
 )
 
 type ComicSubmission struct {
@@ -52,6 +66,7 @@ type ComicSubmission struct {
 	OrganizationID                     primitive.ObjectID `bson:"organization_id,omitempty" json:"organization_id,omitempty"`
 	OrganizationName                   string             `bson:"organization_name" json:"organization_name"`
 	CPSRN                              string             `bson:"cpsrn" json:"cpsrn"`
+	OrderID                            primitive.ObjectID `bson:"order_id,omitempty" json:"order_id,omitempty"` // The order the submission was received with, if any.
 	CreatedAt                          time.Time          `bson:"created_at,omitempty" json:"created_at,omitempty"`
 	CreatedByUserID                    primitive.ObjectID `bson:"created_by_user_id,omitempty" json:"created_by_user_id,omitempty"`
 	CreatedByUserRole                  int8               `bson:"created_by_user_role" json:"created_by_user_role"`
@@ -59,7 +74,8 @@ type ComicSubmission struct {
 	ModifiedByUserID                   primitive.ObjectID `bson:"modified_by_user_id,omitempty" json:"modified_by_user_id,omitempty"`
 	ModifiedByUserRole                 int8               `bson:"modified_by_user_role" json:"modified_by_user_role"`
 	ServiceType                        int8               `bson:"service_type" json:"service_type"`
-	Status                             int8               `bson:"status" json:"status"`
+	Status                             int8               `bson:"status" json:"status"` // Only changed through the transitions of our state machine.
+	StatusModifiedAt                   time.Time          `bson:"status_modified_at,omitempty" json:"status_modified_at,omitempty"`
 	SubmissionDate                     time.Time          `bson:"submission_date" json:"submission_date"`
 	Item                               string             `bson:"item" json:"item"` // Created by system.
 	SeriesTitle                        string             `bson:"series_title" json:"series_title"`
@@ -112,9 +128,19 @@ type ComicSubmission struct {
 	Filename                           string             `bson:"filename" json:"filename"`
 	FileUploadS3ObjectKey              string             `bson:"file_upload_s3_key" json:"file_upload_s3_object_key"`
 	FileUploadDownloadableFileURL      string
+	PDFStatus                          int8                   `bson:"pdf_status" json:"pdf_status"`
+	PDFJobID                           primitive.ObjectID     `bson:"pdf_job_id,omitempty" json:"pdf_job_id,omitempty"`
+	PDFError                           string                 `bson:"pdf_error" json:"pdf_error,omitempty"`
 	Comments                           []*SubmissionComment   `bson:"comments" json:"comments,omitempty"`
 	CollectibleType                    int8                   `bson:"collectible_type" json:"collectible_type"`
 	Signatures                         []*SubmissionSignature `bson:"signatures" json:"signatures,omitempty"`
+	InspectorSignOffs                  []*InspectorSignOff    `bson:"inspector_sign_offs" json:"inspector_sign_offs,omitempty"`
+	GradingConsensus                   *GradingConsensus      `bson:"grading_consensus" json:"grading_consensus,omitempty"`
+	StatusHistory                      []*StatusTransition    `bson:"status_history" json:"status_history,omitempty"`
+	RegistrySignature                  string                 `bson:"registry_signature" json:"registry_signature,omitempty"`               // Ed25519 signature of the grade when the certificate was issued.
+	RegistrySignatureKeyID             string                 `bson:"registry_signature_key_id" json:"registry_signature_key_id,omitempty"` // Identifies the public key to verify the signature with.
+	RegistrySignedAt                   time.Time              `bson:"registry_signed_at,omitempty" json:"registry_signed_at,omitempty"`
+	RegistrySignatureVerified          bool                   `bson:"-" json:"registry_signature_verified"` // Set by the controller on lookup.
 }
 
 type SubmissionComment struct {
@@ -143,6 +169,7 @@ type ComicSubmissionListFilter struct {
 
 	// Filter related.
 	OrganizationID    primitive.ObjectID
+	OrderID           primitive.ObjectID
 	UserID            primitive.ObjectID
 	UserEmail         string
 	CreatedByUserRole int8
@@ -190,21 +217,34 @@ type ComicSubmissionAsSelectOption struct {
 // ComicSubmissionStorer Interface for submission.
 type ComicSubmissionStorer interface {
 	Create(ctx context.Context, m *ComicSubmission) error
+	CreateMany(ctx context.Context, ms []*ComicSubmission) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*ComicSubmission, error)
 	GetByCPSRN(ctx context.Context, cpsrn string) (*ComicSubmission, error)
 	UpdateByID(ctx context.Context, m *ComicSubmission) error
+	UpdatePDFByID(ctx context.Context, m *ComicSubmission, jobID primitive.ObjectID) (bool, error)
+	UpdatePDFJobByID(ctx context.Context, m *ComicSubmission) error
+	UpdateUserByID(ctx context.Context, m *ComicSubmission) error
+	CreateCommentByID(ctx context.Context, m *ComicSubmission, comment *SubmissionComment) error
 	ListByFilter(ctx context.Context, f *ComicSubmissionListFilter) (*ComicSubmissionListResult, error)
+	ExportByFilter(ctx context.Context, f *ComicSubmissionListFilter, fn func(m *ComicSubmission) error) error
 	ListAsSelectOptionByFilter(ctx context.Context, f *ComicSubmissionListFilter) ([]*ComicSubmissionAsSelectOption, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
 	CountAll(ctx context.Context) (int64, error)
 	CountByFilter(ctx context.Context, f *ComicSubmissionListFilter) (int64, error)
+	CreateGradeRevision(ctx context.Context, r *GradeRevision) error
+	GetGradeRevisionByID(ctx context.Context, id primitive.ObjectID) (*GradeRevision, error)
+	ListGradeRevisionsBySubmissionID(ctx context.Context, submissionID primitive.ObjectID) ([]*GradeRevision, error)
+	IsFileSupersededByGradeRevision(ctx context.Context, fileUploadS3ObjectKey string) (bool, error)
+	UpdateGradingByID(ctx context.Context, m *ComicSubmission, previousModifiedAt time.Time) (bool, error)
+	UpdateStatusByID(ctx context.Context, m *ComicSubmission, previousStatus int8) (bool, error)
 	// //TODO: Add more...
 }
 
 type ComicSubmissionStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger                  *slog.Logger
+	DbClient                *mongo.Client
+	Collection              *mongo.Collection
+	GradeRevisionCollection *mongo.Collection
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) ComicSubmissionStorer {
@@ -231,10 +271,46 @@ func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) Co
 		log.Fatal(err)
 	}
 
+	// DEVELOPERS NOTE:
+	// No two submissions may ever share the same `CPS Registry Number`. Legacy
+	// records created before the allocator existed may already contain
+	// duplicates which prevent this index from being built; we do not crash
+	// in that case so operators can resolve them while the service runs.
+	uniqueIndexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "cpsrn", Value: 1}},
+		Options: options.Index().
+			SetName("cpsrn_unique").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"cpsrn": bson.M{"$type": "string", "$gt": ""}}),
+	}
+	if _, err := uc.Indexes().CreateOne(context.TODO(), uniqueIndexModel); err != nil {
+		loggerp.Error("database create unique cpsrn index error", slog.Any("error", err))
+	}
+
+	// Used to look up the submissions of an order.
+	orderIndexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "order_id", Value: 1}},
+		Options: options.Index().SetSparse(true),
+	}
+	if _, err := uc.Indexes().CreateOne(context.TODO(), orderIndexModel); err != nil {
+		log.Fatal(err)
+	}
+
+	// The grade history of every submission is kept in its own collection
+	// which we only ever insert into.
+	grc := client.Database(appCfg.DB.Name).Collection("comic_submission_grade_revisions")
+	if _, err := grc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "submission_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "superseded_file_upload_s3_key", Value: 1}}},
+	}); err != nil {
+		log.Fatal(err)
+	}
+
 	s := &ComicSubmissionStorerImpl{
-		Logger:     loggerp,
-		DbClient:   client,
-		Collection: uc,
+		Logger:                  loggerp,
+		DbClient:                client,
+		Collection:              uc,
+		GradeRevisionCollection: grc,
 	}
 	return s
 }
@@ -0,0 +1,145 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+// GradeRevision records one change to the grade of a submission. Revisions
+// are only ever inserted so we always know what every certificate we issued
+// said, even after the submission was re-graded.
+type GradeRevision struct {
+	ID                              primitive.ObjectID `bson:"_id" json:"id"`
+	SubmissionID                    primitive.ObjectID `bson:"submission_id" json:"submission_id"`
+	OrganizationID                  primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	CPSRN                           string             `bson:"cpsrn" json:"cpsrn"`
+	CreatedAt                       time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID                 primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id"`
+	CreatedByUserRole               int8               `bson:"created_by_user_role" json:"created_by_user_role"`
+	CreatedByName                   string             `bson:"created_by_name" json:"created_by_name"`
+	Reason                          string             `bson:"reason" json:"reason"`
+	OldValues                       *GradeValues       `bson:"old_values" json:"old_values"`
+	NewValues                       *GradeValues       `bson:"new_values" json:"new_values"`
+	SupersededFileUploadS3ObjectKey string             `bson:"superseded_file_upload_s3_key" json:"superseded_file_upload_s3_object_key"` // The certificate issued with the old values.
+	SupersededDownloadableFileURL   string             `bson:"-" json:"superseded_downloadable_file_url,omitempty"`                       // Set by the controller.
+}
+
+// GradeValues are the fields of a submission which make up its grade.
+type GradeValues struct {
+	CreasesFinding                     string  `bson:"creases_finding" json:"creases_finding"`
+	TearsFinding                       string  `bson:"tears_finding" json:"tears_finding"`
+	MissingPartsFinding                string  `bson:"missing_parts_finding" json:"missing_parts_finding"`
+	StainsFinding                      string  `bson:"stains_finding" json:"stains_finding"`
+	DistortionFinding                  string  `bson:"distortion_finding" json:"distortion_finding"`
+	PaperQualityFinding                string  `bson:"paper_quality_finding" json:"paper_quality_finding"`
+	SpineFinding                       string  `bson:"spine_finding" json:"spine_finding"`
+	CoverFinding                       string  `bson:"cover_finding" json:"cover_finding"`
+	ShowsSignsOfTamperingOrRestoration int8    `bson:"shows_signs_of_tampering_or_restoration" json:"shows_signs_of_tampering_or_restoration"`
+	GradingScale                       int8    `bson:"grading_scale" json:"grading_scale"`
+	OverallLetterGrade                 string  `bson:"overall_letter_grade" json:"overall_letter_grade"`
+	OverallNumberGrade                 float64 `bson:"overall_number_grade" json:"overall_number_grade"`
+	CpsPercentageGrade                 float64 `bson:"cps_percentage_grade" json:"cps_percentage_grade"`
+	IsOverallLetterGradeNearMintPlus   bool    `bson:"is_overall_letter_grade_near_mint_plus" json:"is_overall_letter_grade_near_mint_plus"`
+	IsCpsIndieMintGem                  bool    `bson:"is_cps_indie_mint_gem" json:"is_cps_indie_mint_gem"`
+	GradingNotes                       string  `bson:"grading_notes" json:"grading_notes"`
+}
+
+// GradeValuesOf returns a copy of the grade of the submission.
+func GradeValuesOf(m *ComicSubmission) *GradeValues {
+	return &GradeValues{
+		CreasesFinding:                     m.CreasesFinding,
+		TearsFinding:                       m.TearsFinding,
+		MissingPartsFinding:                m.MissingPartsFinding,
+		StainsFinding:                      m.StainsFinding,
+		DistortionFinding:                  m.DistortionFinding,
+		PaperQualityFinding:                m.PaperQualityFinding,
+		SpineFinding:                       m.SpineFinding,
+		CoverFinding:                       m.CoverFinding,
+		ShowsSignsOfTamperingOrRestoration: m.ShowsSignsOfTamperingOrRestoration,
+		GradingScale:                       m.GradingScale,
+		OverallLetterGrade:                 m.OverallLetterGrade,
+		OverallNumberGrade:                 m.OverallNumberGrade,
+		CpsPercentageGrade:                 m.CpsPercentageGrade,
+		IsOverallLetterGradeNearMintPlus:   m.IsOverallLetterGradeNearMintPlus,
+		IsCpsIndieMintGem:                  m.IsCpsIndieMintGem,
+		GradingNotes:                       m.GradingNotes,
+	}
+}
+
+// SetGradeValues overwrites the grade of the submission.
+func SetGradeValues(m *ComicSubmission, g *GradeValues) {
+	m.CreasesFinding = g.CreasesFinding
+	m.TearsFinding = g.TearsFinding
+	m.MissingPartsFinding = g.MissingPartsFinding
+	m.StainsFinding = g.StainsFinding
+	m.DistortionFinding = g.DistortionFinding
+	m.PaperQualityFinding = g.PaperQualityFinding
+	m.SpineFinding = g.SpineFinding
+	m.CoverFinding = g.CoverFinding
+	m.ShowsSignsOfTamperingOrRestoration = g.ShowsSignsOfTamperingOrRestoration
+	m.GradingScale = g.GradingScale
+	m.OverallLetterGrade = g.OverallLetterGrade
+	m.OverallNumberGrade = g.OverallNumberGrade
+	m.CpsPercentageGrade = g.CpsPercentageGrade
+	m.IsOverallLetterGradeNearMintPlus = g.IsOverallLetterGradeNearMintPlus
+	m.IsCpsIndieMintGem = g.IsCpsIndieMintGem
+	m.GradingNotes = g.GradingNotes
+}
+
+func (impl ComicSubmissionStorerImpl) CreateGradeRevision(ctx context.Context, r *GradeRevision) error {
+	if r.ID == primitive.NilObjectID {
+		r.ID = primitive.NewObjectID()
+	}
+	if _, err := impl.GradeRevisionCollection.InsertOne(ctx, r); err != nil {
+		impl.Logger.Error("database create grade revision error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl ComicSubmissionStorerImpl) GetGradeRevisionByID(ctx context.Context, id primitive.ObjectID) (*GradeRevision, error) {
+	var result GradeRevision
+	if err := impl.GradeRevisionCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		impl.Logger.Error("database get grade revision by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListGradeRevisionsBySubmissionID returns the revisions with the newest first.
+func (impl ComicSubmissionStorerImpl) ListGradeRevisionsBySubmissionID(ctx context.Context, submissionID primitive.ObjectID) ([]*GradeRevision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := impl.GradeRevisionCollection.Find(ctx, bson.M{"submission_id": submissionID}, opts)
+	if err != nil {
+		impl.Logger.Error("database list grade revisions error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*GradeRevision{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database list grade revisions decode error", slog.Any("error", err))
+		return nil, err
+	}
+	return results, nil
+}
+
+// IsFileSupersededByGradeRevision returns true if the uploaded certificate is
+// part of the grade history and therefore must never be deleted.
+func (impl ComicSubmissionStorerImpl) IsFileSupersededByGradeRevision(ctx context.Context, fileUploadS3ObjectKey string) (bool, error) {
+	count, err := impl.GradeRevisionCollection.CountDocuments(ctx, bson.M{"superseded_file_upload_s3_key": fileUploadS3ObjectKey})
+	if err != nil {
+		impl.Logger.Error("database count grade revisions error", slog.Any("error", err))
+		return false, err
+	}
+	return count > 0, nil
+}
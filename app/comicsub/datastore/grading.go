@@ -0,0 +1,81 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+)
+
+// InspectorSignOff is the grade one inspector gave the submission during the
+// grading session, independently of the other inspectors.
+type InspectorSignOff struct {
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	FirstName   string             `bson:"first_name" json:"first_name"`
+	LastName    string             `bson:"last_name" json:"last_name"`
+	CompanyName string             `bson:"company_name" json:"company_name"`
+	Signature   string             `bson:"signature" json:"signature"`
+	SignedAt    time.Time          `bson:"signed_at" json:"signed_at"`
+	Grade       *GradeValues       `bson:"grade" json:"grade"`
+}
+
+// GradingConsensus is the grade computed from all the sign-offs of the
+// grading session.
+type GradingConsensus struct {
+	Grade            *GradeValues `bson:"grade" json:"grade"`
+	SignOffCount     int          `bson:"sign_off_count" json:"sign_off_count"`
+	RequiredSignOffs int          `bson:"required_sign_offs" json:"required_sign_offs"`
+	IsReached        bool         `bson:"is_reached" json:"is_reached"`       // True once enough inspectors signed off to issue the certificate.
+	Disagreements    []string     `bson:"disagreements" json:"disagreements"` // The fields where the inspectors are further apart than our tolerance.
+	ComputedAt       time.Time    `bson:"computed_at" json:"computed_at"`
+}
+
+// UpdateGradingByID saves the submission only if nobody else modified it
+// since it was read, so concurrent sign-offs and re-grades are not lost.
+// Returns false if the submission was modified in the meantime. The
+// certificate fields are left to `UpdatePDFByID` so a job which finished in
+// the meantime is not overwritten with the values we read.
+func (impl ComicSubmissionStorerImpl) UpdateGradingByID(ctx context.Context, m *ComicSubmission, previousModifiedAt time.Time) (bool, error) {
+	doc, err := withoutPDFFields(m)
+	if err != nil {
+		impl.Logger.Error("database update grading by id marshal error", slog.Any("error", err))
+		return false, err
+	}
+	filter := bson.M{"_id": m.ID, "modified_at": previousModifiedAt}
+	update := bson.M{"$set": doc}
+
+	result, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		impl.Logger.Error("database update grading by id error", slog.Any("error", err))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// pdfFields are only written by `UpdatePDFByID`.
+var pdfFields = []string{
+	"pdf_status",
+	"pdf_job_id",
+	"pdf_error",
+	"file_upload_s3_key",
+	"registry_signature",
+	"registry_signature_key_id",
+	"registry_signed_at",
+}
+
+func withoutPDFFields(m *ComicSubmission) (bson.M, error) {
+	b, err := bson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	for _, field := range pdfFields {
+		delete(doc, field)
+	}
+	return doc, nil
+}
@@ -0,0 +1,54 @@
+package datastore
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"golang.org/x/exp/slog"
+)
+
+func TestUpdateGradingByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("leaves the certificate to the jobs", func(mt *mtest.T) {
+		impl := ComicSubmissionStorerImpl{Logger: slog.New(slog.NewTextHandler(os.Stderr)), Collection: mt.Coll}
+		previousModifiedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		m := &ComicSubmission{
+			ID:                    primitive.NewObjectID(),
+			OverallLetterGrade:    "vf",
+			ModifiedAt:            time.Now(),
+			PDFStatus:             PDFStatusPending,
+			FileUploadS3ObjectKey: "uploads/stale.pdf",
+			RegistrySignature:     "stale",
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		ok, err := impl.UpdateGradingByID(context.Background(), m, previousModifiedAt)
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		if ok {
+			t.Error("update of a submission modified in the meantime was reported as saved")
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if at := update.Lookup("q", "modified_at").Time(); !at.Equal(previousModifiedAt) {
+			t.Errorf("got modified_at filter %v but was expecting %v", at, previousModifiedAt)
+		}
+		set := update.Lookup("u", "$set").Document()
+		for _, field := range pdfFields {
+			if _, err := set.LookupErr(field); err == nil {
+				t.Errorf("%v was written", field)
+			}
+		}
+		if grade := set.Lookup("overall_letter_grade").StringValue(); grade != "vf" {
+			t.Errorf("got grade %v", grade)
+		}
+	})
+}
@@ -15,31 +15,7 @@ func (impl ComicSubmissionStorerImpl) ListByFilter(ctx context.Context, f *Comic
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
 
-	// Create the filter based on the cursor
-	filter := bson.M{}
-	if !f.Cursor.IsZero() {
-		filter["_id"] = bson.M{"$gt": f.Cursor} // Add the cursor condition to the filter
-	}
-
-	// Add filter conditions to the filter
-	if f.UserID != primitive.NilObjectID {
-		filter["user_id"] = f.UserID
-	}
-	if f.UserEmail != "" {
-		filter["user.email"] = f.UserEmail
-	}
-	if f.CreatedByUserRole != 0 {
-		filter["created_by_user_role"] = f.CreatedByUserRole
-	}
-	if f.OrganizationID != primitive.NilObjectID {
-		filter["organization_id"] = f.OrganizationID
-	}
-	if f.Status != 0 {
-		filter["status"] = f.Status
-	}
-	if !f.CreatedAtGTE.IsZero() {
-		filter["created_at"] = bson.M{"$gt": f.CreatedAtGTE} // Add the cursor condition to the filter
-	}
+	filter := newListFilter(f)
 
 	impl.Logger.Debug("listing filter:",
 		slog.Any("filter", filter))
@@ -101,6 +77,41 @@ func (impl ComicSubmissionStorerImpl) ListByFilter(ctx context.Context, f *Comic
 	}, nil
 }
 
+// newListFilter returns the query of the filter which is shared by our list
+// and export functions.
+func newListFilter(f *ComicSubmissionListFilter) bson.M {
+	// Create the filter based on the cursor
+	filter := bson.M{}
+	if !f.Cursor.IsZero() {
+		filter["_id"] = bson.M{"$gt": f.Cursor} // Add the cursor condition to the filter
+	}
+
+	// Add filter conditions to the filter
+	if f.UserID != primitive.NilObjectID {
+		filter["user_id"] = f.UserID
+	}
+	if f.UserEmail != "" {
+		filter["user.email"] = f.UserEmail
+	}
+	if f.CreatedByUserRole != 0 {
+		filter["created_by_user_role"] = f.CreatedByUserRole
+	}
+	if f.OrganizationID != primitive.NilObjectID {
+		filter["organization_id"] = f.OrganizationID
+	}
+	if f.OrderID != primitive.NilObjectID {
+		filter["order_id"] = f.OrderID
+	}
+	if f.Status != 0 {
+		filter["status"] = f.Status
+	}
+	if !f.CreatedAtGTE.IsZero() {
+		filter["created_at"] = bson.M{"$gt": f.CreatedAtGTE} // Add the cursor condition to the filter
+	}
+
+	return filter
+}
+
 func (impl ComicSubmissionStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *ComicSubmissionListFilter) ([]*ComicSubmissionAsSelectOption, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
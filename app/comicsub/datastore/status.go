@@ -0,0 +1,47 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+)
+
+// StatusTransition records one move of the submission through our state
+// machine, ex: from received to in grading.
+type StatusTransition struct {
+	From              int8               `bson:"from" json:"from"`
+	To                int8               `bson:"to" json:"to"`
+	Reason            string             `bson:"reason" json:"reason,omitempty"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID   primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id"`
+	CreatedByUserRole int8               `bson:"created_by_user_role" json:"created_by_user_role"`
+	CreatedByName     string             `bson:"created_by_name" json:"created_by_name"`
+}
+
+// UpdateStatusByID saves the status and its history of the submission only
+// if it is still in the status it was read with, so two users cannot apply
+// conflicting transitions. Returns false if the status changed in the
+// meantime.
+func (impl ComicSubmissionStorerImpl) UpdateStatusByID(ctx context.Context, m *ComicSubmission, previousStatus int8) (bool, error) {
+	filter := bson.M{"_id": m.ID, "status": previousStatus}
+	update := bson.M{
+		"$set": bson.M{
+			"status":                m.Status,
+			"status_modified_at":    m.StatusModifiedAt,
+			"modified_at":           m.ModifiedAt,
+			"modified_by_user_id":   m.ModifiedByUserID,
+			"modified_by_user_role": m.ModifiedByUserRole,
+			"status_history":        m.StatusHistory,
+		},
+	}
+
+	result, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		impl.Logger.Error("database update status by id error", slog.Any("error", err))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
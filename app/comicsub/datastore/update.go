@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 )
 
@@ -25,3 +26,100 @@ func (impl ComicSubmissionStorerImpl) UpdateByID(ctx context.Context, m *ComicSu
 
 	return nil
 }
+
+// UpdatePDFByID only saves the generated certificate related fields so a
+// background job does not overwrite changes made by users in the meantime.
+// Nothing is saved and false is returned unless the job is still the latest
+// job of the submission, as a job scheduled since generates newer values.
+// The `modified_at` never moves back, ex: to the value a job read before a
+// re-grade, since `UpdateGradingByID` relies on it to detect changes.
+func (impl ComicSubmissionStorerImpl) UpdatePDFByID(ctx context.Context, m *ComicSubmission, jobID primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": m.ID, "pdf_job_id": jobID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"pdf_status":                m.PDFStatus,
+			"pdf_error":                 m.PDFError,
+			"file_upload_s3_key":        m.FileUploadS3ObjectKey,
+			"registry_signature":        m.RegistrySignature,
+			"registry_signature_key_id": m.RegistrySignatureKeyID,
+			"registry_signed_at":        m.RegistrySignedAt,
+		},
+		"$max": bson.M{
+			"modified_at": m.ModifiedAt,
+		},
+	}
+
+	result, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		impl.Logger.Error("database update pdf by id error", slog.Any("error", err))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// UpdatePDFJobByID only saves the job which generates the next certificate,
+// leaving the current certificate to the job which generated it.
+func (impl ComicSubmissionStorerImpl) UpdatePDFJobByID(ctx context.Context, m *ComicSubmission) error {
+	filter := bson.M{"_id": m.ID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"pdf_status": m.PDFStatus,
+			"pdf_job_id": m.PDFJobID,
+			"pdf_error":  m.PDFError,
+		},
+		"$max": bson.M{
+			"modified_at": m.ModifiedAt,
+		},
+	}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update pdf job by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// CreateCommentByID appends the comment to the submission without saving the
+// rest of the submission, so it cannot overwrite what others changed since
+// the submission was read, ex: the certificate a job just generated.
+func (impl ComicSubmissionStorerImpl) CreateCommentByID(ctx context.Context, m *ComicSubmission, comment *SubmissionComment) error {
+	filter := bson.M{"_id": m.ID}
+
+	update := bson.M{
+		"$push": bson.M{
+			"comments": comment,
+		},
+		"$set": bson.M{
+			"modified_at":         m.ModifiedAt,
+			"modified_by_user_id": m.ModifiedByUserID,
+		},
+	}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database create comment by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// UpdateUserByID only saves the customer the submission belongs to.
+func (impl ComicSubmissionStorerImpl) UpdateUserByID(ctx context.Context, m *ComicSubmission) error {
+	filter := bson.M{"_id": m.ID}
+
+	update := bson.M{
+		"$set": bson.M{
+			"user_id":             m.UserID,
+			"user":                m.User,
+			"modified_at":         m.ModifiedAt,
+			"modified_by_user_id": m.ModifiedByUserID,
+		},
+	}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update user by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
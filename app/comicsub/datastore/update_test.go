@@ -0,0 +1,80 @@
+package datastore
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"golang.org/x/exp/slog"
+)
+
+func TestUpdatePDFByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("only the latest job saves its certificate", func(mt *mtest.T) {
+		impl := ComicSubmissionStorerImpl{Logger: slog.New(slog.NewTextHandler(os.Stderr)), Collection: mt.Coll}
+		jobID := primitive.NewObjectID()
+		m := &ComicSubmission{
+			ID:                    primitive.NewObjectID(),
+			PDFStatus:             PDFStatusReady,
+			PDFJobID:              jobID,
+			FileUploadS3ObjectKey: "uploads/new.pdf",
+		}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		ok, err := impl.UpdatePDFByID(context.Background(), m, jobID)
+		if err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		if ok {
+			t.Error("update of a superseded job was reported as saved")
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if id := update.Lookup("q", "pdf_job_id").ObjectID(); id != jobID {
+			t.Errorf("got pdf_job_id filter %v but was expecting %v", id, jobID)
+		}
+		if _, err := update.Lookup("u", "$set").Document().LookupErr("pdf_job_id"); err == nil {
+			t.Error("pdf_job_id was written by the job")
+		}
+	})
+
+	mt.Run("latest job", func(mt *mtest.T) {
+		impl := ComicSubmissionStorerImpl{Logger: slog.New(slog.NewTextHandler(os.Stderr)), Collection: mt.Coll}
+		jobID := primitive.NewObjectID()
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		ok, err := impl.UpdatePDFByID(context.Background(), &ComicSubmission{ID: primitive.NewObjectID()}, jobID)
+		if err != nil || !ok {
+			t.Errorf("got %v and %v", ok, err)
+		}
+	})
+}
+
+func TestCreateCommentByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("only the comment is saved", func(mt *mtest.T) {
+		impl := ComicSubmissionStorerImpl{Logger: slog.New(slog.NewTextHandler(os.Stderr)), Collection: mt.Coll}
+		m := &ComicSubmission{ID: primitive.NewObjectID(), PDFStatus: PDFStatusPending}
+		comment := &SubmissionComment{ID: primitive.NewObjectID(), Content: "Looks good."}
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		if err := impl.CreateCommentByID(context.Background(), m, comment); err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if content := update.Lookup("u", "$push", "comments", "content").StringValue(); content != comment.Content {
+			t.Errorf("got pushed comment %q but was expecting %q", content, comment.Content)
+		}
+		if _, err := update.Lookup("u", "$set").Document().LookupErr("pdf_status"); err == nil {
+			t.Error("pdf_status was written with the comment")
+		}
+	})
+}
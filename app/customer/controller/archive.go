@@ -7,11 +7,16 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 func (impl *CustomerControllerImpl) ArchiveByID(ctx context.Context, id primitive.ObjectID) (*user_s.User, error) {
+	if err := permission.Check(ctx, permission.CustomerManage); err != nil {
+		return nil, err
+	}
+
 	// // Extract from our session the following data.
 	// userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
 
@@ -25,6 +30,9 @@ func (impl *CustomerControllerImpl) ArchiveByID(ctx context.Context, id primitiv
 		impl.Logger.Warn("user does not exist validation error")
 		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
 	}
+	if err := permission.CheckOrganization(ctx, ou.OrganizationID); err != nil {
+		return nil, err
+	}
 
 	ou.ModifiedAt = time.Now()
 	ou.Status = user_s.UserStatusArchived
@@ -33,5 +41,10 @@ func (impl *CustomerControllerImpl) ArchiveByID(ctx context.Context, id primitiv
 		impl.Logger.Error("user update by id error", slog.Any("error", err))
 		return nil, err
 	}
+
+	// Archived users must not stay logged in.
+	if err := impl.SessionController.RevokeAllByUserID(ctx, ou.ID, ""); err != nil {
+		return nil, err
+	}
 	return ou, nil
 }
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
@@ -61,6 +62,10 @@ func (impl *CustomerControllerImpl) userFromCreateRequest(requestData *CustomerC
 }
 
 func (impl *CustomerControllerImpl) Create(ctx context.Context, requestData *CustomerCreateRequestIDO) (*user_s.User, error) {
+	if err := permission.Check(ctx, permission.CustomerManage); err != nil {
+		return nil, err
+	}
+
 	m, err := impl.userFromCreateRequest(requestData)
 	if err != nil {
 		return nil, err
@@ -5,9 +5,15 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
 )
 
 func (impl *CustomerControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	if err := permission.Check(ctx, permission.CustomerManage); err != nil {
+		return err
+	}
+
 	// STEP 1: Lookup the record or error.
 	customer, err := impl.GetByID(ctx, id)
 	if err != nil {
@@ -24,5 +30,5 @@ func (impl *CustomerControllerImpl) DeleteByID(ctx context.Context, id primitive
 		impl.Logger.Error("database delete by id error", slog.Any("error", err))
 		return err
 	}
-	return nil
+	return impl.SessionController.RevokeAllByUserID(ctx, id, "")
 }
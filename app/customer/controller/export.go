@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+)
+
+// ExportByFilter calls the function with every customer of the filter while
+// they are read from the database, scoped like `ListByFilter`.
+func (c *CustomerControllerImpl) ExportByFilter(ctx context.Context, f *user_s.UserListFilter, fn func(m *user_s.User) error) error {
+	if err := permission.Check(ctx, permission.CustomerManage); err != nil {
+		return err
+	}
+
+	// Apply filtering based on ownership and role.
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		f.OrganizationID = organizationID
+	}
+
+	f.Role = user_s.UserRoleCustomer // Manditory
+
+	err := c.UserStorer.ExportByFilter(ctx, f, func(m *user_s.User) error {
+		// Never let the secrets of the customer leave our system.
+		m.PasswordHashAlgorithm = ""
+		m.PasswordHash = ""
+		m.EmailVerificationCode = ""
+		return fn(m)
+	})
+	if err != nil {
+		c.Logger.Error("database export by filter error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -3,20 +3,19 @@ package controller
 import (
 	"context"
 
-	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
-	"github.com/LuchaComics/cps-backend/config/constants"
 )
 
 func (c *CustomerControllerImpl) ListByFilter(ctx context.Context, f *user_s.UserListFilter) (*user_s.UserListResult, error) {
-	// // Extract from our session the following data.
-	organizationID := ctx.Value(constants.SessionUserOrganizationID).(primitive.ObjectID)
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
+	if err := permission.Check(ctx, permission.CustomerManage); err != nil {
+		return nil, err
+	}
 
 	// Apply filtering based on ownership and role.
-	if userRole == user_s.UserRoleRetailer {
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
 		f.OrganizationID = organizationID
 	}
 
@@ -7,11 +7,16 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config/constants"
 )
 
 func (c *CustomerControllerImpl) CreateComment(ctx context.Context, customerID primitive.ObjectID, content string) (*user_s.User, error) {
+	if err := permission.Check(ctx, permission.CustomerManage); err != nil {
+		return nil, err
+	}
+
 	// Fetch the original customer.
 	s, err := c.UserStorer.GetByID(ctx, customerID)
 	if err != nil {
@@ -21,6 +26,9 @@ func (c *CustomerControllerImpl) CreateComment(ctx context.Context, customerID p
 	if s == nil {
 		return nil, nil
 	}
+	if err := permission.CheckOrganization(ctx, s.OrganizationID); err != nil {
+		return nil, err
+	}
 
 	// Create our comment.
 	comment := &user_s.UserComment{
@@ -7,12 +7,17 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 func (impl *CustomerControllerImpl) UpdateByID(ctx context.Context, nu *user_s.User) (*user_s.User, error) {
+	if err := permission.Check(ctx, permission.CustomerManage); err != nil {
+		return nil, err
+	}
+
 	// Extract from our session the following data.
 	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
 	userName, _ := ctx.Value(constants.SessionUserName).(string)
@@ -29,9 +34,16 @@ func (impl *CustomerControllerImpl) UpdateByID(ctx context.Context, nu *user_s.U
 		impl.Logger.Warn("user does not exist validation error")
 		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
 	}
+	if err := permission.CheckOrganization(ctx, ou.OrganizationID); err != nil {
+		return nil, err
+	}
 
-	ou.OrganizationID = orgID
-	ou.OrganizationName = orgName
+	// Our staff keeps the customer in its organization while everyone else
+	// may only have customers in their own.
+	if _, ok := permission.OrganizationScope(ctx); ok {
+		ou.OrganizationID = orgID
+		ou.OrganizationName = orgName
+	}
 	ou.FirstName = nu.FirstName
 	ou.LastName = nu.LastName
 	ou.Name = fmt.Sprintf("%s %s", nu.FirstName, nu.LastName)
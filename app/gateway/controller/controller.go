@@ -2,17 +2,19 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
 	"github.com/LuchaComics/cps-backend/adapter/cache/redis"
 	mg "github.com/LuchaComics/cps-backend/adapter/emailer/mailgun"
+	audit_s "github.com/LuchaComics/cps-backend/app/audit/datastore"
 	gateway_s "github.com/LuchaComics/cps-backend/app/gateway/datastore"
 	organization_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	session_c "github.com/LuchaComics/cps-backend/app/session/controller"
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config"
 	"github.com/LuchaComics/cps-backend/provider/jwt"
@@ -32,6 +34,18 @@ type GatewayController interface {
 	Profile(ctx context.Context) (*user_s.User, error)
 	ProfileUpdate(ctx context.Context, nu *user_s.User) error
 	ProfileChangePassword(ctx context.Context, req *ProfileChangePasswordRequestIDO) error
+	LoginOTP(ctx context.Context, req *LoginOTPRequestIDO) (*gateway_s.LoginResponseIDO, error)
+	LoginOTPEnroll(ctx context.Context, challengeToken string) (*OTPEnrollResponseIDO, error)
+	ProfileOTP(ctx context.Context) (*OTPStatusResponseIDO, error)
+	ProfileOTPEnroll(ctx context.Context) (*OTPEnrollResponseIDO, error)
+	ProfileOTPConfirm(ctx context.Context, req *OTPCodeRequestIDO) (*OTPRecoveryCodesResponseIDO, error)
+	ProfileOTPRegenerateRecoveryCodes(ctx context.Context, req *OTPCodeRequestIDO) (*OTPRecoveryCodesResponseIDO, error)
+	ProfileOTPDisable(ctx context.Context, req *OTPCodeRequestIDO) error
+	ProfileSessions(ctx context.Context) ([]*session_s.Session, error)
+	ProfileRevokeSessions(ctx context.Context) error
+	LoginUnlock(ctx context.Context, code string) error
+	UnlockLoginByUserID(ctx context.Context, id primitive.ObjectID) error
+	JWKS(ctx context.Context) (*jwt.JWKS, error)
 	//TODO: Add more...
 }
 
@@ -45,6 +59,12 @@ type GatewayControllerImpl struct {
 	Emailer            mg.Emailer
 	UserStorer         user_s.UserStorer
 	OrganizationStorer organization_s.OrganizationStorer
+	SessionController  session_c.SessionController
+	AuditEventStorer   audit_s.EventStorer
+
+	// dummyPasswordHash is compared against when the email has no account so
+	// the login takes as long as for an account.
+	dummyPasswordHash string
 }
 
 func NewController(
@@ -57,6 +77,8 @@ func NewController(
 	emailer mg.Emailer,
 	usr_storer user_s.UserStorer,
 	org_storer organization_s.OrganizationStorer,
+	session_controller session_c.SessionController,
+	audit_storer audit_s.EventStorer,
 ) GatewayController {
 	s := &GatewayControllerImpl{
 		Config:             appCfg,
@@ -68,6 +90,8 @@ func NewController(
 		Emailer:            emailer,
 		UserStorer:         usr_storer,
 		OrganizationStorer: org_storer,
+		SessionController:  session_controller,
+		AuditEventStorer:   audit_storer,
 	}
 	s.Logger.Debug("gateway controller initialization started...")
 
@@ -77,28 +101,18 @@ func NewController(
 		log.Fatal(err) // We terminate app here b/c dependency injection not allowed to fail, so fail here at startup of dynamodb.
 	}
 
+	dummyPasswordHash, err := passwordp.GenerateHashFromPassword(uuidp.NewUUID())
+	if err != nil {
+		log.Fatal(err)
+	}
+	s.dummyPasswordHash = dummyPasswordHash
+
 	s.Logger.Debug("gateway controller initialized")
 	return s
 }
 
+// GetUserBySessionID returns the user of the session or nil if the session
+// expired.
 func (impl *GatewayControllerImpl) GetUserBySessionID(ctx context.Context, sessionID string) (*user_s.User, error) {
-	impl.Logger.Debug("gateway controller initialization started...")
-
-	userBytes, err := impl.Cache.Get(ctx, sessionID)
-	if err != nil {
-		return nil, err
-	}
-	if userBytes == nil {
-		impl.Logger.Warn("record not found")
-		return nil, errors.New("record not found")
-	}
-	var user user_s.User
-	err = json.Unmarshal(userBytes, &user)
-	if err != nil {
-		impl.Logger.Error("unmarshalling failed", slog.Any("err", err))
-		return nil, err
-	}
-
-	impl.Logger.Debug("gateway controller initialized")
-	return &user, nil
+	return impl.SessionController.GetUser(ctx, sessionID)
 }
@@ -76,3 +76,38 @@ func (impl *GatewayControllerImpl) SendForgotPasswordEmail(email, verificationCo
 	}
 	return nil
 }
+
+func (impl *GatewayControllerImpl) SendLoginLockedEmail(email, unlockCode, firstName string) error {
+	fp := path.Join("templates", "login_locked.html")
+	tmpl, err := template.ParseFiles(fp)
+	if err != nil {
+		impl.Logger.Error("parsing error", slog.Any("error", err))
+		return err
+	}
+
+	var processed bytes.Buffer
+
+	// Render the HTML template with our data.
+	data := struct {
+		Email          string
+		UnlockLink     string
+		FirstName      string
+		LockoutMinutes int
+	}{
+		Email:          email,
+		UnlockLink:     "https://" + impl.Emailer.GetDomainName() + "/login-unlock?q=" + unlockCode,
+		FirstName:      firstName,
+		LockoutMinutes: impl.Config.Login.LockoutMinutes,
+	}
+	if err := tmpl.Execute(&processed, data); err != nil {
+		impl.Logger.Error("template execution error", slog.Any("error", err))
+		return err
+	}
+	body := processed.String() // DEVELOPERS NOTE: Convert our long sequence of data into a string.
+
+	if err := impl.Emailer.Send(context.Background(), impl.Emailer.GetSenderEmail(), "Your account was locked", email, body); err != nil {
+		impl.Logger.Error("sending error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"context"
+
+	"github.com/LuchaComics/cps-backend/provider/jwt"
+)
+
+// JWKS returns the public keys which verify the tokens we sign.
+func (impl *GatewayControllerImpl) JWKS(ctx context.Context) (*jwt.JWKS, error) {
+	return impl.JWT.JWKS(), nil
+}
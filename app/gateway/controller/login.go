@@ -2,13 +2,14 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"strings"
 	"time"
 
 	"golang.org/x/exp/slog"
 
 	gateway_s "github.com/LuchaComics/cps-backend/app/gateway/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
@@ -17,6 +18,12 @@ func (impl *GatewayControllerImpl) Login(ctx context.Context, email, password st
 	email = strings.ToLower(email)
 	password = strings.ReplaceAll(password, " ", "")
 
+	// Stop guessing once the account or IP address failed too many times.
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	if err := impl.checkLoginAllowed(ctx, email, ipAddress); err != nil {
+		return nil, err
+	}
+
 	// Lookup the user in our database, else return a `400 Bad Request` error.
 	u, err := impl.UserStorer.GetByEmail(ctx, email)
 	if err != nil {
@@ -25,14 +32,15 @@ func (impl *GatewayControllerImpl) Login(ctx context.Context, email, password st
 	}
 	if u == nil {
 		impl.Logger.Warn("user does not exist validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("email", "does not exist")
+		impl.Password.ComparePasswordAndHash(password, impl.dummyPasswordHash)
+		return nil, impl.loginFailed(ctx, email, ipAddress, nil)
 	}
 
 	// Verify the inputted password and hashed password match.
 	passwordMatch, _ := impl.Password.ComparePasswordAndHash(password, u.PasswordHash)
 	if passwordMatch == false {
 		impl.Logger.Warn("password check validation error")
-		return nil, httperror.NewForBadRequestWithSingleField("password", "password do not match with record")
+		return nil, impl.loginFailed(ctx, email, ipAddress, u)
 	}
 
 	// Enforce the verification code of the email.
@@ -41,9 +49,24 @@ func (impl *GatewayControllerImpl) Login(ctx context.Context, email, password st
 		return nil, httperror.NewForBadRequestWithSingleField("email", "was not verified")
 	}
 
-	uBin, err := json.Marshal(u)
+	// Users with two-factor authentication get a challenge to answer with a
+	// code instead of a session.
+	isOTPRequired, err := impl.isOTPRequired(ctx, u)
 	if err != nil {
-		impl.Logger.Error("marshalling error", slog.Any("err", err))
+		return nil, err
+	}
+	if u.OTPEnabled || isOTPRequired {
+		return impl.newOTPChallenge(ctx, u)
+	}
+
+	return impl.newSession(ctx, u)
+}
+
+// newSession starts the session of the user and returns its tokens. It is
+// only called once every factor of the login passed so their failed logins
+// are forgotten.
+func (impl *GatewayControllerImpl) newSession(ctx context.Context, u *user_s.User) (*gateway_s.LoginResponseIDO, error) {
+	if err := impl.loginSucceeded(ctx, u.Email); err != nil {
 		return nil, err
 	}
 
@@ -52,16 +75,13 @@ func (impl *GatewayControllerImpl) Login(ctx context.Context, email, password st
 	rtExpiry := 14 * 24 * time.Hour
 
 	// Start our session using an access and refresh token.
-	sessionUUID := impl.UUID.NewUUID()
-
-	err = impl.Cache.SetWithExpiry(ctx, sessionUUID, uBin, rtExpiry)
+	sessionUUID, refreshTokenID, err := impl.SessionController.Start(ctx, u, rtExpiry)
 	if err != nil {
-		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
 		return nil, err
 	}
 
 	// Generate our JWT token.
-	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, err := impl.JWT.GenerateJWTTokenPair(sessionUUID, atExpiry, rtExpiry)
+	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, err := impl.JWT.GenerateJWTTokenPair(sessionUUID, refreshTokenID, atExpiry, rtExpiry)
 	if err != nil {
 		impl.Logger.Error("jwt generate pairs error", slog.Any("err", err))
 		return nil, err
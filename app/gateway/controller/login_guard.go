@@ -0,0 +1,254 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	audit_s "github.com/LuchaComics/cps-backend/app/audit/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+const (
+	loginFailuresCacheKeyPrefix   = "login_failures:"
+	loginIPFailuresCacheKeyPrefix = "login_failures_ip:"
+	loginLockCacheKeyPrefix       = "login_lock:"
+	loginUnlockCacheKeyPrefix     = "login_unlock:"
+
+	// loginMaxDelay caps how long a failed login waits before responding.
+	loginMaxDelay = 5 * time.Second
+)
+
+// DEVELOPERS NOTE:
+// The same errors are returned whether or not the email belongs to an
+// account, and emails without an account are counted and locked the same
+// way, so the login cannot be used to find out who has an account.
+var (
+	errInvalidCredentials = httperror.NewForBadRequestWithSingleField("message", "invalid credentials")
+	errLoginLocked        = httperror.NewForSingleField(http.StatusTooManyRequests, "message", "too many failed login attempts, please try again later")
+)
+
+func (impl *GatewayControllerImpl) loginLockout() time.Duration {
+	return time.Duration(impl.Config.Login.LockoutMinutes) * time.Minute
+}
+
+// checkLoginAllowed returns an error if the account or the IP address is
+// locked out.
+func (impl *GatewayControllerImpl) checkLoginAllowed(ctx context.Context, email string, ipAddress string) error {
+	ipFailures, err := impl.Cache.Get(ctx, loginIPFailuresCacheKeyPrefix+ipAddress)
+	if err != nil {
+		impl.Logger.Error("cache get error", slog.Any("err", err))
+		return err
+	}
+	if count, _ := strconv.ParseInt(string(ipFailures), 10, 64); count >= impl.Config.Login.MaxFailedAttemptsPerIP {
+		impl.Logger.Warn("login from locked ip address", slog.String("ip_address", ipAddress))
+		return errLoginLocked
+	}
+
+	locked, err := impl.Cache.Get(ctx, loginLockCacheKeyPrefix+email)
+	if err != nil {
+		impl.Logger.Error("cache get error", slog.Any("err", err))
+		return err
+	}
+	if locked != nil {
+		impl.Logger.Warn("login to locked account", slog.String("email", email))
+		return errLoginLocked
+	}
+	return nil
+}
+
+// loginFailed counts the failed login against the account and the IP address,
+// locking them once they reach their limit, and returns the error for the
+// user. The user is nil if the email has no account.
+func (impl *GatewayControllerImpl) loginFailed(ctx context.Context, email string, ipAddress string, u *user_s.User) error {
+	lockout := impl.loginLockout()
+	ipCount, err := impl.Cache.IncrementWithExpiry(ctx, loginIPFailuresCacheKeyPrefix+ipAddress, lockout)
+	if err != nil {
+		return err
+	}
+	if ipCount == impl.Config.Login.MaxFailedAttemptsPerIP {
+		impl.recordAuditEvent(ctx, &audit_s.Event{
+			Type:      audit_s.EventTypeLoginLocked,
+			IPAddress: ipAddress,
+			Message:   "IP address locked after " + strconv.FormatInt(ipCount, 10) + " failed login attempts",
+		})
+	}
+
+	count, err := impl.Cache.IncrementWithExpiry(ctx, loginFailuresCacheKeyPrefix+email, lockout)
+	if err != nil {
+		return err
+	}
+	impl.Logger.Warn("login failed",
+		slog.String("email", email),
+		slog.String("ip_address", ipAddress),
+		slog.Int64("count", count))
+	if count >= impl.Config.Login.MaxFailedAttempts {
+		if err := impl.lockLogin(ctx, email, ipAddress, u, count); err != nil {
+			return err
+		}
+		return errLoginLocked
+	}
+
+	// Slow down whoever is guessing; the delay doubles with every failure.
+	select {
+	case <-time.After(loginFailureDelay(count)):
+	case <-ctx.Done():
+	}
+	return errInvalidCredentials
+}
+
+// loginFailureDelay returns how long to wait after the failed login, ex: none
+// after the first, then 500ms, 1s, 2s, up to `loginMaxDelay`.
+func loginFailureDelay(count int64) time.Duration {
+	if count < 2 {
+		return 0
+	}
+	if count > 6 { // Already past the cap; also keeps the shift small.
+		return loginMaxDelay
+	}
+	if d := 500 * time.Millisecond << (count - 2); d < loginMaxDelay {
+		return d
+	}
+	return loginMaxDelay
+}
+
+// lockLogin locks the account and emails the user a link to unlock it in case
+// it was them.
+func (impl *GatewayControllerImpl) lockLogin(ctx context.Context, email string, ipAddress string, u *user_s.User, count int64) error {
+	lockout := impl.loginLockout()
+	if err := impl.Cache.SetWithExpiry(ctx, loginLockCacheKeyPrefix+email, []byte("1"), lockout); err != nil {
+		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		return err
+	}
+	if err := impl.Cache.Delete(ctx, loginFailuresCacheKeyPrefix+email); err != nil {
+		impl.Logger.Error("cache delete error", slog.Any("err", err))
+		return err
+	}
+	if u == nil {
+		return nil
+	}
+
+	impl.recordAuditEvent(ctx, &audit_s.Event{
+		Type:      audit_s.EventTypeLoginLocked,
+		UserID:    u.ID,
+		Email:     email,
+		IPAddress: ipAddress,
+		Message:   "Account locked after " + strconv.FormatInt(count, 10) + " failed login attempts",
+	})
+
+	unlockCode := impl.UUID.NewUUID()
+	if err := impl.Cache.SetWithExpiry(ctx, loginUnlockCacheKeyPrefix+unlockCode, []byte(email), lockout); err != nil {
+		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		return err
+	}
+	if err := impl.SendLoginLockedEmail(email, unlockCode, u.FirstName); err != nil {
+		impl.Logger.Error("send login locked email error", slog.Any("err", err))
+	}
+	return nil
+}
+
+// loginSucceeded forgets the failed logins of the account.
+func (impl *GatewayControllerImpl) loginSucceeded(ctx context.Context, email string) error {
+	if err := impl.Cache.Delete(ctx, loginFailuresCacheKeyPrefix+email); err != nil {
+		impl.Logger.Error("cache delete error", slog.Any("err", err))
+		return err
+	}
+	return nil
+}
+
+func (impl *GatewayControllerImpl) unlockLogin(ctx context.Context, email string) error {
+	if err := impl.Cache.Delete(ctx, loginLockCacheKeyPrefix+email); err != nil {
+		impl.Logger.Error("cache delete error", slog.Any("err", err))
+		return err
+	}
+	return impl.loginSucceeded(ctx, email)
+}
+
+// LoginUnlock unlocks the account with the code of the email we sent when it
+// was locked.
+func (impl *GatewayControllerImpl) LoginUnlock(ctx context.Context, code string) error {
+	email, err := impl.Cache.Get(ctx, loginUnlockCacheKeyPrefix+code)
+	if err != nil {
+		impl.Logger.Error("cache get error", slog.Any("err", err))
+		return err
+	}
+	if email == nil {
+		return httperror.NewForBadRequestWithSingleField("code", "expired or does not exist")
+	}
+	u, err := impl.UserStorer.GetByEmail(ctx, string(email))
+	if err != nil {
+		impl.Logger.Error("database error", slog.Any("err", err))
+		return err
+	}
+	if u == nil {
+		return httperror.NewForBadRequestWithSingleField("code", "expired or does not exist")
+	}
+
+	if err := impl.unlockLogin(ctx, u.Email); err != nil {
+		return err
+	}
+	if err := impl.Cache.Delete(ctx, loginUnlockCacheKeyPrefix+code); err != nil {
+		impl.Logger.Error("cache delete error", slog.Any("err", err))
+		return err
+	}
+
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	impl.recordAuditEvent(ctx, &audit_s.Event{
+		Type:      audit_s.EventTypeLoginUnlocked,
+		UserID:    u.ID,
+		Email:     u.Email,
+		IPAddress: ipAddress,
+		Message:   "Account unlocked with the link of the email",
+	})
+	return nil
+}
+
+// UnlockLoginByUserID lets root lift the lockout of an account.
+func (impl *GatewayControllerImpl) UnlockLoginByUserID(ctx context.Context, id primitive.ObjectID) error {
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return err
+	}
+	u, err := impl.UserStorer.GetByID(ctx, id)
+	if err != nil {
+		impl.Logger.Error("database error", slog.Any("err", err))
+		return err
+	}
+	if u == nil {
+		return httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
+
+	if err := impl.unlockLogin(ctx, u.Email); err != nil {
+		return err
+	}
+
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userName, _ := ctx.Value(constants.SessionUserName).(string)
+	impl.recordAuditEvent(ctx, &audit_s.Event{
+		Type:            audit_s.EventTypeLoginUnlocked,
+		UserID:          u.ID,
+		Email:           u.Email,
+		IPAddress:       ipAddress,
+		Message:         "Account unlocked by staff",
+		CreatedByUserID: userID,
+		CreatedByName:   userName,
+	})
+	return nil
+}
+
+// recordAuditEvent saves the event to the audit trail; errors are only logged
+// as they must not fail the request.
+func (impl *GatewayControllerImpl) recordAuditEvent(ctx context.Context, m *audit_s.Event) {
+	m.ID = primitive.NewObjectID()
+	m.CreatedAt = time.Now()
+	if err := impl.AuditEventStorer.Create(ctx, m); err != nil {
+		impl.Logger.Error("audit event create error", slog.Any("error", err), slog.String("type", m.Type))
+	}
+}
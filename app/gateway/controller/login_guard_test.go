@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/adapter/cache/redis"
+	audit_s "github.com/LuchaComics/cps-backend/app/audit/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+type fakeCache struct {
+	redis.Cacher
+	values map[string][]byte
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.values[key], nil
+}
+
+func (c *fakeCache) GetDelete(ctx context.Context, key string) ([]byte, error) {
+	val := c.values[key]
+	delete(c.values, key)
+	return val, nil
+}
+
+func (c *fakeCache) SetWithExpiry(ctx context.Context, key string, val []byte, expiry time.Duration) error {
+	c.values[key] = val
+	return nil
+}
+
+func (c *fakeCache) Delete(ctx context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func (c *fakeCache) IncrementWithExpiry(ctx context.Context, key string, expiry time.Duration) (int64, error) {
+	count, _ := strconv.ParseInt(string(c.values[key]), 10, 64)
+	count++
+	c.values[key] = []byte(strconv.FormatInt(count, 10))
+	return count, nil
+}
+
+type fakeEventStorer struct {
+	audit_s.EventStorer
+	events []*audit_s.Event
+}
+
+func (s *fakeEventStorer) Create(ctx context.Context, m *audit_s.Event) error {
+	s.events = append(s.events, m)
+	return nil
+}
+
+func TestLoginLockout(t *testing.T) {
+	cfg := &config.Conf{}
+	cfg.Login.MaxFailedAttempts = 3
+	cfg.Login.MaxFailedAttemptsPerIP = 100
+	cfg.Login.LockoutMinutes = 15
+	cache := &fakeCache{values: map[string][]byte{}}
+	events := &fakeEventStorer{}
+	impl := &GatewayControllerImpl{
+		Config:           cfg,
+		Logger:           slog.New(slog.NewTextHandler(os.Stderr)),
+		Cache:            cache,
+		AuditEventStorer: events,
+	}
+
+	// Cancelled so the delays of the failures are skipped.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Emails without an account are locked like the others.
+	email, ip := "nobody@example.com", "127.0.0.1"
+	for i := int64(1); i < cfg.Login.MaxFailedAttempts; i++ {
+		if err := impl.loginFailed(ctx, email, ip, nil); err != errInvalidCredentials {
+			t.Fatalf("expected invalid credentials on attempt %v but got %v", i, err)
+		}
+		if err := impl.checkLoginAllowed(ctx, email, ip); err != nil {
+			t.Fatalf("expected login to be allowed after %v failures but got %v", i, err)
+		}
+	}
+	if err := impl.loginFailed(ctx, email, ip, nil); err != errLoginLocked {
+		t.Fatalf("expected lockout but got %v", err)
+	}
+	if err := impl.checkLoginAllowed(ctx, email, ip); err != errLoginLocked {
+		t.Errorf("expected login to be locked but got %v", err)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("expected no audit event for an email without an account but got %v", len(events.events))
+	}
+
+	if err := impl.unlockLogin(ctx, email); err != nil {
+		t.Fatal(err)
+	}
+	if err := impl.checkLoginAllowed(ctx, email, ip); err != nil {
+		t.Errorf("expected login to be allowed after unlock but got %v", err)
+	}
+
+	// The IP address is locked once it failed too many times across accounts.
+	cache.values[loginIPFailuresCacheKeyPrefix+ip] = []byte(strconv.FormatInt(cfg.Login.MaxFailedAttemptsPerIP-1, 10))
+	u := &user_s.User{ID: primitive.NewObjectID(), Email: "someone@example.com"}
+	if err := impl.loginFailed(ctx, u.Email, ip, u); err != errInvalidCredentials {
+		t.Fatalf("expected invalid credentials but got %v", err)
+	}
+	if err := impl.checkLoginAllowed(ctx, u.Email, ip); err != errLoginLocked {
+		t.Errorf("expected ip address to be locked but got %v", err)
+	}
+	if len(events.events) != 1 || events.events[0].Type != audit_s.EventTypeLoginLocked || events.events[0].IPAddress != ip {
+		t.Errorf("expected the ip address lockout to be audited but got %v", events.events)
+	}
+}
+
+func TestLoginFailureDelay(t *testing.T) {
+	expected := map[int64]time.Duration{
+		1:   0,
+		2:   500 * time.Millisecond,
+		3:   time.Second,
+		4:   2 * time.Second,
+		5:   4 * time.Second,
+		6:   loginMaxDelay,
+		100: loginMaxDelay,
+	}
+	for count, d := range expected {
+		if actual := loginFailureDelay(count); actual != d {
+			t.Errorf("expected delay %v after %v failures but got %v", d, count, actual)
+		}
+	}
+}
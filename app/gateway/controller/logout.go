@@ -3,18 +3,20 @@ package controller
 import (
 	"context"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
 	"github.com/LuchaComics/cps-backend/config/constants"
-	"golang.org/x/exp/slog"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 func (impl *GatewayControllerImpl) Logout(ctx context.Context) error {
+	// Requests made with an API key have no session to end.
+	if _, ok := ctx.Value(constants.SessionAPIKeyID).(primitive.ObjectID); ok {
+		return httperror.NewForBadRequestWithSingleField("message", "api keys cannot log out; revoke the key instead")
+	}
+
 	// Extract from our session the following data.
 	sessionID := ctx.Value(constants.SessionID).(string)
 
-	if err := impl.Cache.Delete(ctx, sessionID); err != nil {
-		impl.Logger.Error("cache delete error", slog.Any("err", err))
-		return err
-	}
-
-	return nil
+	return impl.SessionController.End(ctx, sessionID)
 }
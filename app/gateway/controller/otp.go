@@ -0,0 +1,348 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	gateway_s "github.com/LuchaComics/cps-backend/app/gateway/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+	"github.com/LuchaComics/cps-backend/utils/totp"
+)
+
+const (
+	// otpIssuer is the name authenticator apps show for our accounts.
+	otpIssuer = "CPS"
+
+	// otpChallengeExpiry is how long the user has to enter their code after
+	// entering their password.
+	otpChallengeExpiry = 5 * time.Minute
+
+	// otpChallengeMaxAttempts is how many wrong codes are accepted before the
+	// user has to enter their password again.
+	otpChallengeMaxAttempts = 5
+
+	otpRecoveryCodeCount = 10
+
+	otpChallengeCacheKeyPrefix = "otp_challenge:"
+)
+
+// otpChallenge is kept in our cache between the two steps of the login.
+type otpChallenge struct {
+	UserID   primitive.ObjectID `json:"user_id"`
+	Attempts int                `json:"attempts"`
+}
+
+// OTPEnrollResponseIDO holds the secret to add to an authenticator app,
+// either typed in or scanned from the `otpauth://` URI as a QR code.
+type OTPEnrollResponseIDO struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// OTPRecoveryCodesResponseIDO holds the recovery codes which are never shown
+// again; each can be used once instead of a code.
+type OTPRecoveryCodesResponseIDO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type OTPStatusResponseIDO struct {
+	Enabled                bool      `json:"enabled"`
+	EnabledAt              time.Time `json:"enabled_at,omitempty"`
+	Required               bool      `json:"required"`
+	RecoveryCodesRemaining int       `json:"recovery_codes_remaining"`
+}
+
+type OTPCodeRequestIDO struct {
+	Code string `json:"code"`
+}
+
+type LoginOTPRequestIDO struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// isOTPRequired returns true if the organization of the user requires their
+// role to use two-factor authentication.
+func (impl *GatewayControllerImpl) isOTPRequired(ctx context.Context, u *user_s.User) (bool, error) {
+	if u.OrganizationID.IsZero() {
+		return false, nil
+	}
+	org, err := impl.OrganizationStorer.GetByID(ctx, u.OrganizationID)
+	if err != nil {
+		impl.Logger.Error("database get by id error", slog.Any("err", err))
+		return false, err
+	}
+	return org != nil && org.IsOTPRequiredForRole(u.Role), nil
+}
+
+// newOTPChallenge returns the challenge token the user exchanges for a
+// session along with their code.
+func (impl *GatewayControllerImpl) newOTPChallenge(ctx context.Context, u *user_s.User) (*gateway_s.LoginResponseIDO, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		impl.Logger.Error("generate challenge token error", slog.Any("err", err))
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := impl.setOTPChallenge(ctx, token, &otpChallenge{UserID: u.ID}); err != nil {
+		return nil, err
+	}
+	return &gateway_s.LoginResponseIDO{
+		OTPRequired:              true,
+		OTPEnrollmentRequired:    !u.OTPEnabled,
+		ChallengeToken:           token,
+		ChallengeTokenExpiryTime: time.Now().Add(otpChallengeExpiry),
+	}, nil
+}
+
+func (impl *GatewayControllerImpl) setOTPChallenge(ctx context.Context, token string, c *otpChallenge) error {
+	cBin, err := json.Marshal(c)
+	if err != nil {
+		impl.Logger.Error("marshalling error", slog.Any("err", err))
+		return err
+	}
+	if err := impl.Cache.SetWithExpiry(ctx, otpChallengeCacheKeyPrefix+token, cBin, otpChallengeExpiry); err != nil {
+		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		return err
+	}
+	return nil
+}
+
+// getOTPChallenge returns the user of the challenge or a `400 Bad Request`
+// error if it expired.
+func (impl *GatewayControllerImpl) getOTPChallenge(ctx context.Context, token string) (*otpChallenge, *user_s.User, error) {
+	cBin, err := impl.Cache.Get(ctx, otpChallengeCacheKeyPrefix+token)
+	if err != nil {
+		impl.Logger.Error("cache get error", slog.Any("err", err))
+		return nil, nil, err
+	}
+	return impl.decodeOTPChallenge(ctx, cBin)
+}
+
+// consumeOTPChallenge is getOTPChallenge but the challenge is removed at the
+// same time so concurrent requests cannot answer it twice; it is put back
+// by whoever wants it answered again, ex: after a wrong code.
+func (impl *GatewayControllerImpl) consumeOTPChallenge(ctx context.Context, token string) (*otpChallenge, *user_s.User, error) {
+	cBin, err := impl.Cache.GetDelete(ctx, otpChallengeCacheKeyPrefix+token)
+	if err != nil {
+		impl.Logger.Error("cache get delete error", slog.Any("err", err))
+		return nil, nil, err
+	}
+	return impl.decodeOTPChallenge(ctx, cBin)
+}
+
+func (impl *GatewayControllerImpl) decodeOTPChallenge(ctx context.Context, cBin []byte) (*otpChallenge, *user_s.User, error) {
+	if cBin == nil {
+		return nil, nil, httperror.NewForBadRequestWithSingleField("challenge_token", "expired, please log in again")
+	}
+	var c otpChallenge
+	if err := json.Unmarshal(cBin, &c); err != nil {
+		impl.Logger.Error("unmarshalling failed", slog.Any("err", err))
+		return nil, nil, err
+	}
+
+	u, err := impl.UserStorer.GetByID(ctx, c.UserID)
+	if err != nil {
+		impl.Logger.Error("database error", slog.Any("err", err))
+		return nil, nil, err
+	}
+	if u == nil {
+		return nil, nil, httperror.NewForBadRequestWithSingleField("challenge_token", "expired, please log in again")
+	}
+	return &c, u, nil
+}
+
+// LoginOTPEnroll starts the enrollment of a user whose organization requires
+// two-factor authentication but who has not enrolled yet.
+func (impl *GatewayControllerImpl) LoginOTPEnroll(ctx context.Context, challengeToken string) (*OTPEnrollResponseIDO, error) {
+	_, u, err := impl.getOTPChallenge(ctx, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if u.OTPEnabled {
+		return nil, httperror.NewForBadRequestWithSingleField("message", "two-factor authentication is already enabled")
+	}
+	return impl.enrollOTP(ctx, u)
+}
+
+// LoginOTP completes the login with the code of the authenticator app or a
+// recovery code. If the user was enrolling then the code confirms the
+// enrollment and their recovery codes are returned along with the session.
+func (impl *GatewayControllerImpl) LoginOTP(ctx context.Context, req *LoginOTPRequestIDO) (*gateway_s.LoginResponseIDO, error) {
+	c, u, err := impl.consumeOTPChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrong codes count as failed logins so the lockout stops guessing codes
+	// as it stops guessing passwords.
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	if err := impl.checkLoginAllowed(ctx, u.Email, ipAddress); err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if u.OTPEnabled {
+		ok, err := impl.checkOTPCode(ctx, u, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, impl.otpChallengeFailed(ctx, req.ChallengeToken, c, u, ipAddress)
+		}
+	} else {
+		if u.OTPPendingSecret == "" {
+			if err := impl.setOTPChallenge(ctx, req.ChallengeToken, c); err != nil {
+				return nil, err
+			}
+			return nil, httperror.NewForBadRequestWithSingleField("message", "two-factor authentication must be enrolled first")
+		}
+		step, ok := totp.Validate(u.OTPPendingSecret, req.Code, time.Now(), 0)
+		if !ok {
+			return nil, impl.otpChallengeFailed(ctx, req.ChallengeToken, c, u, ipAddress)
+		}
+		if recoveryCodes, err = enableOTP(u, step); err != nil {
+			impl.Logger.Error("generate recovery codes error", slog.Any("err", err))
+			return nil, err
+		}
+		if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+			impl.Logger.Error("user update by id error", slog.Any("error", err))
+			return nil, err
+		}
+	}
+
+	res, err := impl.newSession(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+// otpChallengeFailed counts the wrong code against the challenge, the account
+// and the IP address and returns the error for the user; the consumed
+// challenge is put back unless out of attempts or locked out.
+func (impl *GatewayControllerImpl) otpChallengeFailed(ctx context.Context, token string, c *otpChallenge, u *user_s.User, ipAddress string) error {
+	c.Attempts++
+	impl.Logger.Warn("otp code validation error", slog.Any("user_id", c.UserID), slog.Int("attempts", c.Attempts))
+	if err := impl.loginFailed(ctx, u.Email, ipAddress, u); err != errInvalidCredentials {
+		return err
+	}
+	if c.Attempts >= otpChallengeMaxAttempts {
+		return httperror.NewForBadRequestWithSingleField("code", "too many wrong codes, please log in again")
+	}
+	if err := impl.setOTPChallenge(ctx, token, c); err != nil {
+		return err
+	}
+	return httperror.NewForBadRequestWithSingleField("code", "invalid value")
+}
+
+// enrollOTP saves a new secret for the user which only takes effect once
+// confirmed with a code, so an abandoned enrollment does not lock them out.
+func (impl *GatewayControllerImpl) enrollOTP(ctx context.Context, u *user_s.User) (*OTPEnrollResponseIDO, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		impl.Logger.Error("generate otp secret error", slog.Any("err", err))
+		return nil, err
+	}
+	u.OTPPendingSecret = secret
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.Error("user update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &OTPEnrollResponseIDO{
+		Secret: secret,
+		URI:    totp.URI(otpIssuer, u.Email, secret),
+	}, nil
+}
+
+// enableOTP turns on two-factor authentication with the pending secret which
+// was confirmed by a code of the step; the new recovery codes are returned.
+func enableOTP(u *user_s.User, step int64) ([]string, error) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	u.OTPEnabled = true
+	u.OTPEnabledAt = time.Now()
+	u.OTPSecret = u.OTPPendingSecret
+	u.OTPPendingSecret = ""
+	u.OTPLastUsedStep = step
+	u.OTPRecoveryCodeHashes = hashes
+	return codes, nil
+}
+
+// checkOTPCode returns true if the code is the current code of the user or
+// one of their unused recovery codes. The code is marked as used in the
+// database at the same time, so of two requests with the same code only one
+// succeeds, and on the user so a later save keeps it used.
+func (impl *GatewayControllerImpl) checkOTPCode(ctx context.Context, u *user_s.User, code string) (bool, error) {
+	if step, ok := totp.Validate(u.OTPSecret, code, time.Now(), u.OTPLastUsedStep); ok {
+		ok, err := impl.UserStorer.UpdateOTPLastUsedStepByID(ctx, u.ID, step)
+		if err != nil {
+			impl.Logger.Error("user update otp last used step by id error", slog.Any("error", err))
+			return false, err
+		}
+		if ok {
+			u.OTPLastUsedStep = step
+		}
+		return ok, nil
+	}
+
+	hash := hashRecoveryCode(code)
+	for i, h := range u.OTPRecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			ok, err := impl.UserStorer.DeleteOTPRecoveryCodeByID(ctx, u.ID, h)
+			if err != nil {
+				impl.Logger.Error("user delete otp recovery code by id error", slog.Any("error", err))
+				return false, err
+			}
+			if !ok {
+				return false, nil
+			}
+			u.OTPRecoveryCodeHashes = append(u.OTPRecoveryCodeHashes[:i:i], u.OTPRecoveryCodeHashes[i+1:]...)
+			impl.Logger.Info("otp recovery code used",
+				slog.Any("user_id", u.ID),
+				slog.Int("remaining", len(u.OTPRecoveryCodeHashes)))
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newRecoveryCodes returns new recovery codes, ex: `a2b3c-4d5e6`, along with
+// the hashes we save. A fast hash is enough as the codes are random.
+func newRecoveryCodes() (codes []string, hashes []string, err error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < otpRecoveryCodeCount; i++ {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		s := strings.ToLower(encoding.EncodeToString(b))[:10]
+		code := s[:5] + "-" + s[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
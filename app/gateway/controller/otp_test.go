@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/totp"
+)
+
+type fakeUserStorer struct {
+	user_s.UserStorer
+	users map[primitive.ObjectID]*user_s.User
+}
+
+// GetByID returns a copy of the user as every request reads its own.
+func (s *fakeUserStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*user_s.User, error) {
+	u, ok := s.users[id]
+	if !ok {
+		return nil, nil
+	}
+	m := *u
+	return &m, nil
+}
+
+func (s *fakeUserStorer) UpdateOTPLastUsedStepByID(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	u := s.users[id]
+	if u.OTPLastUsedStep >= step {
+		return false, nil
+	}
+	u.OTPLastUsedStep = step
+	return true, nil
+}
+
+func (s *fakeUserStorer) DeleteOTPRecoveryCodeByID(ctx context.Context, id primitive.ObjectID, recoveryCodeHash string) (bool, error) {
+	u := s.users[id]
+	for i, h := range u.OTPRecoveryCodeHashes {
+		if h == recoveryCodeHash {
+			u.OTPRecoveryCodeHashes = append(u.OTPRecoveryCodeHashes[:i:i], u.OTPRecoveryCodeHashes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestCheckOTPCode(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &user_s.User{ID: primitive.NewObjectID(), OTPPendingSecret: secret}
+	codes, err := enableOTP(u, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.OTPEnabled || u.OTPSecret != secret || u.OTPPendingSecret != "" || len(codes) != otpRecoveryCodeCount {
+		t.Fatalf("expected otp to be enabled with %v recovery codes but got %+v", otpRecoveryCodeCount, u)
+	}
+	storer := &fakeUserStorer{users: map[primitive.ObjectID]*user_s.User{u.ID: u}}
+	impl := &GatewayControllerImpl{Logger: slog.New(slog.NewTextHandler(os.Stderr)), UserStorer: storer}
+	ctx := context.Background()
+	u, _ = storer.GetByID(ctx, u.ID)
+
+	// A code of the authenticator app can only be used once.
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	if ok, err := impl.checkOTPCode(ctx, u, code); !ok || err != nil {
+		t.Errorf("expected %v to be valid but got %v", code, err)
+	}
+	if ok, _ := impl.checkOTPCode(ctx, u, code); ok {
+		t.Errorf("expected %v to not be valid twice", code)
+	}
+
+	// So can a recovery code, with or without its dash.
+	if ok, err := impl.checkOTPCode(ctx, u, " "+codes[3][:5]+codes[3][6:]+" "); !ok || err != nil {
+		t.Errorf("expected recovery code %v to be valid but got %v", codes[3], err)
+	}
+	if ok, _ := impl.checkOTPCode(ctx, u, codes[3]); ok {
+		t.Errorf("expected recovery code %v to not be valid twice", codes[3])
+	}
+	if len(u.OTPRecoveryCodeHashes) != otpRecoveryCodeCount-1 {
+		t.Errorf("expected %v recovery codes left but got %v", otpRecoveryCodeCount-1, len(u.OTPRecoveryCodeHashes))
+	}
+	if ok, err := impl.checkOTPCode(ctx, u, codes[0]); !ok || err != nil {
+		t.Errorf("expected recovery code %v to be valid but got %v", codes[0], err)
+	}
+}
+
+func TestCheckOTPCodeConcurrently(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &user_s.User{ID: primitive.NewObjectID(), OTPPendingSecret: secret}
+	codes, err := enableOTP(u, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	storer := &fakeUserStorer{users: map[primitive.ObjectID]*user_s.User{u.ID: u}}
+	impl := &GatewayControllerImpl{Logger: slog.New(slog.NewTextHandler(os.Stderr)), UserStorer: storer}
+	ctx := context.Background()
+
+	// Both requests read the user before either used the code.
+	first, _ := storer.GetByID(ctx, u.ID)
+	second, _ := storer.GetByID(ctx, u.ID)
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	for _, c := range []string{code, codes[0]} {
+		if ok, err := impl.checkOTPCode(ctx, first, c); !ok || err != nil {
+			t.Errorf("expected %v to be valid but got %v", c, err)
+		}
+		if ok, _ := impl.checkOTPCode(ctx, second, c); ok {
+			t.Errorf("expected %v to not be valid for a second request", c)
+		}
+	}
+
+	// Only one request gets to answer the challenge.
+	impl.Cache = &fakeCache{values: map[string][]byte{}}
+	if err := impl.setOTPChallenge(ctx, "challenge", &otpChallenge{UserID: u.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := impl.consumeOTPChallenge(ctx, "challenge"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := impl.consumeOTPChallenge(ctx, "challenge"); err == nil {
+		t.Error("expected the challenge to be answered once")
+	}
+}
+
+func TestLoginOTPCountsFailedLogins(t *testing.T) {
+	cfg := &config.Conf{}
+	cfg.Login.MaxFailedAttempts = 100
+	cfg.Login.MaxFailedAttemptsPerIP = 100
+	cfg.Login.LockoutMinutes = 15
+	cache := &fakeCache{values: map[string][]byte{}}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &user_s.User{ID: primitive.NewObjectID(), Email: "someone@example.com", OTPEnabled: true, OTPSecret: secret}
+	impl := &GatewayControllerImpl{
+		Config:           cfg,
+		Logger:           slog.New(slog.NewTextHandler(os.Stderr)),
+		Cache:            cache,
+		UserStorer:       &fakeUserStorer{users: map[primitive.ObjectID]*user_s.User{u.ID: u}},
+		AuditEventStorer: &fakeEventStorer{},
+	}
+
+	// Cancelled so the delays of the failures are skipped.
+	ip := "127.0.0.1"
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), constants.SessionIPAddress, ip))
+	cancel()
+
+	token := "challenge"
+	if err := impl.setOTPChallenge(ctx, token, &otpChallenge{UserID: u.ID}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= otpChallengeMaxAttempts; i++ {
+		if _, err := impl.LoginOTP(ctx, &LoginOTPRequestIDO{ChallengeToken: token, Code: "000000"}); err == nil {
+			t.Fatalf("expected attempt %v to fail", i)
+		}
+	}
+	expected := []byte("5")
+	if failures := cache.values[loginFailuresCacheKeyPrefix+u.Email]; string(failures) != string(expected) {
+		t.Errorf("expected %s failures of the account but got %s", expected, failures)
+	}
+	if failures := cache.values[loginIPFailuresCacheKeyPrefix+ip]; string(failures) != string(expected) {
+		t.Errorf("expected %s failures of the ip address but got %s", expected, failures)
+	}
+	if _, ok := cache.values[otpChallengeCacheKeyPrefix+token]; ok {
+		t.Error("expected the challenge to be dropped once out of attempts")
+	}
+
+	// A locked account cannot answer its challenge.
+	if err := impl.setOTPChallenge(ctx, token, &otpChallenge{UserID: u.ID}); err != nil {
+		t.Fatal(err)
+	}
+	cache.values[loginLockCacheKeyPrefix+u.Email] = []byte("1")
+	code, _ := totp.Code(secret, totp.Step(time.Now()))
+	if _, err := impl.LoginOTP(ctx, &LoginOTPRequestIDO{ChallengeToken: token, Code: code}); err != errLoginLocked {
+		t.Errorf("expected lockout but got %v", err)
+	}
+}
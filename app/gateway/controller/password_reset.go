@@ -40,5 +40,6 @@ func (impl *GatewayControllerImpl) PasswordReset(ctx context.Context, code strin
 		return err
 	}
 
-	return nil
+	// Whoever knew the old password must not stay logged in.
+	return impl.SessionController.RevokeAllByUserID(ctx, u.ID, "")
 }
@@ -133,5 +133,9 @@ func (impl *GatewayControllerImpl) ProfileChangePassword(ctx context.Context, re
 		impl.Logger.Error("user update by id error", slog.Any("error", err))
 		return err
 	}
-	return nil
+
+	// Whoever knew the old password must not stay logged in; the user stays
+	// logged in where they changed it.
+	sessionID, _ := ctx.Value(constants.SessionID).(string)
+	return impl.SessionController.RevokeAllByUserID(ctx, u.ID, sessionID)
 }
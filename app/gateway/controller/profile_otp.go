@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+	"github.com/LuchaComics/cps-backend/utils/totp"
+)
+
+// profileUser returns the logged in user from our database.
+func (impl *GatewayControllerImpl) profileUser(ctx context.Context) (*user_s.User, error) {
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	u, err := impl.UserStorer.GetByID(ctx, userID)
+	if err != nil {
+		impl.Logger.Error("database error", slog.Any("err", err))
+		return nil, err
+	}
+	if u == nil {
+		impl.Logger.Warn("user does not exist validation error")
+		return nil, httperror.NewForBadRequestWithSingleField("id", "does not exist")
+	}
+	return u, nil
+}
+
+func (impl *GatewayControllerImpl) ProfileOTP(ctx context.Context) (*OTPStatusResponseIDO, error) {
+	u, err := impl.profileUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	isOTPRequired, err := impl.isOTPRequired(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	return &OTPStatusResponseIDO{
+		Enabled:                u.OTPEnabled,
+		EnabledAt:              u.OTPEnabledAt,
+		Required:               isOTPRequired,
+		RecoveryCodesRemaining: len(u.OTPRecoveryCodeHashes),
+	}, nil
+}
+
+// ProfileOTPEnroll returns a new secret for the logged in user to confirm
+// with `ProfileOTPConfirm`.
+func (impl *GatewayControllerImpl) ProfileOTPEnroll(ctx context.Context) (*OTPEnrollResponseIDO, error) {
+	u, err := impl.profileUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u.OTPEnabled {
+		return nil, httperror.NewForBadRequestWithSingleField("message", "two-factor authentication is already enabled")
+	}
+	return impl.enrollOTP(ctx, u)
+}
+
+// ProfileOTPConfirm turns on two-factor authentication once the user proves
+// their authenticator app has the secret.
+func (impl *GatewayControllerImpl) ProfileOTPConfirm(ctx context.Context, req *OTPCodeRequestIDO) (*OTPRecoveryCodesResponseIDO, error) {
+	u, err := impl.profileUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if u.OTPEnabled {
+		return nil, httperror.NewForBadRequestWithSingleField("message", "two-factor authentication is already enabled")
+	}
+	if u.OTPPendingSecret == "" {
+		return nil, httperror.NewForBadRequestWithSingleField("message", "two-factor authentication must be enrolled first")
+	}
+	step, ok := totp.Validate(u.OTPPendingSecret, req.Code, time.Now(), 0)
+	if !ok {
+		return nil, httperror.NewForBadRequestWithSingleField("code", "invalid value")
+	}
+
+	codes, err := enableOTP(u, step)
+	if err != nil {
+		impl.Logger.Error("generate recovery codes error", slog.Any("err", err))
+		return nil, err
+	}
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.Error("user update by id error", slog.Any("error", err))
+		return nil, err
+	}
+
+	impl.Logger.Info("otp enabled", slog.Any("user_id", u.ID))
+	return &OTPRecoveryCodesResponseIDO{RecoveryCodes: codes}, nil
+}
+
+// ProfileOTPRegenerateRecoveryCodes replaces the recovery codes of the user,
+// ex: after they used most of them.
+func (impl *GatewayControllerImpl) ProfileOTPRegenerateRecoveryCodes(ctx context.Context, req *OTPCodeRequestIDO) (*OTPRecoveryCodesResponseIDO, error) {
+	u, err := impl.profileUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !u.OTPEnabled {
+		return nil, httperror.NewForBadRequestWithSingleField("message", "two-factor authentication is not enabled")
+	}
+	ok, err := impl.checkOTPCode(ctx, u, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, httperror.NewForBadRequestWithSingleField("code", "invalid value")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		impl.Logger.Error("generate recovery codes error", slog.Any("err", err))
+		return nil, err
+	}
+	u.OTPRecoveryCodeHashes = hashes
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.Error("user update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &OTPRecoveryCodesResponseIDO{RecoveryCodes: codes}, nil
+}
+
+// ProfileOTPDisable turns off two-factor authentication unless the
+// organization of the user requires it.
+func (impl *GatewayControllerImpl) ProfileOTPDisable(ctx context.Context, req *OTPCodeRequestIDO) error {
+	u, err := impl.profileUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !u.OTPEnabled {
+		return httperror.NewForBadRequestWithSingleField("message", "two-factor authentication is not enabled")
+	}
+	isOTPRequired, err := impl.isOTPRequired(ctx, u)
+	if err != nil {
+		return err
+	}
+	if isOTPRequired {
+		return httperror.NewForForbiddenWithSingleField("message", "your organization requires two-factor authentication")
+	}
+	ok, err := impl.checkOTPCode(ctx, u, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return httperror.NewForBadRequestWithSingleField("code", "invalid value")
+	}
+
+	u.OTPEnabled = false
+	u.OTPEnabledAt = time.Time{}
+	u.OTPSecret = ""
+	u.OTPPendingSecret = ""
+	u.OTPLastUsedStep = 0
+	u.OTPRecoveryCodeHashes = nil
+	if err := impl.UserStorer.UpdateByID(ctx, u); err != nil {
+		impl.Logger.Error("user update by id error", slog.Any("error", err))
+		return err
+	}
+
+	impl.Logger.Info("otp disabled", slog.Any("user_id", u.ID))
+	return nil
+}
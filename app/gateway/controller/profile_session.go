@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+)
+
+// ProfileSessions returns where the logged in user is logged in.
+func (impl *GatewayControllerImpl) ProfileSessions(ctx context.Context) ([]*session_s.Session, error) {
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	return impl.SessionController.ListByUserID(ctx, userID)
+}
+
+// ProfileRevokeSessions logs out the logged in user everywhere else, ex: after
+// they lost a device.
+func (impl *GatewayControllerImpl) ProfileRevokeSessions(ctx context.Context) error {
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	sessionID, _ := ctx.Value(constants.SessionID).(string)
+	return impl.SessionController.RevokeAllByUserID(ctx, userID, sessionID)
+}
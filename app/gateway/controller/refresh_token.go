@@ -2,13 +2,13 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
+	"net/http"
 	"time"
 
 	"golang.org/x/exp/slog"
 
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 func (impl *GatewayControllerImpl) RefreshToken(ctx context.Context, value string) (*user_s.User, string, time.Time, string, time.Time, error) {
@@ -16,27 +16,24 @@ func (impl *GatewayControllerImpl) RefreshToken(ctx context.Context, value strin
 	//// Extract the `sessionID` so we can process it.
 	////
 
-	sessionID, err := impl.JWT.ProcessJWTToken(value)
+	sessionID, refreshTokenID, err := impl.JWT.ProcessJWTRefreshToken(value)
 	if err != nil {
-		impl.Logger.Warn("process jwt refresh token does not exist", slog.String("value", value))
-		err := errors.New("jwt refresh token failed")
+		impl.Logger.Warn("process jwt refresh token error", slog.Any("err", err))
+		err := httperror.NewForSingleField(http.StatusUnauthorized, "value", "jwt refresh token failed")
 		return nil, "", time.Now(), "", time.Now(), err
 	}
 
+	// Set expiry duration.
+	atExpiry := 24 * time.Hour
+	rtExpiry := 14 * 24 * time.Hour
+
 	////
-	//// Lookup in our in-memory the user record for the `sessionID` or error.
+	//// Consume the refresh token of the session, which returns the user
+	//// record for the `sessionID`, or error.
 	////
 
-	uBin, err := impl.Cache.Get(ctx, sessionID)
+	u, newRefreshTokenID, err := impl.SessionController.Rotate(ctx, sessionID, refreshTokenID, rtExpiry)
 	if err != nil {
-		impl.Logger.Error("in-memory set error", slog.Any("err", err))
-		return nil, "", time.Now(), "", time.Now(), err
-	}
-
-	var u *user_s.User
-	err = json.Unmarshal(uBin, &u)
-	if err != nil {
-		impl.Logger.Error("unmarshal error", slog.Any("err", err))
 		return nil, "", time.Now(), "", time.Now(), err
 	}
 
@@ -44,21 +41,8 @@ func (impl *GatewayControllerImpl) RefreshToken(ctx context.Context, value strin
 	//// Generate new access and refresh tokens and return them.
 	////
 
-	// Set expiry duration.
-	atExpiry := 24 * time.Hour
-	rtExpiry := 14 * 24 * time.Hour
-
-	// Start our session using an access and refresh token.
-	newSessionUUID := impl.UUID.NewUUID()
-
-	err = impl.Cache.SetWithExpiry(ctx, newSessionUUID, uBin, rtExpiry)
-	if err != nil {
-		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
-		return nil, "", time.Now(), "", time.Now(), err
-	}
-
 	// Generate our JWT token.
-	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, err := impl.JWT.GenerateJWTTokenPair(newSessionUUID, atExpiry, rtExpiry)
+	accessToken, accessTokenExpiry, refreshToken, refreshTokenExpiry, err := impl.JWT.GenerateJWTTokenPair(sessionID, newRefreshTokenID, atExpiry, rtExpiry)
 	if err != nil {
 		impl.Logger.Error("jwt generate pairs error", slog.Any("err", err))
 		return nil, "", time.Now(), "", time.Now(), err
@@ -40,10 +40,17 @@ type RegisterResponseIDO struct {
 	RefreshTokenExpiryTime time.Time    `json:"refresh_token_expiry_time"`
 }
 
+// LoginResponseIDO holds the tokens of the new session; when the user has to
+// pass two-factor authentication only the challenge is set instead.
 type LoginResponseIDO struct {
-	User                   *user_s.User `json:"user"`
-	AccessToken            string       `json:"access_token"`
-	AccessTokenExpiryTime  time.Time    `json:"access_token_expiry_time"`
-	RefreshToken           string       `json:"refresh_token"`
-	RefreshTokenExpiryTime time.Time    `json:"refresh_token_expiry_time"`
+	User                     *user_s.User `json:"user"`
+	AccessToken              string       `json:"access_token"`
+	AccessTokenExpiryTime    time.Time    `json:"access_token_expiry_time"`
+	RefreshToken             string       `json:"refresh_token"`
+	RefreshTokenExpiryTime   time.Time    `json:"refresh_token_expiry_time"`
+	OTPRequired              bool         `json:"otp_required,omitempty"`
+	OTPEnrollmentRequired    bool         `json:"otp_enrollment_required,omitempty"` // The user must enroll before they can log in.
+	ChallengeToken           string       `json:"challenge_token,omitempty"`
+	ChallengeTokenExpiryTime time.Time    `json:"challenge_token_expiry_time,omitempty"`
+	RecoveryCodes            []string     `json:"recovery_codes,omitempty"` // Only set when the login completed the enrollment.
 }
@@ -0,0 +1,31 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+)
+
+func (impl JobStorerImpl) Create(ctx context.Context, m *Job) error {
+	if m.ID == primitive.NilObjectID {
+		m.ID = primitive.NewObjectID()
+	}
+	if m.Status == 0 {
+		m.Status = StatusPending
+	}
+	if m.RunAt.IsZero() {
+		m.RunAt = time.Now()
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+	m.ModifiedAt = time.Now()
+
+	if _, err := impl.Collection.InsertOne(ctx, m); err != nil {
+		impl.Logger.Error("database insert job error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slog"
+
+	c "github.com/LuchaComics/cps-backend/config"
+)
+
+const (
+	StatusPending    = 1
+	StatusProcessing = 2
+	StatusCompleted  = 3
+	StatusFailed     = 4
+
+	TypeGenerateComicSubmissionPDF       = "generate_comic_submission_pdf"
+	TypeSendComicSubmissionCreatedEmails = "send_comic_submission_created_emails"
+	TypeSendComicSubmissionStatusEmails  = "send_comic_submission_status_emails"
+	TypeGenerateOrderPackingSlip         = "generate_order_packing_slip"
+	TypeDeliverWebhook                   = "deliver_webhook"
+)
+
+// Job represents a unit of background work which is durably stored so it
+// survives restarts and can be retried by any running instance.
+type Job struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	Type        string             `bson:"type" json:"type"`
+	ReferenceID primitive.ObjectID `bson:"reference_id" json:"reference_id"` // The record this job operates on, ex: submission id.
+	Status      int8               `bson:"status" json:"status"`
+	Attempts    int                `bson:"attempts" json:"attempts"`
+	MaxAttempts int                `bson:"max_attempts" json:"max_attempts"`
+	LastError   string             `bson:"last_error" json:"last_error,omitempty"`
+	RunAt       time.Time          `bson:"run_at" json:"run_at"`             // The earliest time the job may be picked up.
+	LockedUntil time.Time          `bson:"locked_until" json:"locked_until"` // Lease held by the worker processing the job.
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	ModifiedAt  time.Time          `bson:"modified_at" json:"modified_at"`
+	CompletedAt time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// JobStorer Interface for job.
+type JobStorer interface {
+	Create(ctx context.Context, m *Job) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Job, error)
+	UpdateByID(ctx context.Context, m *Job) error
+	ClaimNext(ctx context.Context, lease time.Duration) (*Job, error)
+}
+
+type JobStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) JobStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("jobs")
+
+	// The following few lines of code will create the index for our app for this
+	// colleciton.
+	_, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "run_at", Value: 1}}},
+		{Keys: bson.D{{Key: "reference_id", Value: 1}, {Key: "type", Value: 1}}},
+	})
+	if err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &JobStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+func (impl JobStorerImpl) UpdateByID(ctx context.Context, m *Job) error {
+	filter := bson.M{"_id": m.ID}
+
+	update := bson.M{ // DEVELOPERS NOTE: https://stackoverflow.com/a/60946010
+		"$set": m,
+	}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// ClaimNext atomically picks the next job which is due and leases it to the
+// caller for the `lease` duration. Jobs whose lease expired, ex: the server
+// crashed mid-way, are picked up again. Returns nil if there is nothing to do.
+func (impl JobStorerImpl) ClaimNext(ctx context.Context, lease time.Duration) (*Job, error) {
+	now := time.Now()
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": StatusPending, "run_at": bson.M{"$lte": now}},
+			{"status": StatusProcessing, "locked_until": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":       StatusProcessing,
+			"locked_until": now.Add(lease),
+			"modified_at":  now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "run_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var result Job
+	err := impl.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		impl.Logger.Error("database claim next job error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/adapter/pdfbuilder"
+	s3_storage "github.com/LuchaComics/cps-backend/adapter/storage/s3"
+	comicsub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	comicsub_s "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	order_s "github.com/LuchaComics/cps-backend/app/order/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+// OrderController Interface for order business logic controller.
+type OrderController interface {
+	Create(ctx context.Context, req *OrderCreateRequestIDO) (*order_s.Order, error)
+	GetByID(ctx context.Context, id primitive.ObjectID) (*order_s.Order, error)
+	ListByFilter(ctx context.Context, f *order_s.OrderListFilter) (*order_s.OrderListResult, error)
+	RegeneratePackingSlip(ctx context.Context, id primitive.ObjectID) (*order_s.Order, error)
+	GeneratePackingSlipPDF(ctx context.Context, j *job_s.Job) error
+}
+
+type OrderControllerImpl struct {
+	Config                    *config.Conf
+	Logger                    *slog.Logger
+	S3                        s3_storage.S3Storager
+	PackingSlipRenderer       pdfbuilder.PackingSlipRenderer
+	ComicSubmissionController comicsub_c.ComicSubmissionController
+	ComicSubmissionStorer     comicsub_s.ComicSubmissionStorer
+	OrderStorer               order_s.OrderStorer
+	JobStorer                 job_s.JobStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	s3 s3_storage.S3Storager,
+	packingSlipRenderer pdfbuilder.PackingSlipRenderer,
+	sub_controller comicsub_c.ComicSubmissionController,
+	sub_storer comicsub_s.ComicSubmissionStorer,
+	order_storer order_s.OrderStorer,
+	job_storer job_s.JobStorer,
+) OrderController {
+	loggerp.Debug("order controller initialization started...")
+
+	s := &OrderControllerImpl{
+		Config:                    appCfg,
+		Logger:                    loggerp,
+		S3:                        s3,
+		PackingSlipRenderer:       packingSlipRenderer,
+		ComicSubmissionController: sub_controller,
+		ComicSubmissionStorer:     sub_storer,
+		OrderStorer:               order_storer,
+		JobStorer:                 job_storer,
+	}
+
+	// Keep the running status and the packing slip of our orders up to date
+	// as their submissions move through grading.
+	sub_controller.AddStatusTransitionHook(s.onSubmissionTransition)
+	sub_controller.AddCertificateIssuedHook(s.onCertificateIssued)
+
+	s.Logger.Debug("order controller initialized")
+	return s
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	sub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// MaxOrderLines is the most submissions we accept in one order.
+const MaxOrderLines = 250
+
+// OrderLineRequestIDO is one submission of the order along with the value
+// the customer declared for it.
+type OrderLineRequestIDO struct {
+	DeclaredValue float64 `bson:"declared_value" json:"declared_value"`
+	sub_c.ComicSubmissionCreateRequestIDO
+}
+
+type OrderCreateRequestIDO struct {
+	OrganizationID primitive.ObjectID     `bson:"organization_id,omitempty" json:"organization_id,omitempty"`
+	UserID         primitive.ObjectID     `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Shipping       *o_d.ShippingDetails   `bson:"shipping" json:"shipping"`
+	SpecialNotes   string                 `bson:"special_notes" json:"special_notes"`
+	Lines          []*OrderLineRequestIDO `bson:"lines" json:"lines"`
+}
+
+// Create creates the order along with a submission for every line of the
+// request, each with its own `CPS Registry Number`. The order is saved first
+// so the submissions, and the certificate jobs they schedule, never refer to
+// an order which does not exist.
+func (c *OrderControllerImpl) Create(ctx context.Context, req *OrderCreateRequestIDO) (*o_d.Order, error) {
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userRole := ctx.Value(constants.SessionUserRole).(int8)
+
+	// DEVELOPERS NOTE:
+	// Users limited to their organization can only create orders for it;
+	// however, our staff has the ability to pick the organization.
+	if err := permission.Check(ctx, permission.OrderManage); err != nil {
+		return nil, err
+	}
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		req.OrganizationID = organizationID
+	}
+	if len(req.Lines) > MaxOrderLines {
+		return nil, httperror.NewForBadRequestWithSingleField("lines", fmt.Sprintf("over %v submissions", MaxOrderLines))
+	}
+
+	o := &o_d.Order{
+		ID:                 primitive.NewObjectID(),
+		OrganizationID:     req.OrganizationID,
+		UserID:             req.UserID,
+		Status:             o_d.OrderStatusReceived,
+		Shipping:           req.Shipping,
+		SpecialNotes:       req.SpecialNotes,
+		PackingSlipStatus:  o_d.PackingSlipStatusAwaiting,
+		CreatedAt:          time.Now(),
+		CreatedByUserID:    userID,
+		CreatedByUserRole:  userRole,
+		ModifiedAt:         time.Now(),
+		ModifiedByUserID:   userID,
+		ModifiedByUserRole: userRole,
+		Lines:              []*o_d.OrderLine{},
+	}
+	if err := c.OrderStorer.Create(ctx, o); err != nil {
+		c.Logger.Error("database create error", slog.Any("error", err), slog.Any("order_id", o.ID))
+		return nil, err
+	}
+
+	subReq := &sub_c.ComicSubmissionCreateManyRequestIDO{
+		OrderID:        o.ID,
+		OrganizationID: req.OrganizationID,
+		UserID:         req.UserID,
+		Lines:          make([]*sub_c.ComicSubmissionCreateRequestIDO, len(req.Lines)),
+	}
+	for i, line := range req.Lines {
+		subReq.Lines[i] = &line.ComicSubmissionCreateRequestIDO
+	}
+	submissions, err := c.ComicSubmissionController.CreateMany(ctx, subReq)
+	if err != nil {
+		c.deleteIfEmpty(ctx, o)
+		return nil, err
+	}
+
+	o.Lines = make([]*o_d.OrderLine, len(submissions))
+	for i, m := range submissions {
+		o.Lines[i] = &o_d.OrderLine{
+			SubmissionID:  m.ID,
+			CPSRN:         m.CPSRN,
+			Item:          m.Item,
+			ServiceType:   m.ServiceType,
+			DeclaredValue: req.Lines[i].DeclaredValue,
+			Status:        m.Status,
+		}
+		o.DeclaredValueTotal += req.Lines[i].DeclaredValue
+		o.OrganizationID = m.OrganizationID
+		o.OrganizationName = m.OrganizationName
+	}
+	o.LineCount = len(o.Lines)
+	o.ModifiedAt = time.Now()
+
+	saved, err := c.OrderStorer.UpdateLinesByID(ctx, o)
+	if err != nil {
+		c.Logger.Error("database update lines by id error", slog.Any("error", err), slog.Any("order_id", o.ID))
+		return nil, err
+	}
+	if saved == nil {
+		return nil, fmt.Errorf("order was deleted during creation: %v", o.ID)
+	}
+
+	// A certificate issued before the lines were saved scheduled the packing
+	// slip of an empty order, so schedule it again now that it has them.
+	if saved.PackingSlipJobID != primitive.NilObjectID {
+		if err := c.enqueuePackingSlip(ctx, saved); err != nil {
+			c.Logger.Error("enqueue packing slip job error", slog.Any("error", err))
+			return nil, err
+		}
+	}
+	return saved, nil
+}
+
+// deleteIfEmpty deletes the order after its submissions failed to be created
+// unless some of them were saved, in which case they still refer to it.
+func (c *OrderControllerImpl) deleteIfEmpty(ctx context.Context, o *o_d.Order) {
+	count, err := c.ComicSubmissionStorer.CountByFilter(ctx, &s_d.ComicSubmissionListFilter{OrderID: o.ID})
+	if err != nil {
+		c.Logger.Error("database count by filter error", slog.Any("error", err), slog.Any("order_id", o.ID))
+		return
+	}
+	if count > 0 {
+		c.Logger.Warn("order kept after failed creation", slog.Any("order_id", o.ID), slog.Int64("submissions", count))
+		return
+	}
+	if err := c.OrderStorer.DeleteByID(ctx, o.ID); err != nil {
+		c.Logger.Error("database delete by id error", slog.Any("error", err), slog.Any("order_id", o.ID))
+	}
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (c *OrderControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*o_d.Order, error) {
+	o, err := c.getPermittedOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The following will generate a pre-signed URL so user can download the file.
+	if o.PackingSlipS3ObjectKey != "" {
+		downloadableURL, err := c.S3.GetDownloadablePresignedURL(ctx, o.PackingSlipS3ObjectKey, time.Minute*15)
+		if err != nil {
+			c.Logger.Warn("s3 presign error", slog.Any("error", err))
+		}
+		o.PackingSlipDownloadableURL = downloadableURL
+	}
+	return o, nil
+}
+
+// getPermittedOrder returns the order if the logged in user may work with it.
+func (c *OrderControllerImpl) getPermittedOrder(ctx context.Context, id primitive.ObjectID) (*o_d.Order, error) {
+	if err := permission.Check(ctx, permission.OrderManage); err != nil {
+		return nil, err
+	}
+
+	o, err := c.OrderStorer.GetByID(ctx, id)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if o == nil {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "order does not exist")
+	}
+
+	// Users limited to their organization may only work with its orders.
+	if err := permission.CheckOrganization(ctx, o.OrganizationID); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
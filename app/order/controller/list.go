@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+)
+
+func (c *OrderControllerImpl) ListByFilter(ctx context.Context, f *o_d.OrderListFilter) (*o_d.OrderListResult, error) {
+	// Apply protection based on ownership and role.
+	if err := permission.Check(ctx, permission.OrderManage); err != nil {
+		return nil, err
+	}
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		f.OrganizationID = organizationID
+	}
+
+	m, err := c.OrderStorer.ListByFilter(ctx, f)
+	if err != nil {
+		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+)
+
+// RegeneratePackingSlip schedules the packing slip of the order to be
+// generated again, ex: after a previous attempt failed.
+func (c *OrderControllerImpl) RegeneratePackingSlip(ctx context.Context, id primitive.ObjectID) (*o_d.Order, error) {
+	o, err := c.getPermittedOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.enqueuePackingSlip(ctx, o); err != nil {
+		c.Logger.Error("enqueue packing slip job error", slog.Any("error", err))
+		return nil, err
+	}
+	return o, nil
+}
+
+// onCertificateIssued rolls the newly issued certificate of a submission up
+// into the packing slip of its order.
+func (c *OrderControllerImpl) onCertificateIssued(ctx context.Context, m *s_d.ComicSubmission) {
+	if m.OrderID.IsZero() {
+		return
+	}
+	o, err := c.OrderStorer.GetByID(ctx, m.OrderID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return
+	}
+	if o == nil {
+		return
+	}
+	if err := c.enqueuePackingSlip(ctx, o); err != nil {
+		c.Logger.Error("enqueue packing slip job error", slog.Any("error", err))
+	}
+}
+
+// enqueuePackingSlip schedules the packing slip of the order to be generated.
+// Only the latest scheduled job generates it, so the certificates of a big
+// order being issued one after the other do not generate it over and over.
+func (c *OrderControllerImpl) enqueuePackingSlip(ctx context.Context, o *o_d.Order) error {
+	j := &job_s.Job{
+		Type:        job_s.TypeGenerateOrderPackingSlip,
+		ReferenceID: o.ID,
+		MaxAttempts: c.Config.JobQueue.MaxAttempts,
+	}
+	if err := c.JobStorer.Create(ctx, j); err != nil {
+		return err
+	}
+	o.PackingSlipStatus = o_d.PackingSlipStatusPending
+	o.PackingSlipJobID = j.ID
+	o.PackingSlipError = ""
+	return c.OrderStorer.UpdatePackingSlipByID(ctx, o)
+}
+
+// GeneratePackingSlipPDF is the job handler which generates the packing slip
+// of the order once the certificate of every submission was issued.
+func (c *OrderControllerImpl) GeneratePackingSlipPDF(ctx context.Context, j *job_s.Job) error {
+	o, err := c.OrderStorer.GetByID(ctx, j.ReferenceID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if o == nil {
+		c.Logger.Warn("order does not exist, skipping packing slip job", slog.Any("id", j.ReferenceID))
+		return nil
+	}
+	if o.PackingSlipJobID != primitive.NilObjectID && o.PackingSlipJobID != j.ID {
+		c.Logger.Debug("packing slip job superseded", slog.Any("job_id", j.ID), slog.Any("latest_job_id", o.PackingSlipJobID))
+		return nil
+	}
+
+	// An order without lines is still being created and schedules this job
+	// once it has them.
+	if len(o.Lines) == 0 {
+		c.Logger.Debug("packing slip awaiting order lines", slog.Any("order_id", o.ID))
+		o.PackingSlipStatus = o_d.PackingSlipStatusAwaiting
+		return c.OrderStorer.UpdatePackingSlipByID(ctx, o)
+	}
+
+	res, err := c.ComicSubmissionStorer.ListByFilter(ctx, &s_d.ComicSubmissionListFilter{
+		OrderID:   o.ID,
+		PageSize:  int64(len(o.Lines)),
+		SortField: "_id",
+		SortOrder: 1,
+	})
+	if err != nil {
+		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		return err
+	}
+
+	// Wait until the certificate of every submission was issued; the last
+	// certificate to be issued schedules this job again.
+	for _, m := range res.Results {
+		if m.PDFStatus != s_d.PDFStatusReady && m.Status != s_d.StatusRejected {
+			c.Logger.Debug("packing slip awaiting certificates", slog.Any("order_id", o.ID), slog.String("cpsrn", m.CPSRN))
+			o.PackingSlipStatus = o_d.PackingSlipStatusAwaiting
+			return c.OrderStorer.UpdatePackingSlipByID(ctx, o)
+		}
+	}
+
+	pdfResponse, err := c.PackingSlipRenderer.Render(o, res.Results)
+	if err != nil {
+		return c.packingSlipJobFailed(ctx, o, j, err)
+	}
+
+	path := fmt.Sprintf("uploads/orders/%v/%v", o.ID.Hex(), pdfResponse.FileName)
+	if err := c.S3.UploadContent(ctx, path, pdfResponse.Content); err != nil {
+		c.Logger.Error("s3 upload error", slog.Any("error", err))
+		return c.packingSlipJobFailed(ctx, o, j, err)
+	}
+
+	o.PackingSlipS3ObjectKey = path
+	o.PackingSlipStatus = o_d.PackingSlipStatusReady
+	o.PackingSlipError = ""
+	o.PackingSlipGeneratedAt = time.Now()
+	if err := c.OrderStorer.UpdatePackingSlipByID(ctx, o); err != nil {
+		c.Logger.Error("database update packing slip error", slog.Any("error", err))
+		return err
+	}
+
+	// Removing local file from the directory and don't do anything if we have errors.
+	if err := os.Remove(pdfResponse.FilePath); err != nil {
+		c.Logger.Warn("removing local file error", slog.Any("error", err))
+	}
+	return nil
+}
+
+// packingSlipJobFailed records the error on the order and returns it so the
+// job gets retried; once out of attempts the packing slip is marked as failed.
+func (c *OrderControllerImpl) packingSlipJobFailed(ctx context.Context, o *o_d.Order, j *job_s.Job, jobErr error) error {
+	c.Logger.Error("generate packing slip error", slog.Any("error", jobErr), slog.Int("attempts", j.Attempts))
+	o.PackingSlipError = jobErr.Error()
+	o.PackingSlipStatus = o_d.PackingSlipStatusPending
+	if j.Attempts >= j.MaxAttempts {
+		o.PackingSlipStatus = o_d.PackingSlipStatusFailed
+	}
+	if err := c.OrderStorer.UpdatePackingSlipByID(ctx, o); err != nil {
+		c.Logger.Error("database update packing slip error", slog.Any("error", err))
+	}
+	return jobErr
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+)
+
+// onSubmissionTransition copies the new status of the submission into its
+// line of the order and updates the running status of the order.
+func (c *OrderControllerImpl) onSubmissionTransition(ctx context.Context, m *s_d.ComicSubmission, t *s_d.StatusTransition) {
+	if m.OrderID.IsZero() {
+		return
+	}
+	o, err := c.OrderStorer.UpdateLineStatus(ctx, m.OrderID, m.ID, t.To)
+	if err != nil {
+		c.Logger.Error("database update line status error", slog.Any("error", err))
+		return
+	}
+	if o == nil || o.Status == o_d.OrderStatusArchived {
+		return
+	}
+	if status := orderStatus(o.Lines); status != o.Status {
+		if err := c.OrderStorer.UpdateStatusByID(ctx, o.ID, status); err != nil {
+			c.Logger.Error("database update status by id error", slog.Any("error", err))
+		}
+	}
+}
+
+// orderStatus returns the running status of an order with the lines.
+func orderStatus(lines []*o_d.OrderLine) int8 {
+	if len(lines) == 0 {
+		return o_d.OrderStatusReceived
+	}
+	isStarted, isShipped, isCompleted := false, true, true
+	for _, line := range lines {
+		switch line.Status {
+		case s_d.StatusCompleted, s_d.StatusRejected, s_d.StatusArchived:
+			isStarted = true
+		case s_d.StatusShipped:
+			isStarted = true
+			isCompleted = false
+		case s_d.StatusReceived, s_d.StatusPending, s_d.StatusActive, s_d.StatusError:
+			isShipped, isCompleted = false, false
+		default:
+			isStarted = true
+			isShipped, isCompleted = false, false
+		}
+	}
+	switch {
+	case isCompleted:
+		return o_d.OrderStatusCompleted
+	case isShipped:
+		return o_d.OrderStatusShipped
+	case isStarted:
+		return o_d.OrderStatusInProgress
+	}
+	return o_d.OrderStatusReceived
+}
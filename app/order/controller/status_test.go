@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"testing"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	o_d "github.com/LuchaComics/cps-backend/app/order/datastore"
+)
+
+func TestOrderStatus(t *testing.T) {
+	tests := []struct {
+		statuses []int8
+		expected int8
+	}{
+		{nil, o_d.OrderStatusReceived},
+		{[]int8{s_d.StatusReceived, s_d.StatusReceived}, o_d.OrderStatusReceived},
+		{[]int8{s_d.StatusReceived, s_d.StatusInGrading}, o_d.OrderStatusInProgress},
+		{[]int8{s_d.StatusOnHold, s_d.StatusCompleted}, o_d.OrderStatusInProgress},
+		{[]int8{s_d.StatusShipped, s_d.StatusRejected}, o_d.OrderStatusShipped},
+		{[]int8{s_d.StatusShipped, s_d.StatusCompleted}, o_d.OrderStatusShipped},
+		{[]int8{s_d.StatusCompleted, s_d.StatusRejected}, o_d.OrderStatusCompleted},
+	}
+	for _, test := range tests {
+		var lines []*o_d.OrderLine
+		for _, status := range test.statuses {
+			lines = append(lines, &o_d.OrderLine{Status: status})
+		}
+		if actual := orderStatus(lines); actual != test.expected {
+			t.Errorf("expected %v for %v but got %v", test.expected, test.statuses, actual)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slog"
+
+	c "github.com/LuchaComics/cps-backend/config"
+)
+
+const (
+	OrderStatusReceived       = 1 // None of the submissions started grading.
+	OrderStatusInProgress     = 2
+	OrderStatusShipped        = 3 // Every submission was shipped back, or rejected.
+	OrderStatusCompleted      = 4 // Every submission was completed, or rejected.
+	OrderStatusArchived       = 100
+	PackingSlipStatusPending  = 1
+	PackingSlipStatusReady    = 2
+	PackingSlipStatusFailed   = 3
+	PackingSlipStatusAwaiting = 4 // Waiting on the certificates of the submissions.
+)
+
+// Order groups the submissions a retailer sends us in one box.
+type Order struct {
+	ID                         primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizationID             primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	OrganizationName           string             `bson:"organization_name" json:"organization_name"`
+	UserID                     primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"` // This is the customer the order belongs to.
+	Status                     int8               `bson:"status" json:"status"`
+	Shipping                   *ShippingDetails   `bson:"shipping" json:"shipping"`
+	Lines                      []*OrderLine       `bson:"lines" json:"lines"`
+	LineCount                  int                `bson:"line_count" json:"line_count"`
+	DeclaredValueTotal         float64            `bson:"declared_value_total" json:"declared_value_total"`
+	SpecialNotes               string             `bson:"special_notes" json:"special_notes"`
+	PackingSlipStatus          int8               `bson:"packing_slip_status" json:"packing_slip_status"`
+	PackingSlipJobID           primitive.ObjectID `bson:"packing_slip_job_id,omitempty" json:"packing_slip_job_id,omitempty"`
+	PackingSlipError           string             `bson:"packing_slip_error" json:"packing_slip_error,omitempty"`
+	PackingSlipS3ObjectKey     string             `bson:"packing_slip_s3_key" json:"packing_slip_s3_object_key"`
+	PackingSlipDownloadableURL string             `bson:"-" json:"packing_slip_downloadable_url,omitempty"`
+	PackingSlipGeneratedAt     time.Time          `bson:"packing_slip_generated_at,omitempty" json:"packing_slip_generated_at,omitempty"`
+	CreatedAt                  time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID            primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id"`
+	CreatedByUserRole          int8               `bson:"created_by_user_role" json:"created_by_user_role"`
+	ModifiedAt                 time.Time          `bson:"modified_at" json:"modified_at"`
+	ModifiedByUserID           primitive.ObjectID `bson:"modified_by_user_id" json:"modified_by_user_id"`
+	ModifiedByUserRole         int8               `bson:"modified_by_user_role" json:"modified_by_user_role"`
+}
+
+// ShippingDetails is where and how the order is shipped back to.
+type ShippingDetails struct {
+	Name           string `bson:"name" json:"name"`
+	Phone          string `bson:"phone" json:"phone"`
+	AddressLine1   string `bson:"address_line_1" json:"address_line_1"`
+	AddressLine2   string `bson:"address_line_2" json:"address_line_2"`
+	City           string `bson:"city" json:"city"`
+	Region         string `bson:"region" json:"region"`
+	PostalCode     string `bson:"postal_code" json:"postal_code"`
+	Country        string `bson:"country" json:"country"`
+	Carrier        string `bson:"carrier" json:"carrier"`
+	TrackingNumber string `bson:"tracking_number" json:"tracking_number"`
+}
+
+// OrderLine is one submission of the order.
+type OrderLine struct {
+	SubmissionID  primitive.ObjectID `bson:"submission_id" json:"submission_id"`
+	CPSRN         string             `bson:"cpsrn" json:"cpsrn"`
+	Item          string             `bson:"item" json:"item"`
+	ServiceType   int8               `bson:"service_type" json:"service_type"`
+	DeclaredValue float64            `bson:"declared_value" json:"declared_value"`
+	Status        int8               `bson:"status" json:"status"` // Copy of the status of the submission.
+}
+
+type OrderListFilter struct {
+	// Pagination related.
+	Cursor    primitive.ObjectID
+	PageSize  int64
+	SortField string
+	SortOrder int8 // 1=ascending | -1=descending
+
+	// Filter related.
+	OrganizationID  primitive.ObjectID
+	UserID          primitive.ObjectID
+	Status          int8
+	ExcludeArchived bool
+	CreatedAtGTE    time.Time
+}
+
+type OrderListResult struct {
+	Results     []*Order           `json:"results"`
+	NextCursor  primitive.ObjectID `json:"next_cursor"`
+	HasNextPage bool               `json:"has_next_page"`
+}
+
+// OrderStorer Interface for order.
+type OrderStorer interface {
+	Create(ctx context.Context, m *Order) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Order, error)
+	UpdateByID(ctx context.Context, m *Order) error
+	UpdateLinesByID(ctx context.Context, m *Order) (*Order, error)
+	UpdatePackingSlipByID(ctx context.Context, m *Order) error
+	UpdateLineStatus(ctx context.Context, orderID primitive.ObjectID, submissionID primitive.ObjectID, status int8) (*Order, error)
+	UpdateStatusByID(ctx context.Context, id primitive.ObjectID, status int8) error
+	ListByFilter(ctx context.Context, f *OrderListFilter) (*OrderListResult, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+type OrderStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) OrderStorer {
+	// ctx := context.Background()
+	uc := client.Database(appCfg.DB.Name).Collection("orders")
+
+	// The following few lines of code will create the index for our app for this
+	// colleciton.
+	if _, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "lines.submission_id", Value: 1}}},
+	}); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	s := &OrderStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
@@ -0,0 +1,87 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+func (impl OrderStorerImpl) ListByFilter(ctx context.Context, f *OrderListFilter) (*OrderListResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+
+	// Create the filter based on the cursor
+	filter := bson.M{}
+	if !f.Cursor.IsZero() {
+		filter["_id"] = bson.M{"$gt": f.Cursor} // Add the cursor condition to the filter
+	}
+
+	// Add filter conditions to the filter
+	if f.OrganizationID != primitive.NilObjectID {
+		filter["organization_id"] = f.OrganizationID
+	}
+	if f.UserID != primitive.NilObjectID {
+		filter["user_id"] = f.UserID
+	}
+	if f.ExcludeArchived {
+		filter["status"] = bson.M{"$ne": OrderStatusArchived} // Do not list archived items!
+	}
+	if f.Status != 0 {
+		filter["status"] = f.Status
+	}
+	if !f.CreatedAtGTE.IsZero() {
+		filter["created_at"] = bson.M{"$gt": f.CreatedAtGTE}
+	}
+
+	impl.Logger.Debug("listing filter:",
+		slog.Any("filter", filter))
+
+	// Include additional filters for our cursor-based pagination pertaining to
+	// sorting and limit. The lines are left out as an order can have hundreds.
+	options := options.Find().
+		SetSort(bson.M{f.SortField: f.SortOrder}).
+		SetLimit(f.PageSize + 1).
+		SetProjection(bson.M{"lines": 0})
+
+	// Execute the query
+	cursor, err := impl.Collection.Find(ctx, filter, options)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// Retrieve the documents and check if there is a next page
+	results := []*Order{}
+	hasNextPage := false
+	for cursor.Next(ctx) {
+		// Stop fetching documents if we have reached the desired page size
+		if int64(len(results)) >= f.PageSize {
+			hasNextPage = true
+			break
+		}
+		document := &Order{}
+		if err := cursor.Decode(document); err != nil {
+			return nil, err
+		}
+		results = append(results, document)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// Get the last document's _id as the next cursor
+	nextCursor := primitive.NilObjectID
+	if hasNextPage {
+		nextCursor = results[len(results)-1].ID
+	}
+
+	return &OrderListResult{
+		Results:     results,
+		NextCursor:  nextCursor,
+		HasNextPage: hasNextPage,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+func (impl OrderStorerImpl) UpdateByID(ctx context.Context, m *Order) error {
+	filter := bson.M{"_id": m.ID}
+	update := bson.M{"$set": m}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// UpdateLinesByID only saves the lines of the order, and what is derived from
+// them, and returns the order as it is after the update so the packing slip
+// fields a background job saved in the meantime are not lost.
+func (impl OrderStorerImpl) UpdateLinesByID(ctx context.Context, m *Order) (*Order, error) {
+	filter := bson.M{"_id": m.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"lines":                m.Lines,
+			"line_count":           m.LineCount,
+			"declared_value_total": m.DeclaredValueTotal,
+			"organization_id":      m.OrganizationID,
+			"organization_name":    m.OrganizationName,
+			"modified_at":          m.ModifiedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result Order
+	if err := impl.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		impl.Logger.Error("database update lines by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdatePackingSlipByID only saves the packing slip related fields so a
+// background job does not overwrite changes made by users in the meantime.
+func (impl OrderStorerImpl) UpdatePackingSlipByID(ctx context.Context, m *Order) error {
+	filter := bson.M{"_id": m.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"packing_slip_status":       m.PackingSlipStatus,
+			"packing_slip_job_id":       m.PackingSlipJobID,
+			"packing_slip_error":        m.PackingSlipError,
+			"packing_slip_s3_key":       m.PackingSlipS3ObjectKey,
+			"packing_slip_generated_at": m.PackingSlipGeneratedAt,
+		},
+	}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update packing slip by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// UpdateLineStatus copies the status of one submission into its line of the
+// order and returns the order as it is after the update.
+func (impl OrderStorerImpl) UpdateLineStatus(ctx context.Context, orderID primitive.ObjectID, submissionID primitive.ObjectID, status int8) (*Order, error) {
+	filter := bson.M{"_id": orderID, "lines.submission_id": submissionID}
+	update := bson.M{
+		"$set": bson.M{
+			"lines.$.status": status,
+			"modified_at":    time.Now(),
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result Order
+	if err := impl.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		impl.Logger.Error("database update line status error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateStatusByID only saves the running status of the order.
+func (impl OrderStorerImpl) UpdateStatusByID(ctx context.Context, id primitive.ObjectID, status int8) error {
+	filter := bson.M{"_id": id}
+	update := bson.M{"$set": bson.M{"status": status}}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update status by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -8,7 +8,7 @@ import (
 	"golang.org/x/exp/slog"
 
 	s_d "github.com/LuchaComics/cps-backend/app/organization/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
@@ -20,7 +20,7 @@ func (c *OrganizationControllerImpl) Create(ctx context.Context, m *s_d.Organiza
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot {
+	if !permission.IsAllowed(ctx, permission.OrganizationAll) || !permission.IsAllowed(ctx, permission.OrganizationManage) {
 		c.Logger.Error("authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
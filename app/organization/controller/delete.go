@@ -7,7 +7,7 @@ import (
 	"golang.org/x/exp/slog"
 
 	org_d "github.com/LuchaComics/cps-backend/app/organization/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
@@ -18,7 +18,7 @@ func (impl *OrganizationControllerImpl) DeleteByID(ctx context.Context, id primi
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot {
+	if !permission.IsAllowed(ctx, permission.OrganizationAll) || !permission.IsAllowed(ctx, permission.OrganizationManage) {
 		impl.Logger.Error("authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	domain "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// ExportByFilter calls the function with every organization of the filter
+// while they are read from the database; only staff may do this.
+func (c *OrganizationControllerImpl) ExportByFilter(ctx context.Context, f *domain.OrganizationListFilter, fn func(m *domain.Organization) error) error {
+	// Extract from our session the following data.
+	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
+	userRole := ctx.Value(constants.SessionUserRole).(int8)
+
+	// Apply protection based on ownership and role.
+	if !permission.IsAllowed(ctx, permission.OrganizationAll) {
+		c.Logger.Error("authenticated user is not staff role error",
+			slog.Any("role", userRole),
+			slog.Any("userID", userID))
+		return httperror.NewForForbiddenWithSingleField("message", "you role does not grant you access to this")
+	}
+
+	if err := c.OrganizationStorer.ExportByFilter(ctx, f, fn); err != nil {
+		c.Logger.Error("database export by filter error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
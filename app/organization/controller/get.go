@@ -4,7 +4,7 @@ import (
 	"context"
 
 	domain "github.com/LuchaComics/cps-backend/app/organization/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,7 +17,7 @@ func (c *OrganizationControllerImpl) GetByID(ctx context.Context, id primitive.O
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// If user is not administrator nor belongs to the organization then error.
-	if userRole != user_d.UserRoleRoot && id != userOrganizationID {
+	if permission.CheckOrganization(ctx, id) != nil {
 		c.Logger.Error("authenticated user is not staff role nor belongs to the organization error",
 			slog.Any("userRole", userRole),
 			slog.Any("userOrganizationID", userOrganizationID))
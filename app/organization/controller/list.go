@@ -4,7 +4,7 @@ import (
 	"context"
 
 	domain "github.com/LuchaComics/cps-backend/app/organization/datastore"
-	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -17,7 +17,7 @@ func (c *OrganizationControllerImpl) ListByFilter(ctx context.Context, f *domain
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot {
+	if !permission.IsAllowed(ctx, permission.OrganizationAll) {
 		c.Logger.Error("authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
@@ -51,7 +51,7 @@ func (c *OrganizationControllerImpl) ListAsSelectOptionByFilter(ctx context.Cont
 	userRole := ctx.Value(constants.SessionUserRole).(int8)
 
 	// Apply protection based on ownership and role.
-	if userRole != user_d.UserRoleRoot {
+	if !permission.IsAllowed(ctx, permission.OrganizationAll) {
 		c.Logger.Error("authenticated user is not staff role error",
 			slog.Any("role", userRole),
 			slog.Any("userID", userID))
@@ -9,6 +9,7 @@ import (
 
 	sub_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
 	domain "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_d "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
@@ -34,7 +35,7 @@ func (c *OrganizationControllerImpl) UpdateByID(ctx context.Context, ns *domain.
 	userName := ctx.Value(constants.SessionUserName).(string)
 
 	// If user is not administrator nor belongs to the organization then error.
-	if userRole != user_d.UserRoleRoot && os.ID != userOrganizationID {
+	if permission.CheckOrganization(ctx, os.ID) != nil || !permission.IsAllowed(ctx, permission.OrganizationManage) {
 		c.Logger.Error("authenticated user is not staff role nor belongs to the organization error",
 			slog.Any("userRole", userRole),
 			slog.Any("userOrganizationID", userOrganizationID))
@@ -49,6 +50,12 @@ func (c *OrganizationControllerImpl) UpdateByID(ctx context.Context, ns *domain.
 	os.Status = ns.Status
 	os.Name = ns.Name
 
+	// Only root staff decide who must use two-factor authentication.
+	if permission.IsAllowed(ctx, permission.OrganizationAll) {
+		os.IsOTPRequired = ns.IsOTPRequired
+		os.OTPRequiredRoles = ns.OTPRequiredRoles
+	}
+
 	// Save to the database the modified organization.
 	if err := c.OrganizationStorer.UpdateByID(ctx, os); err != nil {
 		c.Logger.Error("database update by id error", slog.Any("error", err))
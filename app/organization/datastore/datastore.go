@@ -34,6 +34,22 @@ type Organization struct {
 	CreatedByUserName  string                 `bson:"created_by_user_name" json:"created_by_user_name"`
 	CreatedByUserID    primitive.ObjectID     `bson:"created_by_user_id" json:"created_by_user_id"`
 	Comments           []*OrganizationComment `bson:"comments" json:"comments"`
+	IsOTPRequired      bool                   `bson:"is_otp_required" json:"is_otp_required"`       // Every member must use two-factor authentication.
+	OTPRequiredRoles   []int8                 `bson:"otp_required_roles" json:"otp_required_roles"` // Members of these roles must use two-factor authentication.
+}
+
+// IsOTPRequiredForRole returns true if members of the organization with the
+// role must use two-factor authentication.
+func (m *Organization) IsOTPRequiredForRole(role int8) bool {
+	if m.IsOTPRequired {
+		return true
+	}
+	for _, r := range m.OTPRequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
 }
 
 type OrganizationComment struct {
@@ -82,6 +98,7 @@ type OrganizationStorer interface {
 	GetByID(ctx context.Context, id primitive.ObjectID) (*Organization, error)
 	UpdateByID(ctx context.Context, m *Organization) error
 	ListByFilter(ctx context.Context, m *OrganizationListFilter) (*OrganizationListResult, error)
+	ExportByFilter(ctx context.Context, f *OrganizationListFilter, fn func(m *Organization) error) error
 	ListAsSelectOptionByFilter(ctx context.Context, f *OrganizationListFilter) ([]*OrganizationAsSelectOption, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
 	// //TODO: Add more...
@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+// ExportByFilter calls the function with every record of the filter, in the
+// order they were created, while they are read from the database cursor.
+// There is no page size and no timeout besides the context of the caller as
+// exports can be large.
+func (impl OrganizationStorerImpl) ExportByFilter(ctx context.Context, f *OrganizationListFilter, fn func(m *Organization) error) error {
+	filter := newListFilter(f)
+	if f.SearchText != "" {
+		filter["$text"] = bson.M{"$search": f.SearchText}
+	}
+
+	impl.Logger.Debug("exporting filter:",
+		slog.Any("filter", filter))
+
+	options := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetBatchSize(500)
+
+	cursor, err := impl.Collection.Find(ctx, filter, options)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		m := &Organization{}
+		if err := cursor.Decode(m); err != nil {
+			return err
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
@@ -15,25 +15,7 @@ func (impl OrganizationStorerImpl) ListByFilter(ctx context.Context, f *Organiza
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
 
-	// Create the filter based on the cursor
-	filter := bson.M{}
-	if !f.Cursor.IsZero() {
-		filter["_id"] = bson.M{"$gt": f.Cursor} // Add the cursor condition to the filter
-	}
-
-	// Add filter conditions to the filter
-	if f.UserID != primitive.NilObjectID {
-		filter["user_id"] = f.UserID
-	}
-	if f.ExcludeArchived {
-		filter["status"] = bson.M{"$ne": OrganizationArchivedStatus} // Do not list archived items! This code
-	}
-	if f.Status != 0 {
-		filter["status"] = f.Status
-	}
-	if !f.CreatedAtGTE.IsZero() {
-		filter["created_at"] = bson.M{"$gt": f.CreatedAtGTE} // Add the cursor condition to the filter
-	}
+	filter := newListFilter(f)
 
 	impl.Logger.Debug("listing filter:",
 		slog.Any("filter", filter))
@@ -95,6 +77,32 @@ func (impl OrganizationStorerImpl) ListByFilter(ctx context.Context, f *Organiza
 	}, nil
 }
 
+// newListFilter returns the query of the filter which is shared by our list
+// and export functions.
+func newListFilter(f *OrganizationListFilter) bson.M {
+	// Create the filter based on the cursor
+	filter := bson.M{}
+	if !f.Cursor.IsZero() {
+		filter["_id"] = bson.M{"$gt": f.Cursor} // Add the cursor condition to the filter
+	}
+
+	// Add filter conditions to the filter
+	if f.UserID != primitive.NilObjectID {
+		filter["user_id"] = f.UserID
+	}
+	if f.ExcludeArchived {
+		filter["status"] = bson.M{"$ne": OrganizationArchivedStatus} // Do not list archived items! This code
+	}
+	if f.Status != 0 {
+		filter["status"] = f.Status
+	}
+	if !f.CreatedAtGTE.IsZero() {
+		filter["created_at"] = bson.M{"$gt": f.CreatedAtGTE} // Add the cursor condition to the filter
+	}
+
+	return filter
+}
+
 func (impl OrganizationStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *OrganizationListFilter) ([]*OrganizationAsSelectOption, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
@@ -0,0 +1,174 @@
+// Package permission is our role-based access control. Every role is a set of
+// permissions and the controllers only ever ask whether the logged in user
+// has a permission, so adding a role only takes a new entry in `Roles`.
+package permission
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// Permission is an action on a resource, ex: `submission:create`.
+type Permission string
+
+const (
+	SubmissionRead   Permission = "submission:read"
+	SubmissionCreate Permission = "submission:create"
+	SubmissionUpdate Permission = "submission:update"
+	SubmissionDelete Permission = "submission:delete"
+	SubmissionGrade  Permission = "submission:grade"
+	SubmissionShip   Permission = "submission:ship"
+	SubmissionHold   Permission = "submission:hold"
+	SubmissionReject Permission = "submission:reject"
+
+	OrderManage      Permission = "order:manage"
+	CustomerManage   Permission = "customer:manage"
+	AttachmentManage Permission = "attachment:manage"
+	ReportRead       Permission = "report:read"
+
+	// OrganizationAll lifts the tenancy of the user; without it the user only
+	// sees the records of their own organization.
+	OrganizationAll    Permission = "organization:all"
+	OrganizationManage Permission = "organization:manage"
+
+	UserManage Permission = "user:manage"
+)
+
+// Role is a named set of permissions.
+type Role struct {
+	ID          int8         `json:"id"`
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Roles are every role of our system keyed by the `role` of the user.
+var Roles = map[int8]*Role{
+	u_d.UserRoleRoot: {
+		ID:   u_d.UserRoleRoot,
+		Name: "Root",
+		Permissions: []Permission{
+			SubmissionRead, SubmissionCreate, SubmissionUpdate, SubmissionDelete,
+			SubmissionGrade, SubmissionShip, SubmissionHold, SubmissionReject,
+			OrderManage, CustomerManage, AttachmentManage, ReportRead,
+			OrganizationAll, OrganizationManage,
+			UserManage,
+		},
+	},
+	u_d.UserRoleRetailer: {
+		ID:   u_d.UserRoleRetailer,
+		Name: "Retailer",
+		Permissions: []Permission{
+			SubmissionRead, SubmissionCreate, SubmissionUpdate, SubmissionDelete, SubmissionHold,
+			OrderManage, CustomerManage, AttachmentManage, ReportRead,
+			OrganizationManage,
+		},
+	},
+	u_d.UserRoleCustomer: {
+		ID:   u_d.UserRoleCustomer,
+		Name: "Customer",
+		Permissions: []Permission{
+			SubmissionRead,
+		},
+	},
+	u_d.UserRoleGrader: {
+		ID:   u_d.UserRoleGrader,
+		Name: "Grader",
+		Permissions: []Permission{
+			SubmissionRead, SubmissionGrade, SubmissionHold,
+			AttachmentManage,
+			OrganizationAll,
+		},
+	},
+	u_d.UserRoleFrontDesk: {
+		ID:   u_d.UserRoleFrontDesk,
+		Name: "Front-Desk",
+		Permissions: []Permission{
+			SubmissionRead, SubmissionCreate, SubmissionUpdate, SubmissionShip, SubmissionHold,
+			OrderManage, CustomerManage, AttachmentManage,
+			OrganizationAll,
+		},
+	},
+}
+
+// APIKeyScopes are the permissions an API key of an organization may be
+// granted; keys cannot manage the organization nor other keys.
+var APIKeyScopes = []Permission{
+	SubmissionRead, SubmissionCreate, SubmissionUpdate,
+	OrderManage, CustomerManage, AttachmentManage, ReportRead,
+}
+
+// IsAPIKeyScope returns true if an API key may be granted the permission.
+func IsAPIKeyScope(p Permission) bool {
+	return contains(APIKeyScopes, p)
+}
+
+func contains(permissions []Permission, p Permission) bool {
+	for _, granted := range permissions {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRole returns true if the role exists.
+func IsRole(role int8) bool {
+	_, ok := Roles[role]
+	return ok
+}
+
+// Has returns true if the role was granted the permission.
+func Has(role int8, p Permission) bool {
+	if r, ok := Roles[role]; ok {
+		return contains(r.Permissions, p)
+	}
+	return false
+}
+
+// sessionUserRole returns the role of the logged in user.
+func sessionUserRole(ctx context.Context) int8 {
+	role, _ := ctx.Value(constants.SessionUserRole).(int8)
+	return role
+}
+
+// IsAllowed returns true if the logged in user has the permission. Requests
+// made with an API key are further limited to the scopes of the key.
+func IsAllowed(ctx context.Context, p Permission) bool {
+	if scopes, ok := ctx.Value(constants.SessionAPIKeyScopes).([]Permission); ok && !contains(scopes, p) {
+		return false
+	}
+	return Has(sessionUserRole(ctx), p)
+}
+
+// Check returns a `403 Forbidden` error unless the logged in user has the
+// permission.
+func Check(ctx context.Context, p Permission) error {
+	if !IsAllowed(ctx, p) {
+		return httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	}
+	return nil
+}
+
+// OrganizationScope returns the organization the logged in user is limited
+// to; false is returned when they may see every organization.
+func OrganizationScope(ctx context.Context) (primitive.ObjectID, bool) {
+	if IsAllowed(ctx, OrganizationAll) {
+		return primitive.NilObjectID, false
+	}
+	organizationID, _ := ctx.Value(constants.SessionUserOrganizationID).(primitive.ObjectID)
+	return organizationID, true
+}
+
+// CheckOrganization returns a `403 Forbidden` error unless the logged in user
+// may work with the records of the organization.
+func CheckOrganization(ctx context.Context, organizationID primitive.ObjectID) error {
+	if scope, ok := OrganizationScope(ctx); ok && scope != organizationID {
+		return httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package permission
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+)
+
+func sessionContext(role int8, organizationID primitive.ObjectID) context.Context {
+	ctx := context.WithValue(context.Background(), constants.SessionUserRole, role)
+	return context.WithValue(ctx, constants.SessionUserOrganizationID, organizationID)
+}
+
+func TestHas(t *testing.T) {
+	tests := []struct {
+		role     int8
+		p        Permission
+		expected bool
+	}{
+		{u_d.UserRoleRoot, SubmissionReject, true},
+		{u_d.UserRoleRetailer, SubmissionCreate, true},
+		{u_d.UserRoleRetailer, SubmissionGrade, false},
+		{u_d.UserRoleCustomer, SubmissionCreate, false},
+		{u_d.UserRoleGrader, SubmissionGrade, true},
+		{u_d.UserRoleGrader, SubmissionShip, false},
+		{u_d.UserRoleFrontDesk, SubmissionShip, true},
+		{u_d.UserRoleFrontDesk, SubmissionGrade, false},
+		{0, SubmissionRead, false},
+	}
+	for _, test := range tests {
+		if actual := Has(test.role, test.p); actual != test.expected {
+			t.Errorf("role %v with %v: expected %v but got %v", test.role, test.p, test.expected, actual)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	orgID := primitive.NewObjectID()
+	if err := Check(sessionContext(u_d.UserRoleRetailer, orgID), OrderManage); err != nil {
+		t.Errorf("expected retailer to manage orders but got %v", err)
+	}
+	if err := Check(sessionContext(u_d.UserRoleCustomer, orgID), OrderManage); err == nil {
+		t.Error("expected customer to be forbidden")
+	}
+	if err := Check(context.Background(), SubmissionRead); err == nil {
+		t.Error("expected anonymous user to be forbidden")
+	}
+}
+
+func TestCheckAPIKeyScopes(t *testing.T) {
+	ctx := sessionContext(u_d.UserRoleRetailer, primitive.NewObjectID())
+	ctx = context.WithValue(ctx, constants.SessionAPIKeyScopes, []Permission{SubmissionRead, SubmissionCreate})
+
+	if err := Check(ctx, SubmissionCreate); err != nil {
+		t.Errorf("expected the key to create submissions but got %v", err)
+	}
+	if err := Check(ctx, OrderManage); err == nil {
+		t.Error("expected the key to be limited to its scopes")
+	}
+	if IsAPIKeyScope(OrganizationManage) {
+		t.Error("expected keys to not manage their organization")
+	}
+}
+
+func TestOrganizationScope(t *testing.T) {
+	orgID, otherOrgID := primitive.NewObjectID(), primitive.NewObjectID()
+
+	if scope, ok := OrganizationScope(sessionContext(u_d.UserRoleRetailer, orgID)); !ok || scope != orgID {
+		t.Errorf("expected retailer to be scoped to %v but got %v", orgID, scope)
+	}
+	if _, ok := OrganizationScope(sessionContext(u_d.UserRoleGrader, orgID)); ok {
+		t.Error("expected grader to see every organization")
+	}
+	if err := CheckOrganization(sessionContext(u_d.UserRoleRetailer, orgID), otherOrgID); err == nil {
+		t.Error("expected retailer to be forbidden from another organization")
+	}
+	if err := CheckOrganization(sessionContext(u_d.UserRoleFrontDesk, orgID), otherOrgID); err != nil {
+		t.Errorf("expected front desk to work with every organization but got %v", err)
+	}
+}
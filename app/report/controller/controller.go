@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	report_s "github.com/LuchaComics/cps-backend/app/report/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+// ReportController Interface for the reporting and analytics business logic
+// controller.
+type ReportController interface {
+	SubmissionVolume(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.VolumePoint, error)
+	GradeDistribution(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.GradeDistribution, error)
+	Turnaround(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.TurnaroundPoint, error)
+	TopRetailers(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.RetailerTotal, error)
+}
+
+type ReportControllerImpl struct {
+	Config       *config.Conf
+	Logger       *slog.Logger
+	ReportStorer report_s.ReportStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	report_storer report_s.ReportStorer,
+) ReportController {
+	s := &ReportControllerImpl{
+		Config:       appCfg,
+		Logger:       loggerp,
+		ReportStorer: report_storer,
+	}
+	s.Logger.Debug("report controller initialization started...")
+	s.Logger.Debug("report controller initialized")
+	return s
+}
+
+// applyTenancy scopes the report to the organization of the logged in user
+// unless they may see every organization; customers have no reports.
+func (c *ReportControllerImpl) applyTenancy(ctx context.Context, f *report_s.ReportFilter) error {
+	if err := permission.Check(ctx, permission.ReportRead); err != nil {
+		return err
+	}
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		f.OrganizationID = organizationID
+	}
+	return nil
+}
+
+func (c *ReportControllerImpl) SubmissionVolume(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.VolumePoint, error) {
+	if err := c.applyTenancy(ctx, f); err != nil {
+		return nil, err
+	}
+	res, err := c.ReportStorer.SubmissionVolume(ctx, f)
+	if err != nil {
+		c.Logger.Error("database submission volume error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *ReportControllerImpl) GradeDistribution(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.GradeDistribution, error) {
+	if err := c.applyTenancy(ctx, f); err != nil {
+		return nil, err
+	}
+	res, err := c.ReportStorer.GradeDistribution(ctx, f)
+	if err != nil {
+		c.Logger.Error("database grade distribution error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *ReportControllerImpl) Turnaround(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.TurnaroundPoint, error) {
+	if err := c.applyTenancy(ctx, f); err != nil {
+		return nil, err
+	}
+	res, err := c.ReportStorer.Turnaround(ctx, f)
+	if err != nil {
+		c.Logger.Error("database turnaround error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *ReportControllerImpl) TopRetailers(ctx context.Context, f *report_s.ReportFilter) ([]*report_s.RetailerTotal, error) {
+	if err := c.applyTenancy(ctx, f); err != nil {
+		return nil, err
+	}
+	res, err := c.ReportStorer.TopRetailers(ctx, f)
+	if err != nil {
+		c.Logger.Error("database top retailers error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,148 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	c "github.com/LuchaComics/cps-backend/config"
+)
+
+// The periods our time series can be bucketed by.
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// ReportFilter narrows down the submissions a report is generated from.
+type ReportFilter struct {
+	OrganizationID primitive.ObjectID
+	ServiceType    int8
+	PublisherName  int8
+	Bucket         string
+	CreatedAtGTE   time.Time
+	CreatedAtLT    time.Time
+	Limit          int64
+}
+
+// VolumePoint is the number of submissions of a service type received
+// within the bucket.
+type VolumePoint struct {
+	Bucket      time.Time `bson:"bucket" json:"bucket"`
+	ServiceType int8      `bson:"service_type" json:"service_type"`
+	Count       int64     `bson:"count" json:"count"`
+}
+
+// GradeDistribution is the number of submissions of a publisher which were
+// given the grade.
+type GradeDistribution struct {
+	PublisherName int8   `bson:"publisher_name" json:"publisher_name"`
+	GradingScale  int8   `bson:"grading_scale" json:"grading_scale"`
+	Grade         string `bson:"grade" json:"grade"`
+	Count         int64  `bson:"count" json:"count"`
+}
+
+// TurnaroundPoint is the average time between receiving and shipping the
+// submissions which were shipped within the bucket.
+type TurnaroundPoint struct {
+	Bucket       time.Time `bson:"bucket" json:"bucket"`
+	Count        int64     `bson:"count" json:"count"`
+	AverageHours float64   `bson:"average_hours" json:"average_hours"`
+}
+
+// RetailerTotal is the number of submissions received from the retailer.
+type RetailerTotal struct {
+	OrganizationID   primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	OrganizationName string             `bson:"organization_name" json:"organization_name"`
+	Count            int64              `bson:"count" json:"count"`
+}
+
+// ReportStorer Interface for the aggregations over our submissions.
+type ReportStorer interface {
+	SubmissionVolume(ctx context.Context, f *ReportFilter) ([]*VolumePoint, error)
+	GradeDistribution(ctx context.Context, f *ReportFilter) ([]*GradeDistribution, error)
+	Turnaround(ctx context.Context, f *ReportFilter) ([]*TurnaroundPoint, error)
+	TopRetailers(ctx context.Context, f *ReportFilter) ([]*RetailerTotal, error)
+}
+
+type ReportStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) ReportStorer {
+	// The reports are read only views of our submissions.
+	uc := client.Database(appCfg.DB.Name).Collection("comic_submissions")
+
+	s := &ReportStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+	return s
+}
+
+// newMatchStage returns the `$match` stage of the filter; the date range is
+// applied to the date field the report is about, if any.
+func newMatchStage(f *ReportFilter, dateField string) bson.D {
+	filter := bson.M{
+		"status": bson.M{"$ne": s_d.StatusArchived},
+	}
+	if !f.OrganizationID.IsZero() {
+		filter["organization_id"] = f.OrganizationID
+	}
+	if f.ServiceType != 0 {
+		filter["service_type"] = f.ServiceType
+	}
+	if f.PublisherName != 0 {
+		filter["publisher_name"] = f.PublisherName
+	}
+	if dateField != "" {
+		filter[dateField] = newDateRange(f)
+	}
+	return bson.D{{Key: "$match", Value: filter}}
+}
+
+// newDateRange returns the condition of a date within the range of the
+// filter.
+func newDateRange(f *ReportFilter) bson.M {
+	dateRange := bson.M{"$type": "date"}
+	if !f.CreatedAtGTE.IsZero() {
+		dateRange["$gte"] = f.CreatedAtGTE
+	}
+	if !f.CreatedAtLT.IsZero() {
+		dateRange["$lt"] = f.CreatedAtLT
+	}
+	return dateRange
+}
+
+// newBucketExpression truncates the date field to the start of its bucket;
+// weeks start on monday.
+func newBucketExpression(f *ReportFilter, dateField string) bson.M {
+	dateTrunc := bson.M{"date": "$" + dateField, "unit": f.Bucket, "timezone": "UTC"}
+	if f.Bucket == BucketWeek {
+		dateTrunc["startOfWeek"] = "monday"
+	}
+	return bson.M{"$dateTrunc": dateTrunc}
+}
+
+func (impl ReportStorerImpl) aggregate(ctx context.Context, pipeline mongo.Pipeline, results interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	impl.Logger.Debug("aggregating pipeline:", slog.Any("pipeline", pipeline))
+
+	cursor, err := impl.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, results)
+}
@@ -0,0 +1,147 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+)
+
+// SubmissionVolume returns the number of submissions received per bucket and
+// service type.
+func (impl ReportStorerImpl) SubmissionVolume(ctx context.Context, f *ReportFilter) ([]*VolumePoint, error) {
+	pipeline := mongo.Pipeline{
+		newMatchStage(f, "created_at"),
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"bucket":       newBucketExpression(f, "created_at"),
+				"service_type": "$service_type",
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			"bucket":       "$_id.bucket",
+			"service_type": "$_id.service_type",
+			"count":        1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "bucket", Value: 1}, {Key: "service_type", Value: 1}}}},
+	}
+
+	results := []*VolumePoint{}
+	if err := impl.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GradeDistribution returns the number of submissions per publisher and
+// grade; the grade is read from the field of the grading scale used.
+func (impl ReportStorerImpl) GradeDistribution(ctx context.Context, f *ReportFilter) ([]*GradeDistribution, error) {
+	grade := bson.M{"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{"case": bson.M{"$eq": bson.A{"$grading_scale", s_d.GradingScaleLetter}}, "then": "$overall_letter_grade"},
+			bson.M{"case": bson.M{"$eq": bson.A{"$grading_scale", s_d.GradingScaleNumber}}, "then": bson.M{"$toString": "$overall_number_grade"}},
+			bson.M{"case": bson.M{"$eq": bson.A{"$grading_scale", s_d.GradingScaleCPSPercentage}}, "then": bson.M{"$toString": "$cps_percentage_grade"}},
+		},
+		"default": "",
+	}}
+
+	pipeline := mongo.Pipeline{
+		newMatchStage(f, "created_at"),
+		{{Key: "$match", Value: bson.M{"grading_scale": bson.M{"$gt": 0}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"publisher_name": "$publisher_name",
+				"grading_scale":  "$grading_scale",
+				"grade":          grade,
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":            0,
+			"publisher_name": "$_id.publisher_name",
+			"grading_scale":  "$_id.grading_scale",
+			"grade":          "$_id.grade",
+			"count":          1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "publisher_name", Value: 1}, {Key: "grading_scale", Value: 1}, {Key: "grade", Value: 1}}}},
+	}
+
+	results := []*GradeDistribution{}
+	if err := impl.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Turnaround returns the average hours it took to ship the submissions per
+// bucket of the date they were shipped on, which is read from the status
+// history of every submission.
+func (impl ReportStorerImpl) Turnaround(ctx context.Context, f *ReportFilter) ([]*TurnaroundPoint, error) {
+	shippedAt := bson.M{"$arrayElemAt": bson.A{
+		bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$status_history", bson.A{}}},
+				"cond":  bson.M{"$eq": bson.A{"$$this.to", s_d.StatusShipped}},
+			}},
+			"in": "$$this.created_at",
+		}},
+		-1,
+	}}
+
+	pipeline := mongo.Pipeline{
+		newMatchStage(f, ""),
+		{{Key: "$addFields", Value: bson.M{"shipped_at": shippedAt}}},
+		{{Key: "$match", Value: bson.M{"shipped_at": newDateRange(f)}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   newBucketExpression(f, "shipped_at"),
+			"count": bson.M{"$sum": 1},
+			"average_hours": bson.M{"$avg": bson.M{"$divide": bson.A{
+				bson.M{"$subtract": bson.A{"$shipped_at", "$created_at"}},
+				3600000,
+			}}},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":           0,
+			"bucket":        "$_id",
+			"count":         1,
+			"average_hours": 1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "bucket", Value: 1}}}},
+	}
+
+	results := []*TurnaroundPoint{}
+	if err := impl.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// TopRetailers returns the organizations which sent us the most submissions.
+func (impl ReportStorerImpl) TopRetailers(ctx context.Context, f *ReportFilter) ([]*RetailerTotal, error) {
+	pipeline := mongo.Pipeline{
+		newMatchStage(f, "created_at"),
+		{{Key: "$group", Value: bson.M{
+			"_id":               "$organization_id",
+			"organization_name": bson.M{"$last": "$organization_name"},
+			"count":             bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":               0,
+			"organization_id":   "$_id",
+			"organization_name": 1,
+			"count":             1,
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "organization_name", Value: 1}}}},
+		{{Key: "$limit", Value: f.Limit}},
+	}
+
+	results := []*RetailerTotal{}
+	if err := impl.aggregate(ctx, pipeline, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/adapter/cache/redis"
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+	"github.com/LuchaComics/cps-backend/provider/uuid"
+)
+
+const (
+	// lastSeenInterval is how often the last seen time of a session is saved.
+	lastSeenInterval = time.Minute
+
+	// userVersionExpiry is how long the version of a user is kept after their
+	// last update; sessions unused for longer have expired anyway.
+	userVersionExpiry = 14 * 24 * time.Hour
+
+	userVersionCacheKeyPrefix = "user_version:"
+)
+
+// SessionController Interface for the login sessions of our users. A session
+// is the user record kept in our cache under a random key, which is carried
+// by the JWT tokens, and indexed by user in our database. The refresh tokens
+// of a session form a family: each can be used once, and using one twice
+// ends the session. The user saved in a session is reloaded whenever the user
+// was updated since.
+type SessionController interface {
+	Start(ctx context.Context, u *user_s.User, expiry time.Duration) (string, string, error)
+	Rotate(ctx context.Context, sessionID string, refreshTokenID string, expiry time.Duration) (*user_s.User, string, error)
+	GetUser(ctx context.Context, sessionID string) (*user_s.User, error)
+	Touch(ctx context.Context, sessionID string)
+	End(ctx context.Context, sessionID string) error
+	ListByUserID(ctx context.Context, userID primitive.ObjectID) ([]*session_s.Session, error)
+	RevokeByID(ctx context.Context, userID primitive.ObjectID, id primitive.ObjectID) error
+	RevokeAllByUserID(ctx context.Context, userID primitive.ObjectID, exceptSessionID string) error
+}
+
+type SessionControllerImpl struct {
+	Config        *config.Conf
+	Logger        *slog.Logger
+	UUID          uuid.Provider
+	Cache         redis.Cacher
+	SessionStorer session_s.SessionStorer
+	UserStorer    user_s.UserStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	uuidp uuid.Provider,
+	cache redis.Cacher,
+	session_storer session_s.SessionStorer,
+	usr_storer user_s.UserStorer,
+) SessionController {
+	s := &SessionControllerImpl{
+		Config:        appCfg,
+		Logger:        loggerp,
+		UUID:          uuidp,
+		Cache:         cache,
+		SessionStorer: session_storer,
+		UserStorer:    usr_storer,
+	}
+	usr_storer.AddUpdatedHook(s.onUserUpdated)
+	s.Logger.Debug("session controller initialized")
+	return s
+}
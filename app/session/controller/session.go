@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+var errSessionExpired = httperror.NewForSingleField(http.StatusUnauthorized, "message", "session expired - please log in again")
+
+// Start saves the user into a new session and returns its key along with the
+// id of its first refresh token; the device and IP address of the request are
+// recorded for the user to see.
+func (impl *SessionControllerImpl) Start(ctx context.Context, u *user_s.User, expiry time.Duration) (string, string, error) {
+	uBin, err := json.Marshal(u)
+	if err != nil {
+		impl.Logger.Error("marshalling error", slog.Any("err", err))
+		return "", "", err
+	}
+
+	sessionID := impl.UUID.NewUUID()
+	if err := impl.Cache.SetWithExpiry(ctx, sessionID, uBin, expiry); err != nil {
+		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		return "", "", err
+	}
+
+	ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+	userAgent, _ := ctx.Value(constants.SessionUserAgent).(string)
+	now := time.Now()
+	m := &session_s.Session{
+		ID:             primitive.NewObjectID(),
+		SessionID:      sessionID,
+		RefreshTokenID: impl.UUID.NewUUID(),
+		UserID:         u.ID,
+		UserAgent:      userAgent,
+		IPAddress:      ipAddress,
+		CreatedAt:      now,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(expiry),
+	}
+	if err := impl.SessionStorer.Create(ctx, m); err != nil {
+		impl.Logger.Error("database create error", slog.Any("error", err))
+		return "", "", err
+	}
+	return sessionID, m.RefreshTokenID, nil
+}
+
+// Rotate consumes the refresh token of the session and returns the user along
+// with the id of the next refresh token; the session is kept alive for the
+// expiry from now. A refresh token used twice was stolen by someone, so the
+// session is ended for whoever holds it.
+func (impl *SessionControllerImpl) Rotate(ctx context.Context, sessionID string, refreshTokenID string, expiry time.Duration) (*user_s.User, string, error) {
+	uBin, err := impl.Cache.Get(ctx, sessionID)
+	if err != nil {
+		impl.Logger.Error("cache get error", slog.Any("err", err))
+		return nil, "", err
+	}
+	if uBin == nil { // Expired, logged out or revoked.
+		return nil, "", errSessionExpired
+	}
+	var u *user_s.User
+	if err := json.Unmarshal(uBin, &u); err != nil {
+		impl.Logger.Error("unmarshal error", slog.Any("err", err))
+		return nil, "", err
+	}
+
+	newRefreshTokenID := impl.UUID.NewUUID()
+	ok, err := impl.SessionStorer.RotateRefreshTokenID(ctx, sessionID, refreshTokenID, newRefreshTokenID, time.Now().Add(expiry))
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		ipAddress, _ := ctx.Value(constants.SessionIPAddress).(string)
+		impl.Logger.Warn("security event: refresh token reuse detected, revoking session",
+			slog.Any("user_id", u.ID),
+			slog.String("session_id", sessionID),
+			slog.String("ip_address", ipAddress))
+		if err := impl.End(ctx, sessionID); err != nil {
+			return nil, "", err
+		}
+		return nil, "", errSessionExpired
+	}
+
+	if err := impl.Cache.SetWithExpiry(ctx, sessionID, uBin, expiry); err != nil {
+		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		return nil, "", err
+	}
+	return u, newRefreshTokenID, nil
+}
+
+// Touch records the session was just used. Errors are only logged as they
+// must not fail the request.
+func (impl *SessionControllerImpl) Touch(ctx context.Context, sessionID string) {
+	if err := impl.SessionStorer.UpdateLastSeenAtBySessionID(ctx, sessionID, time.Now(), lastSeenInterval); err != nil {
+		impl.Logger.Warn("touch session error", slog.Any("error", err))
+	}
+}
+
+// End ends the session, ex: when the user logs out.
+func (impl *SessionControllerImpl) End(ctx context.Context, sessionID string) error {
+	m, err := impl.SessionStorer.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		impl.Logger.Error("database get by session id error", slog.Any("error", err))
+		return err
+	}
+	if m == nil { // Sessions started before we indexed them.
+		if err := impl.Cache.Delete(ctx, sessionID); err != nil {
+			impl.Logger.Error("cache delete error", slog.Any("err", err))
+			return err
+		}
+		return nil
+	}
+	return impl.revoke(ctx, m)
+}
+
+// ListByUserID returns the active sessions of the user; the session of the
+// request is flagged as current.
+func (impl *SessionControllerImpl) ListByUserID(ctx context.Context, userID primitive.ObjectID) ([]*session_s.Session, error) {
+	ms, err := impl.SessionStorer.ListByUserID(ctx, userID)
+	if err != nil {
+		impl.Logger.Error("database list by user id error", slog.Any("error", err))
+		return nil, err
+	}
+	currentSessionID, _ := ctx.Value(constants.SessionID).(string)
+	for _, m := range ms {
+		m.IsCurrent = m.SessionID == currentSessionID
+	}
+	return ms, nil
+}
+
+// RevokeByID ends the session of the user.
+func (impl *SessionControllerImpl) RevokeByID(ctx context.Context, userID primitive.ObjectID, id primitive.ObjectID) error {
+	ms, err := impl.SessionStorer.ListByUserID(ctx, userID)
+	if err != nil {
+		impl.Logger.Error("database list by user id error", slog.Any("error", err))
+		return err
+	}
+	for _, m := range ms {
+		if m.ID == id {
+			return impl.revoke(ctx, m)
+		}
+	}
+	return httperror.NewForNotFoundWithSingleField("session_id", "session does not exist")
+}
+
+// RevokeAllByUserID ends every session of the user except the one given, if
+// any, ex: to keep the user logged in where they changed their password.
+func (impl *SessionControllerImpl) RevokeAllByUserID(ctx context.Context, userID primitive.ObjectID, exceptSessionID string) error {
+	ms, err := impl.SessionStorer.ListByUserID(ctx, userID)
+	if err != nil {
+		impl.Logger.Error("database list by user id error", slog.Any("error", err))
+		return err
+	}
+	count := 0
+	for _, m := range ms {
+		if m.SessionID == exceptSessionID {
+			continue
+		}
+		if err := impl.revoke(ctx, m); err != nil {
+			return err
+		}
+		count++
+	}
+	impl.Logger.Info("sessions revoked", slog.Any("user_id", userID), slog.Int("count", count))
+	return nil
+}
+
+func (impl *SessionControllerImpl) revoke(ctx context.Context, m *session_s.Session) error {
+	if err := impl.Cache.Delete(ctx, m.SessionID); err != nil {
+		impl.Logger.Error("cache delete error", slog.Any("err", err))
+		return err
+	}
+	if err := impl.SessionStorer.DeleteByID(ctx, m.ID); err != nil {
+		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
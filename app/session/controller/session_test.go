@@ -0,0 +1,237 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/adapter/cache/redis"
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/provider/uuid"
+)
+
+type fakeCache struct {
+	redis.Cacher
+	values map[string][]byte
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return c.values[key], nil
+}
+
+func (c *fakeCache) SetWithExpiry(ctx context.Context, key string, val []byte, expiry time.Duration) error {
+	c.values[key] = val
+	return nil
+}
+
+func (c *fakeCache) Delete(ctx context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+type fakeSessionStorer struct {
+	session_s.SessionStorer
+	sessions map[primitive.ObjectID]*session_s.Session
+}
+
+func (s *fakeSessionStorer) Create(ctx context.Context, m *session_s.Session) error {
+	s.sessions[m.ID] = m
+	return nil
+}
+
+func (s *fakeSessionStorer) GetBySessionID(ctx context.Context, sessionID string) (*session_s.Session, error) {
+	for _, m := range s.sessions {
+		if m.SessionID == sessionID {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fakeSessionStorer) ListByUserID(ctx context.Context, userID primitive.ObjectID) ([]*session_s.Session, error) {
+	var ms []*session_s.Session
+	for _, m := range s.sessions {
+		if m.UserID == userID {
+			ms = append(ms, m)
+		}
+	}
+	return ms, nil
+}
+
+func (s *fakeSessionStorer) RotateRefreshTokenID(ctx context.Context, sessionID string, refreshTokenID string, newRefreshTokenID string, expiresAt time.Time) (bool, error) {
+	m, _ := s.GetBySessionID(ctx, sessionID)
+	if m == nil || m.RefreshTokenID != refreshTokenID {
+		return false, nil
+	}
+	m.RefreshTokenID = newRefreshTokenID
+	m.ExpiresAt = expiresAt
+	return true, nil
+}
+
+func (s *fakeSessionStorer) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	delete(s.sessions, id)
+	return nil
+}
+
+type fakeUserStorer struct {
+	user_s.UserStorer
+	users map[primitive.ObjectID]*user_s.User
+	hooks []user_s.UpdatedHook
+}
+
+func (s *fakeUserStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*user_s.User, error) {
+	if m, ok := s.users[id]; ok {
+		c := *m
+		return &c, nil
+	}
+	return nil, nil
+}
+
+func (s *fakeUserStorer) UpdateByID(ctx context.Context, m *user_s.User) error {
+	m.Version++
+	c := *m
+	s.users[m.ID] = &c
+	for _, hook := range s.hooks {
+		hook(ctx, m)
+	}
+	return nil
+}
+
+func (s *fakeUserStorer) AddUpdatedHook(hook user_s.UpdatedHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+func newTestController() (SessionController, *fakeCache, *fakeSessionStorer) {
+	impl, cache, storer, _ := newTestControllerWithUsers()
+	return impl, cache, storer
+}
+
+func newTestControllerWithUsers() (SessionController, *fakeCache, *fakeSessionStorer, *fakeUserStorer) {
+	cache := &fakeCache{values: map[string][]byte{}}
+	storer := &fakeSessionStorer{sessions: map[primitive.ObjectID]*session_s.Session{}}
+	users := &fakeUserStorer{users: map[primitive.ObjectID]*user_s.User{}}
+	impl := NewController(nil, slog.New(slog.NewTextHandler(os.Stderr)), uuid.NewProvider(), cache, storer, users)
+	return impl, cache, storer, users
+}
+
+func TestRevokeAllByUserID(t *testing.T) {
+	impl, cache, storer := newTestController()
+
+	ctx := context.WithValue(context.Background(), constants.SessionIPAddress, "127.0.0.1")
+	ctx = context.WithValue(ctx, constants.SessionUserAgent, "test")
+	u := &user_s.User{ID: primitive.NewObjectID()}
+	other := &user_s.User{ID: primitive.NewObjectID()}
+
+	current, _, err := impl.Start(ctx, u, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := impl.Start(ctx, u, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	otherSessionID, _, err := impl.Start(ctx, other, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms, err := impl.ListByUserID(context.WithValue(ctx, constants.SessionID, current), u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 2 {
+		t.Fatalf("expected 2 sessions but got %v", len(ms))
+	}
+	for _, m := range ms {
+		if m.IsCurrent != (m.SessionID == current) || m.IPAddress != "127.0.0.1" || m.UserAgent != "test" {
+			t.Errorf("unexpected session %+v", m)
+		}
+	}
+
+	// Only the sessions of the user other than the current one are revoked.
+	if err := impl.RevokeAllByUserID(ctx, u.ID, current); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.values) != 2 || cache.values[current] == nil || cache.values[otherSessionID] == nil {
+		t.Errorf("expected the current and other user sessions to remain but got %v", len(cache.values))
+	}
+	if ms, _ := impl.ListByUserID(ctx, u.ID); len(ms) != 1 {
+		t.Errorf("expected 1 session but got %v", len(ms))
+	}
+
+	if err := impl.End(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+	if cache.values[current] != nil || len(storer.sessions) != 1 {
+		t.Errorf("expected the session to end")
+	}
+}
+
+func TestRotate(t *testing.T) {
+	impl, cache, storer := newTestController()
+	ctx := context.Background()
+	u := &user_s.User{ID: primitive.NewObjectID()}
+
+	sessionID, refreshTokenID, err := impl.Start(ctx, u, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ru, nextRefreshTokenID, err := impl.Rotate(ctx, sessionID, refreshTokenID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ru.ID != u.ID || nextRefreshTokenID == "" || nextRefreshTokenID == refreshTokenID {
+		t.Fatalf("expected the user and a new refresh token but got %v and %v", ru.ID, nextRefreshTokenID)
+	}
+
+	// Using the old refresh token again ends the session, so neither refresh
+	// token works anymore.
+	if _, _, err := impl.Rotate(ctx, sessionID, refreshTokenID, time.Hour); err == nil {
+		t.Fatal("expected reused refresh token to fail")
+	}
+	if cache.values[sessionID] != nil || len(storer.sessions) != 0 {
+		t.Errorf("expected the session to be revoked")
+	}
+	if _, _, err := impl.Rotate(ctx, sessionID, nextRefreshTokenID, time.Hour); err == nil {
+		t.Error("expected refresh token of revoked session to fail")
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	impl, _, _, users := newTestControllerWithUsers()
+	ctx := context.Background()
+	u := &user_s.User{ID: primitive.NewObjectID(), Role: user_s.UserRoleRetailer, Status: user_s.UserStatusActive}
+	users.users[u.ID] = u
+
+	sessionID, _, err := impl.Start(ctx, u, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Updates made after the session started are picked up right away.
+	nu, _ := users.GetByID(ctx, u.ID)
+	nu.Status = user_s.UserStatusArchived
+	if err := users.UpdateByID(ctx, nu); err != nil {
+		t.Fatal(err)
+	}
+	su, err := impl.GetUser(ctx, sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if su.Status != user_s.UserStatusArchived || su.Version != nu.Version {
+		t.Errorf("expected the updated user but got status %v and version %v", su.Status, su.Version)
+	}
+
+	// Deleted users lose their session.
+	delete(users.users, u.ID)
+	users.hooks[0](ctx, &user_s.User{ID: u.ID, Version: nu.Version + 1})
+	if su, err := impl.GetUser(ctx, sessionID); err != nil || su != nil {
+		t.Errorf("expected no user but got %v with error %v", su, err)
+	}
+}
@@ -0,0 +1,82 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+)
+
+// onUserUpdated saves the version of the user for `GetUser` to compare with
+// the version saved in each of their sessions.
+func (impl *SessionControllerImpl) onUserUpdated(ctx context.Context, u *user_s.User) {
+	version := []byte(strconv.FormatInt(u.Version, 10))
+	if err := impl.Cache.SetWithExpiry(ctx, userVersionCacheKeyPrefix+u.ID.Hex(), version, userVersionExpiry); err != nil {
+		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+	}
+}
+
+// GetUser returns the user of the session or nil if the session expired. If
+// the user was updated since the session started then the user is reloaded
+// from our database so changes to their role, status or organization take
+// effect right away.
+func (impl *SessionControllerImpl) GetUser(ctx context.Context, sessionID string) (*user_s.User, error) {
+	uBin, err := impl.Cache.Get(ctx, sessionID)
+	if err != nil {
+		impl.Logger.Error("cache get error", slog.Any("err", err))
+		return nil, err
+	}
+	if uBin == nil { // Expired, logged out or revoked.
+		return nil, nil
+	}
+	var u *user_s.User
+	if err := json.Unmarshal(uBin, &u); err != nil {
+		impl.Logger.Error("unmarshalling failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	version, err := impl.Cache.Get(ctx, userVersionCacheKeyPrefix+u.ID.Hex())
+	if err != nil {
+		impl.Logger.Error("cache get error", slog.Any("err", err))
+		return nil, err
+	}
+	if version == nil || string(version) == strconv.FormatInt(u.Version, 10) {
+		return u, nil
+	}
+	return impl.reloadUser(ctx, sessionID, u)
+}
+
+// reloadUser saves the latest record of the user into their session.
+func (impl *SessionControllerImpl) reloadUser(ctx context.Context, sessionID string, u *user_s.User) (*user_s.User, error) {
+	m, err := impl.SessionStorer.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		impl.Logger.Error("database get by session id error", slog.Any("error", err))
+		return nil, err
+	}
+	nu, err := impl.UserStorer.GetByID(ctx, u.ID)
+	if err != nil {
+		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if m == nil || nu == nil || !m.ExpiresAt.After(time.Now()) { // Sessions started before we indexed them or deleted users.
+		return nil, impl.End(ctx, sessionID)
+	}
+
+	uBin, err := json.Marshal(nu)
+	if err != nil {
+		impl.Logger.Error("marshalling error", slog.Any("err", err))
+		return nil, err
+	}
+	if err := impl.Cache.SetWithExpiry(ctx, sessionID, uBin, time.Until(m.ExpiresAt)); err != nil {
+		impl.Logger.Error("cache set with expiry error", slog.Any("err", err))
+		return nil, err
+	}
+	impl.Logger.Debug("session user reloaded",
+		slog.Any("user_id", nu.ID),
+		slog.Int64("version", nu.Version))
+	return nu, nil
+}
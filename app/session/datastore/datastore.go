@@ -0,0 +1,70 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+
+	c "github.com/LuchaComics/cps-backend/config"
+)
+
+// Session indexes the sessions we keep in our cache by user so they can be
+// listed and revoked; the cache remains the source of truth for whether a
+// session is still valid.
+type Session struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	SessionID      string             `bson:"session_id" json:"-"`       // The key of the session in our cache.
+	RefreshTokenID string             `bson:"refresh_token_id" json:"-"` // The only refresh token of the session which can still be used.
+	UserID         primitive.ObjectID `bson:"user_id" json:"user_id"`
+	UserAgent      string             `bson:"user_agent" json:"user_agent"`
+	IPAddress      string             `bson:"ip_address" json:"ip_address"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	LastSeenAt     time.Time          `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt      time.Time          `bson:"expires_at" json:"expires_at"`
+	IsCurrent      bool               `bson:"-" json:"is_current"` // (Optional, added by endpoint)
+}
+
+// SessionStorer Interface for the index of our sessions.
+type SessionStorer interface {
+	Create(ctx context.Context, m *Session) error
+	GetBySessionID(ctx context.Context, sessionID string) (*Session, error)
+	ListByUserID(ctx context.Context, userID primitive.ObjectID) ([]*Session, error)
+	UpdateByID(ctx context.Context, m *Session) error
+	UpdateLastSeenAtBySessionID(ctx context.Context, sessionID string, lastSeenAt time.Time, interval time.Duration) error
+	RotateRefreshTokenID(ctx context.Context, sessionID string, refreshTokenID string, newRefreshTokenID string, expiresAt time.Time) (bool, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+type SessionStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) SessionStorer {
+	uc := client.Database(appCfg.DB.Name).Collection("sessions")
+
+	// The following few lines of code will create the index for our app for this
+	// colleciton. Sessions are removed by MongoDB once they expired.
+	if _, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "session_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	return &SessionStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+}
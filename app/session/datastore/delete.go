@@ -0,0 +1,17 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+)
+
+func (impl SessionStorerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := impl.Collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
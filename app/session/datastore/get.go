@@ -0,0 +1,23 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slog"
+)
+
+func (impl SessionStorerImpl) GetBySessionID(ctx context.Context, sessionID string) (*Session, error) {
+	var result Session
+	err := impl.Collection.FindOne(ctx, bson.M{"session_id": sessionID}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get session error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,32 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+// ListByUserID returns the sessions of the user which have not expired,
+// newest first.
+func (impl SessionStorerImpl) ListByUserID(ctx context.Context, userID primitive.ObjectID) ([]*Session, error) {
+	filter := bson.M{"user_id": userID, "expires_at": bson.M{"$gt": time.Now()}}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list sessions error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Session{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database decode sessions error", slog.Any("error", err))
+		return nil, err
+	}
+	return results, nil
+}
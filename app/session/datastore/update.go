@@ -0,0 +1,52 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/exp/slog"
+)
+
+func (impl SessionStorerImpl) UpdateByID(ctx context.Context, m *Session) error {
+	filter := bson.M{"_id": m.ID}
+	update := bson.M{"$set": m}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// UpdateLastSeenAtBySessionID only writes if the session was last seen more
+// than the interval ago so we do not write on every request.
+func (impl SessionStorerImpl) UpdateLastSeenAtBySessionID(ctx context.Context, sessionID string, lastSeenAt time.Time, interval time.Duration) error {
+	filter := bson.M{"session_id": sessionID, "last_seen_at": bson.M{"$lt": lastSeenAt.Add(-interval)}}
+	update := bson.M{"$set": bson.M{"last_seen_at": lastSeenAt}}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update last seen at error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+// RotateRefreshTokenID replaces the refresh token of the session only if it is
+// still the one given, so each refresh token can be used once even when
+// requests race; false is returned if it was already used.
+func (impl SessionStorerImpl) RotateRefreshTokenID(ctx context.Context, sessionID string, refreshTokenID string, newRefreshTokenID string, expiresAt time.Time) (bool, error) {
+	filter := bson.M{"session_id": sessionID, "refresh_token_id": refreshTokenID}
+	update := bson.M{"$set": bson.M{
+		"refresh_token_id": newRefreshTokenID,
+		"last_seen_at":     time.Now(),
+		"expires_at":       expiresAt,
+	}}
+
+	res, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		impl.Logger.Error("database rotate refresh token id error", slog.Any("error", err))
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
@@ -7,18 +7,15 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
-	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 func (impl *UserControllerImpl) ArchiveByID(ctx context.Context, id primitive.ObjectID) (*user_s.User, error) {
-	// Extract from our session the following data.
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
-
 	// Apply filtering based on ownership and role.
-	if userRole != user_s.UserRoleRoot {
-		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return nil, err
 	}
 
 	// Lookup the user in our database, else return a `400 Bad Request` error.
@@ -45,5 +42,10 @@ func (impl *UserControllerImpl) ArchiveByID(ctx context.Context, id primitive.Ob
 		impl.Logger.Error("user update by id error", slog.Any("error", err))
 		return nil, err
 	}
+
+	// Archived users must not stay logged in.
+	if err := impl.SessionController.RevokeAllByUserID(ctx, ou.ID, ""); err != nil {
+		return nil, err
+	}
 	return ou, nil
 }
@@ -7,6 +7,7 @@ import (
 	"golang.org/x/exp/slog"
 
 	organization_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	session_c "github.com/LuchaComics/cps-backend/app/session/controller"
 	domain "github.com/LuchaComics/cps-backend/app/user/datastore"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config"
@@ -25,6 +26,7 @@ type UserController interface {
 	ListAsSelectOptionByFilter(ctx context.Context, f *user_s.UserListFilter) ([]*user_s.UserAsSelectOption, error)
 	UpdateByID(ctx context.Context, request *UserUpdateRequestIDO) (*user_s.User, error)
 	CreateComment(ctx context.Context, customerID primitive.ObjectID, content string) (*user_s.User, error)
+	RevokeSessionsByID(ctx context.Context, id primitive.ObjectID) error
 	//TODO: Add more...
 }
 
@@ -35,6 +37,7 @@ type UserControllerImpl struct {
 	Password           password.Provider
 	OrganizationStorer organization_s.OrganizationStorer
 	UserStorer         user_s.UserStorer
+	SessionController  session_c.SessionController
 }
 
 func NewController(
@@ -44,6 +47,7 @@ func NewController(
 	passwordp password.Provider,
 	org_storer organization_s.OrganizationStorer,
 	usr_storer user_s.UserStorer,
+	session_controller session_c.SessionController,
 ) UserController {
 	s := &UserControllerImpl{
 		Config:             appCfg,
@@ -52,6 +56,7 @@ func NewController(
 		Password:           passwordp,
 		OrganizationStorer: org_storer,
 		UserStorer:         usr_storer,
+		SessionController:  session_controller,
 	}
 	s.Logger.Debug("user controller initialization started...")
 
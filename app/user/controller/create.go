@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
@@ -73,13 +74,12 @@ func (impl *UserControllerImpl) Create(ctx context.Context, requestData *UserCre
 	}
 
 	// Extract from our session the following data.
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
 	userID := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
 	userName, _ := ctx.Value(constants.SessionUserName).(string)
 
 	// Apply filtering based on ownership and role.
-	if userRole != user_s.UserRoleRoot {
-		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return nil, err
 	}
 
 	// Lookup the user in our database, else return a `400 Bad Request` error.
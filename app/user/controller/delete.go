@@ -3,20 +3,17 @@ package controller
 import (
 	"context"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
-	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 )
 
 func (impl *UserControllerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
-	// Extract from our session the following data.
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
-
 	// Apply filtering based on ownership and role.
-	if userRole != user_s.UserRoleRoot {
-		return httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return err
 	}
 
 	// STEP 1: Lookup the record or error.
@@ -41,5 +38,5 @@ func (impl *UserControllerImpl) DeleteByID(ctx context.Context, id primitive.Obj
 		impl.Logger.Error("database delete by id error", slog.Any("error", err))
 		return err
 	}
-	return nil
+	return impl.SessionController.RevokeAllByUserID(ctx, id, "")
 }
@@ -3,21 +3,17 @@ package controller
 import (
 	"context"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	domain "github.com/LuchaComics/cps-backend/app/user/datastore"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
-	"github.com/LuchaComics/cps-backend/config/constants"
-	"github.com/LuchaComics/cps-backend/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 )
 
 func (c *UserControllerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*user_s.User, error) {
-	// Extract from our session the following data.
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
-
 	// Apply filtering based on ownership and role.
-	if userRole != user_s.UserRoleRoot {
-		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return nil, err
 	}
 
 	// Retrieve from our database the record for the specific id.
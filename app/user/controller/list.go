@@ -5,18 +5,14 @@ import (
 
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
-	"github.com/LuchaComics/cps-backend/config/constants"
-	"github.com/LuchaComics/cps-backend/utils/httperror"
 )
 
 func (c *UserControllerImpl) ListByFilter(ctx context.Context, f *user_s.UserListFilter) (*user_s.UserListResult, error) {
-	// Extract from our session the following data.
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
-
 	// Apply filtering based on ownership and role.
-	if userRole != user_s.UserRoleRoot {
-		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return nil, err
 	}
 
 	c.Logger.Debug("listing using filter options:",
@@ -40,12 +36,9 @@ func (c *UserControllerImpl) ListByFilter(ctx context.Context, f *user_s.UserLis
 }
 
 func (c *UserControllerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *user_s.UserListFilter) ([]*user_s.UserAsSelectOption, error) {
-	// Extract from our session the following data.
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
-
 	// Apply filtering based on ownership and role.
-	if userRole != user_s.UserRoleRoot {
-		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return nil, err
 	}
 
 	c.Logger.Debug("listing using filter options:",
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// RevokeSessionsByID logs the user out everywhere, ex: when their account was
+// compromised.
+func (impl *UserControllerImpl) RevokeSessionsByID(ctx context.Context, id primitive.ObjectID) error {
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return err
+	}
+
+	u, err := impl.UserStorer.GetByID(ctx, id)
+	if err != nil {
+		impl.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if u == nil {
+		return httperror.NewForNotFoundWithSingleField("id", "does not exist")
+	}
+	return impl.SessionController.RevokeAllByUserID(ctx, u.ID, "")
+}
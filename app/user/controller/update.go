@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/exp/slog"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config/constants"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
@@ -76,12 +77,9 @@ func (impl *UserControllerImpl) UpdateByID(ctx context.Context, requestData *Use
 	userID, _ := ctx.Value(constants.SessionUserID).(primitive.ObjectID)
 	userName, _ := ctx.Value(constants.SessionUserName).(string)
 
-	// Extract from our session the following data.
-	userRole := ctx.Value(constants.SessionUserRole).(int8)
-
 	// Apply filtering based on ownership and role.
-	if userRole != user_s.UserRoleRoot {
-		return nil, httperror.NewForForbiddenWithSingleField("message", "you do not have permission")
+	if err := permission.Check(ctx, permission.UserManage); err != nil {
+		return nil, err
 	}
 
 	// Lookup the user in our database, else return a `400 Bad Request` error.
@@ -106,6 +104,14 @@ func (impl *UserControllerImpl) UpdateByID(ctx context.Context, requestData *Use
 		return nil, httperror.NewForBadRequestWithSingleField("organization_id", "organization does not exist")
 	}
 
+	// Security: Prevent the role or status of root user(s) from changing, the
+	// same as they cannot be archived or deleted.
+	isStatusChanged := nu.Status != 0 && nu.Status != ou.Status
+	if ou.Role == user_s.UserRoleRoot && (nu.Role != ou.Role || isStatusChanged) {
+		impl.Logger.Warn("root user(s) role or status cannot be changed error")
+		return nil, httperror.NewForForbiddenWithSingleField("role", "root user(s) role or status cannot be changed")
+	}
+
 	ou.OrganizationID = o.ID
 	ou.OrganizationName = o.Name
 	ou.FirstName = nu.FirstName
@@ -123,6 +129,10 @@ func (impl *UserControllerImpl) UpdateByID(ctx context.Context, requestData *Use
 	ou.HowDidYouHearAboutUs = nu.HowDidYouHearAboutUs
 	ou.HowDidYouHearAboutUsOther = nu.HowDidYouHearAboutUsOther
 	ou.AgreePromotionsEmail = nu.AgreePromotionsEmail
+	ou.Role = nu.Role
+	if isStatusChanged {
+		ou.Status = nu.Status
+	}
 	ou.ModifiedByUserID = userID
 	ou.ModifiedByName = userName
 
@@ -130,5 +140,12 @@ func (impl *UserControllerImpl) UpdateByID(ctx context.Context, requestData *Use
 		impl.Logger.Error("user update by id error", slog.Any("error", err))
 		return nil, err
 	}
+
+	// Archived users must not stay logged in.
+	if isStatusChanged && ou.Status == user_s.UserStatusArchived {
+		if err := impl.SessionController.RevokeAllByUserID(ctx, ou.ID, ""); err != nil {
+			return nil, err
+		}
+	}
 	return ou, nil
 }
@@ -19,6 +19,8 @@ const (
 	UserRoleRoot       = 1
 	UserRoleRetailer   = 2
 	UserRoleCustomer   = 3
+	UserRoleGrader     = 4
+	UserRoleFrontDesk  = 5
 )
 
 type User struct {
@@ -59,6 +61,13 @@ type User struct {
 	ModifiedByName            string             `bson:"modified_by_name" json:"modified_by_name"`
 	Status                    int8               `bson:"status" json:"status"`
 	Comments                  []*UserComment     `bson:"comments" json:"comments"`
+	OTPEnabled                bool               `bson:"otp_enabled" json:"otp_enabled"`
+	OTPEnabledAt              time.Time          `bson:"otp_enabled_at,omitempty" json:"otp_enabled_at,omitempty"`
+	OTPSecret                 string             `bson:"otp_secret" json:"-"`
+	OTPPendingSecret          string             `bson:"otp_pending_secret" json:"-"`       // Awaiting the first code to confirm the enrollment.
+	OTPLastUsedStep           int64              `bson:"otp_last_used_step" json:"-"`       // Prevents a code from being used twice.
+	OTPRecoveryCodeHashes     []string           `bson:"otp_recovery_code_hashes" json:"-"` // Removed once used.
+	Version                   int64              `bson:"version" json:"version"`            // Incremented on every update so sessions notice the change.
 }
 
 type UserComment struct {
@@ -113,18 +122,23 @@ type UserStorer interface {
 	GetByVerificationCode(ctx context.Context, verificationCode string) (*User, error)
 	CheckIfExistsByEmail(ctx context.Context, email string) (bool, error)
 	UpdateByID(ctx context.Context, m *User) error
+	UpdateOTPLastUsedStepByID(ctx context.Context, id primitive.ObjectID, step int64) (bool, error)
+	DeleteOTPRecoveryCodeByID(ctx context.Context, id primitive.ObjectID, recoveryCodeHash string) (bool, error)
 	ListByFilter(ctx context.Context, f *UserListFilter) (*UserListResult, error)
+	ExportByFilter(ctx context.Context, f *UserListFilter, fn func(m *User) error) error
 	ListAsSelectOptionByFilter(ctx context.Context, f *UserListFilter) ([]*UserAsSelectOption, error)
 	ListAllRootStaff(ctx context.Context) (*UserListResult, error)
 	ListAllRetailerStaffForOrganizationID(ctx context.Context, organizationID primitive.ObjectID) (*UserListResult, error)
 	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	AddUpdatedHook(hook UpdatedHook)
 	// //TODO: Add more...
 }
 
 type UserStorerImpl struct {
-	Logger     *slog.Logger
-	DbClient   *mongo.Client
-	Collection *mongo.Collection
+	Logger       *slog.Logger
+	DbClient     *mongo.Client
+	Collection   *mongo.Collection
+	UpdatedHooks []UpdatedHook
 }
 
 func NewDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) UserStorer {
@@ -15,40 +15,7 @@ func (impl UserStorerImpl) ListByFilter(ctx context.Context, f *UserListFilter)
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
 
-	// Create the filter based on the cursor
-	filter := bson.M{}
-	if !f.Cursor.IsZero() {
-		filter["_id"] = bson.M{"$gt": f.Cursor} // Add the cursor condition to the filter
-	}
-
-	// Add filter conditions to the filter
-	if !f.OrganizationID.IsZero() {
-		filter["organization_id"] = f.OrganizationID
-	}
-	if f.Role > 0 {
-		filter["role"] = f.Role
-	}
-	if f.FirstName != "" {
-		filter["first_name"] = f.FirstName
-	}
-	if f.LastName != "" {
-		filter["last_name"] = f.LastName
-	}
-	if f.Email != "" {
-		filter["email"] = f.Email
-	}
-	if f.Phone != "" {
-		filter["phone"] = f.Phone
-	}
-	if f.ExcludeArchived {
-		filter["status"] = bson.M{"$ne": UserStatusArchived} // Do not list archived items! This code
-	}
-	if f.Status != 0 {
-		filter["status"] = f.Status
-	}
-	if !f.CreatedAtGTE.IsZero() {
-		filter["created_at"] = bson.M{"$gt": f.CreatedAtGTE} // Add the cursor condition to the filter
-	}
+	filter := newListFilter(f)
 
 	impl.Logger.Debug("listing filter:",
 		slog.Any("filter", filter))
@@ -110,6 +77,47 @@ func (impl UserStorerImpl) ListByFilter(ctx context.Context, f *UserListFilter)
 	}, nil
 }
 
+// newListFilter returns the query of the filter which is shared by our list
+// and export functions.
+func newListFilter(f *UserListFilter) bson.M {
+	// Create the filter based on the cursor
+	filter := bson.M{}
+	if !f.Cursor.IsZero() {
+		filter["_id"] = bson.M{"$gt": f.Cursor} // Add the cursor condition to the filter
+	}
+
+	// Add filter conditions to the filter
+	if !f.OrganizationID.IsZero() {
+		filter["organization_id"] = f.OrganizationID
+	}
+	if f.Role > 0 {
+		filter["role"] = f.Role
+	}
+	if f.FirstName != "" {
+		filter["first_name"] = f.FirstName
+	}
+	if f.LastName != "" {
+		filter["last_name"] = f.LastName
+	}
+	if f.Email != "" {
+		filter["email"] = f.Email
+	}
+	if f.Phone != "" {
+		filter["phone"] = f.Phone
+	}
+	if f.ExcludeArchived {
+		filter["status"] = bson.M{"$ne": UserStatusArchived} // Do not list archived items! This code
+	}
+	if f.Status != 0 {
+		filter["status"] = f.Status
+	}
+	if !f.CreatedAtGTE.IsZero() {
+		filter["created_at"] = bson.M{"$gt": f.CreatedAtGTE} // Add the cursor condition to the filter
+	}
+
+	return filter
+}
+
 func (impl UserStorerImpl) ListAsSelectOptionByFilter(ctx context.Context, f *UserListFilter) ([]*UserAsSelectOption, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 12*time.Second)
 	defer cancel()
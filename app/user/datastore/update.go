@@ -4,24 +4,89 @@ import (
 	"context"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/exp/slog"
 )
 
+// UpdatedHook is notified after a user was saved, ex: so their sessions pick
+// up their new role or status.
+type UpdatedHook func(ctx context.Context, m *User)
+
+// AddUpdatedHook registers a hook to be notified after every user updated.
+func (impl *UserStorerImpl) AddUpdatedHook(hook UpdatedHook) {
+	impl.UpdatedHooks = append(impl.UpdatedHooks, hook)
+}
+
+// UpdateByID saves the user and increments their version in the database,
+// so two concurrent updates never end up with the same version, and the
+// hooks receive the version the update produced.
 func (impl UserStorerImpl) UpdateByID(ctx context.Context, m *User) error {
 	filter := bson.D{{"_id", m.ID}}
 
+	// The version is only ever incremented by the database.
+	b, err := bson.Marshal(m)
+	if err != nil {
+		impl.Logger.Error("marshalling error", slog.Any("error", err))
+		return err
+	}
+	var set bson.M
+	if err := bson.Unmarshal(b, &set); err != nil {
+		impl.Logger.Error("unmarshalling error", slog.Any("error", err))
+		return err
+	}
+	delete(set, "version")
 	update := bson.M{ // DEVELOPERS NOTE: https://stackoverflow.com/a/60946010
-		"$set": m,
+		"$set": set,
+		"$inc": bson.M{"version": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"version": 1})
+
+	var result User
+	if err := impl.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		impl.Logger.Error("database update by user id error", slog.Any("error", err))
+		return err
+	}
+	m.Version = result.Version
+
+	for _, hook := range impl.UpdatedHooks {
+		hook(ctx, m)
 	}
+	return nil
+}
+
+// UpdateOTPLastUsedStepByID saves the step of the code the user logged in
+// with unless the step or a later one was used already, so two requests
+// racing with the same code cannot both succeed. Returns false if the code
+// was used already.
+func (impl UserStorerImpl) UpdateOTPLastUsedStepByID(ctx context.Context, id primitive.ObjectID, step int64) (bool, error) {
+	filter := bson.M{"_id": id, "otp_last_used_step": bson.M{"$lt": step}}
+	update := bson.M{"$set": bson.M{"otp_last_used_step": step}}
 
-	// execute the UpdateOne() function to update the first matching document
 	result, err := impl.Collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		impl.Logger.Error("database update by user id error", slog.Any("error", err))
+		impl.Logger.Error("database update otp last used step by id error", slog.Any("error", err))
+		return false, err
 	}
+	return result.MatchedCount == 1, nil
+}
 
-	// display the number of documents updated
-	impl.Logger.Debug("number of documents updated", slog.Int64("modified_count", result.ModifiedCount))
+// DeleteOTPRecoveryCodeByID removes the recovery code the user logged in
+// with. Returns false if the code was used already, ex: by another request.
+func (impl UserStorerImpl) DeleteOTPRecoveryCodeByID(ctx context.Context, id primitive.ObjectID, recoveryCodeHash string) (bool, error) {
+	filter := bson.M{"_id": id, "otp_recovery_code_hashes": recoveryCodeHash}
+	update := bson.M{"$pull": bson.M{"otp_recovery_code_hashes": recoveryCodeHash}}
 
-	return nil
+	result, err := impl.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		impl.Logger.Error("database delete otp recovery code by id error", slog.Any("error", err))
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
 }
@@ -0,0 +1,42 @@
+package datastore
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"golang.org/x/exp/slog"
+)
+
+func TestUpdateByIDIncrementsVersion(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("hooks receive the saved version", func(mt *mtest.T) {
+		impl := &UserStorerImpl{Logger: slog.New(slog.NewTextHandler(os.Stderr)), Collection: mt.Coll}
+		var hookVersion int64
+		impl.AddUpdatedHook(func(ctx context.Context, m *User) { hookVersion = m.Version })
+
+		// Another update was saved since the user was read.
+		m := &User{ID: primitive.NewObjectID(), Email: "someone@example.com", Version: 3}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "_id", Value: m.ID}, {Key: "version", Value: int64(5)}}}))
+
+		if err := impl.UpdateByID(context.Background(), m); err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		if m.Version != 5 || hookVersion != 5 {
+			t.Errorf("expected version 5 but got %v and %v", m.Version, hookVersion)
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("update").Document()
+		if inc := update.Lookup("$inc", "version").Int32(); inc != 1 {
+			t.Errorf("expected the version to be incremented by 1 but got %v", inc)
+		}
+		if _, err := update.Lookup("$set").Document().LookupErr("version"); err == nil {
+			t.Error("version was written by the update")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// newHTTPClient returns the client which posts our events. The urls are
+// chosen by our retailers so the client refuses to connect to our own
+// network and does not follow redirects, which could lead it there.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // A proxy would dial the url for us.
+	transport.DialContext = func(ctx context.Context, network string, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkDialAddress is called with the resolved address right before the
+// client connects, so a host which resolves to a public address when
+// validated and to a private one when delivered to is still refused.
+func checkDialAddress(network string, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("webhook: connecting to %v is not allowed", host)
+	}
+	return nil
+}
+
+// isPublicIP returns false for the addresses of our own network.
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// checkURL returns a `400 Bad Request` error unless we may post our events to
+// the url: it must use https, unless insecure urls are allowed for
+// development, and must not point to our own network.
+func (c *WebhookControllerImpl) checkURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return httperror.NewForBadRequestWithSingleField("url", "invalid value")
+	}
+	if u.Scheme != "https" && !(u.Scheme == "http" && c.Config.Webhook.AllowInsecureURLs) {
+		return httperror.NewForBadRequestWithSingleField("url", "must use https")
+	}
+	if u.Hostname() == "localhost" {
+		return httperror.NewForBadRequestWithSingleField("url", "must not point to a private address")
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublicIP(ip) {
+		return httperror.NewForBadRequestWithSingleField("url", "must not point to a private address")
+	}
+	return nil
+}
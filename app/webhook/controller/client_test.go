@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+func TestHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	client := newHTTPClient(5 * time.Second)
+	if res, err := client.Post(receiver.URL, "application/json", nil); err == nil {
+		res.Body.Close()
+		t.Error("expected the loopback address to be refused")
+	}
+	if called {
+		t.Error("expected the receiver not to be called")
+	}
+	if err := client.CheckRedirect(nil, nil); err != http.ErrUseLastResponse {
+		t.Errorf("expected redirects not to be followed but got %v", err)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	tests := []struct {
+		url           string
+		allowInsecure bool
+		valid         bool
+	}{
+		{"https://retailer.example.com/hooks", false, true},
+		{"http://retailer.example.com/hooks", false, false},
+		{"http://retailer.example.com/hooks", true, true},
+		{"ftp://retailer.example.com/hooks", true, false},
+		{"https://localhost/hooks", false, false},
+		{"https://127.0.0.1/hooks", false, false},
+		{"https://10.0.0.8/hooks", false, false},
+		{"https://192.168.1.1/hooks", false, false},
+		{"https://169.254.169.254/latest/meta-data", false, false},
+		{"https://0.0.0.0/hooks", false, false},
+		{"https://[::1]/hooks", false, false},
+		{"https://[fd00::1]/hooks", false, false},
+		{"https://8.8.8.8/hooks", false, true},
+		{"https:///hooks", false, false},
+	}
+	for _, test := range tests {
+		cfg := &config.Conf{}
+		cfg.Webhook.AllowInsecureURLs = test.allowInsecure
+		c := &WebhookControllerImpl{Config: cfg}
+		if err := c.checkURL(test.url); (err == nil) != test.valid {
+			t.Errorf("%v: expected valid %v but got %v", test.url, test.valid, err)
+		}
+	}
+}
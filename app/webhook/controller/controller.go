@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	comicsub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+// WebhookController Interface for the webhook subscriptions of our
+// organizations and the delivery of their events.
+type WebhookController interface {
+	Create(ctx context.Context, req *WebhookCreateRequestIDO) (*WebhookCreateResponseIDO, error)
+	GetByID(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) (*webhook_s.Subscription, error)
+	ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*webhook_s.Subscription, error)
+	UpdateByID(ctx context.Context, req *WebhookUpdateRequestIDO) (*webhook_s.Subscription, error)
+	DeleteByID(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) error
+	SendTestEvent(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) (*webhook_s.Delivery, error)
+	ListDeliveriesByFilter(ctx context.Context, organizationID primitive.ObjectID, f *webhook_s.DeliveryListFilter) (*webhook_s.DeliveryListResult, error)
+	ReplayDeliveryByID(ctx context.Context, organizationID primitive.ObjectID, subscriptionID primitive.ObjectID, id primitive.ObjectID) (*webhook_s.Delivery, error)
+	Deliver(ctx context.Context, j *job_s.Job) error
+}
+
+type WebhookControllerImpl struct {
+	Config             *config.Conf
+	Logger             *slog.Logger
+	HTTPClient         *http.Client
+	SubscriptionStorer webhook_s.SubscriptionStorer
+	DeliveryStorer     webhook_s.DeliveryStorer
+	JobStorer          job_s.JobStorer
+}
+
+func NewController(
+	appCfg *config.Conf,
+	loggerp *slog.Logger,
+	sub_controller comicsub_c.ComicSubmissionController,
+	subscription_storer webhook_s.SubscriptionStorer,
+	delivery_storer webhook_s.DeliveryStorer,
+	job_storer job_s.JobStorer,
+) WebhookController {
+	s := &WebhookControllerImpl{
+		Config:             appCfg,
+		Logger:             loggerp,
+		HTTPClient:         newHTTPClient(time.Duration(appCfg.Webhook.TimeoutSeconds) * time.Second),
+		SubscriptionStorer: subscription_storer,
+		DeliveryStorer:     delivery_storer,
+		JobStorer:          job_storer,
+	}
+	sub_controller.AddCreatedHook(s.onSubmissionCreated)
+	sub_controller.AddStatusTransitionHook(s.onSubmissionTransition)
+	sub_controller.AddCertificateIssuedHook(s.onCertificateIssued)
+	s.Logger.Debug("webhook controller initialized")
+	return s
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/url"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// secretPrefix starts every signing secret so it can be told apart from our
+// api keys, ex: `whsec_<secret>`.
+const secretPrefix = "whsec_"
+
+type WebhookCreateRequestIDO struct {
+	OrganizationID primitive.ObjectID `json:"organization_id"`
+	URL            string             `json:"url"`
+	Description    string             `json:"description"`
+	Events         []string           `json:"events"`
+}
+
+// WebhookCreateResponseIDO holds the signing secret which is never shown
+// again.
+type WebhookCreateResponseIDO struct {
+	Webhook *webhook_s.Subscription `json:"webhook"`
+	Secret  string                  `json:"secret"`
+}
+
+func (c *WebhookControllerImpl) Create(ctx context.Context, req *WebhookCreateRequestIDO) (*WebhookCreateResponseIDO, error) {
+	if err := checkManage(ctx, req.OrganizationID); err != nil {
+		return nil, err
+	}
+	if err := c.checkURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		c.Logger.Error("generate webhook secret error", slog.Any("error", err))
+		return nil, err
+	}
+
+	m := &webhook_s.Subscription{
+		ID:              primitive.NewObjectID(),
+		OrganizationID:  req.OrganizationID,
+		URL:             req.URL,
+		Description:     req.Description,
+		Events:          req.Events,
+		Secret:          secret,
+		Status:          webhook_s.SubscriptionStatusActive,
+		CreatedAt:       time.Now(),
+		CreatedByUserID: ctx.Value(constants.SessionUserID).(primitive.ObjectID),
+		ModifiedAt:      time.Now(),
+	}
+	if err := c.SubscriptionStorer.Create(ctx, m); err != nil {
+		c.Logger.Error("database create error", slog.Any("error", err))
+		return nil, err
+	}
+
+	c.Logger.Info("webhook created",
+		slog.Any("id", m.ID),
+		slog.Any("organization_id", m.OrganizationID),
+		slog.Any("events", m.Events))
+	return &WebhookCreateResponseIDO{Webhook: m, Secret: secret}, nil
+}
+
+// newSecret returns a new secret to sign the payloads of a subscription.
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// IsValidURL returns true if the url is an absolute http(s) url; the
+// controller checks whether we may post our events to it.
+func IsValidURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+}
+
+// checkManage returns a `403 Forbidden` error unless the logged in user may
+// manage the webhooks of the organization.
+func checkManage(ctx context.Context, organizationID primitive.ObjectID) error {
+	if err := permission.Check(ctx, permission.OrganizationManage); err != nil {
+		return err
+	}
+	return permission.CheckOrganization(ctx, organizationID)
+}
+
+// getSubscription returns the subscription of the organization or a
+// `404 Not Found` error.
+func (c *WebhookControllerImpl) getSubscription(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) (*webhook_s.Subscription, error) {
+	m, err := c.SubscriptionStorer.GetByID(ctx, id)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if m == nil || m.OrganizationID != organizationID {
+		return nil, httperror.NewForNotFoundWithSingleField("id", "webhook does not exist")
+	}
+	return m, nil
+}
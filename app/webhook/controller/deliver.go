@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+)
+
+const (
+	// The headers sent along with every payload.
+	HeaderEvent     = "X-CPS-Event"
+	HeaderDelivery  = "X-CPS-Delivery"
+	HeaderSignature = "X-CPS-Signature"
+
+	// maxResponseBodyLength is how much of the response of the receiver is
+	// kept in the delivery log.
+	maxResponseBodyLength = 1024
+)
+
+// Sign returns the value of the `X-CPS-Signature` header, ex:
+// `t=1700000000,v1=<hex>`, where `v1` is the HMAC-SHA256 of the timestamp, a
+// dot and the body keyed with the secret of the subscription. Receivers should
+// recompute it and reject old timestamps to prevent replays.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Deliver is the job handler which posts the payload of a delivery to its
+// subscription. Every attempt is added to the delivery log; returning an error
+// has the job retried with exponential backoff until it runs out of attempts.
+func (c *WebhookControllerImpl) Deliver(ctx context.Context, j *job_s.Job) error {
+	d, err := c.DeliveryStorer.GetByID(ctx, j.ReferenceID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if d == nil || d.Status == webhook_s.DeliveryStatusSucceeded {
+		c.Logger.Warn("webhook delivery does not exist or was delivered, skipping job", slog.Any("id", j.ReferenceID))
+		return nil
+	}
+
+	s, err := c.SubscriptionStorer.GetByID(ctx, d.SubscriptionID)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return err
+	}
+	if s == nil || s.Status != webhook_s.SubscriptionStatusActive {
+		// Retrying will not help so fail right away; once the subscription
+		// is active again the delivery can be replayed.
+		d.Status = webhook_s.DeliveryStatusFailed
+		d.Attempts = append(d.Attempts, &webhook_s.DeliveryAttempt{
+			AttemptedAt: time.Now(),
+			Error:       "webhook was deleted or disabled",
+		})
+		d.ModifiedAt = time.Now()
+		return c.DeliveryStorer.UpdateByID(ctx, d)
+	}
+
+	attempt := c.post(ctx, s, d)
+	d.Attempts = append(d.Attempts, attempt)
+	d.ModifiedAt = time.Now()
+	if attempt.Error == "" {
+		d.Status = webhook_s.DeliveryStatusSucceeded
+		d.DeliveredAt = attempt.AttemptedAt
+	} else if j.Attempts >= j.MaxAttempts {
+		d.Status = webhook_s.DeliveryStatusFailed
+	}
+	if err := c.DeliveryStorer.UpdateByID(ctx, d); err != nil {
+		c.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+
+	if attempt.Error != "" {
+		c.Logger.Warn("webhook delivery error",
+			slog.Any("id", d.ID),
+			slog.String("url", s.URL),
+			slog.Int("attempts", j.Attempts),
+			slog.String("error", attempt.Error))
+		return errors.New(attempt.Error)
+	}
+	return nil
+}
+
+// post sends the payload once; anything but a `2xx` response is an error.
+func (c *WebhookControllerImpl) post(ctx context.Context, s *webhook_s.Subscription, d *webhook_s.Delivery) *webhook_s.DeliveryAttempt {
+	attempt := &webhook_s.DeliveryAttempt{AttemptedAt: time.Now()}
+	body := []byte(d.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CPS-Webhook/1.0")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID.Hex())
+	req.Header.Set(HeaderSignature, Sign(s.Secret, attempt.AttemptedAt, body))
+
+	res, err := c.HTTPClient.Do(req)
+	attempt.DurationMs = time.Since(attempt.AttemptedAt).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer res.Body.Close()
+
+	resBody, _ := io.ReadAll(io.LimitReader(res.Body, maxResponseBodyLength))
+	attempt.StatusCode = res.StatusCode
+	attempt.ResponseBody = string(resBody)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("receiver responded with %d %s", res.StatusCode, http.StatusText(res.StatusCode))
+	}
+	return attempt
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+type fakeSubscriptionStorer struct {
+	webhook_s.SubscriptionStorer
+	subscriptions map[primitive.ObjectID]*webhook_s.Subscription
+}
+
+func (s *fakeSubscriptionStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*webhook_s.Subscription, error) {
+	return s.subscriptions[id], nil
+}
+
+func (s *fakeSubscriptionStorer) ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*webhook_s.Subscription, error) {
+	var ms []*webhook_s.Subscription
+	for _, m := range s.subscriptions {
+		if m.OrganizationID == organizationID {
+			ms = append(ms, m)
+		}
+	}
+	return ms, nil
+}
+
+type fakeDeliveryStorer struct {
+	webhook_s.DeliveryStorer
+	deliveries map[primitive.ObjectID]*webhook_s.Delivery
+}
+
+func (s *fakeDeliveryStorer) Create(ctx context.Context, m *webhook_s.Delivery) error {
+	s.deliveries[m.ID] = m
+	return nil
+}
+
+func (s *fakeDeliveryStorer) GetByID(ctx context.Context, id primitive.ObjectID) (*webhook_s.Delivery, error) {
+	return s.deliveries[id], nil
+}
+
+func (s *fakeDeliveryStorer) UpdateByID(ctx context.Context, m *webhook_s.Delivery) error {
+	s.deliveries[m.ID] = m
+	return nil
+}
+
+type fakeJobStorer struct {
+	job_s.JobStorer
+	jobs []*job_s.Job
+}
+
+func (s *fakeJobStorer) Create(ctx context.Context, m *job_s.Job) error {
+	s.jobs = append(s.jobs, m)
+	return nil
+}
+
+func newTestController(url string) (*WebhookControllerImpl, *webhook_s.Subscription, *fakeJobStorer) {
+	cfg := &config.Conf{}
+	cfg.Webhook.MaxAttempts = 3
+	s := &webhook_s.Subscription{
+		ID:             primitive.NewObjectID(),
+		OrganizationID: primitive.NewObjectID(),
+		URL:            url,
+		Events:         []string{webhook_s.EventSubmissionGraded},
+		Secret:         "whsec_test",
+		Status:         webhook_s.SubscriptionStatusActive,
+	}
+	jobs := &fakeJobStorer{}
+	c := &WebhookControllerImpl{
+		Config:             cfg,
+		Logger:             slog.New(slog.NewTextHandler(os.Stderr)),
+		HTTPClient:         &http.Client{Timeout: 5 * time.Second},
+		SubscriptionStorer: &fakeSubscriptionStorer{subscriptions: map[primitive.ObjectID]*webhook_s.Subscription{s.ID: s}},
+		DeliveryStorer:     &fakeDeliveryStorer{deliveries: map[primitive.ObjectID]*webhook_s.Delivery{}},
+		JobStorer:          jobs,
+	}
+	return c, s, jobs
+}
+
+func TestDeliver(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	c, s, jobs := newTestController(receiver.URL)
+	m := &s_d.ComicSubmission{ID: primitive.NewObjectID(), OrganizationID: s.OrganizationID}
+
+	// Only the events subscribed to are delivered.
+	c.onSubmissionTransition(context.Background(), m, &s_d.StatusTransition{To: s_d.StatusInGrading})
+	if len(jobs.jobs) != 0 {
+		t.Fatalf("expected no delivery but got %v", len(jobs.jobs))
+	}
+	c.onSubmissionTransition(context.Background(), m, &s_d.StatusTransition{To: s_d.StatusGraded})
+	if len(jobs.jobs) != 1 {
+		t.Fatalf("expected one delivery but got %v", len(jobs.jobs))
+	}
+
+	j := jobs.jobs[0]
+	j.Attempts = 1
+	if err := c.Deliver(context.Background(), j); err != nil {
+		t.Fatal(err)
+	}
+
+	d, _ := c.DeliveryStorer.GetByID(context.Background(), j.ReferenceID)
+	if d.Status != webhook_s.DeliveryStatusSucceeded || len(d.Attempts) != 1 || d.Attempts[0].StatusCode != http.StatusNoContent {
+		t.Errorf("expected delivery to succeed but got status %v with attempts %v", d.Status, d.Attempts)
+	}
+	if received.Header.Get(HeaderEvent) != webhook_s.EventSubmissionGraded || received.Header.Get(HeaderDelivery) != d.ID.Hex() {
+		t.Errorf("expected event headers but got %v", received.Header)
+	}
+	if string(receivedBody) != d.Payload {
+		t.Errorf("expected body %v but got %v", d.Payload, string(receivedBody))
+	}
+	if signature := Sign(s.Secret, d.Attempts[0].AttemptedAt, receivedBody); received.Header.Get(HeaderSignature) != signature {
+		t.Errorf("expected signature %v but got %v", signature, received.Header.Get(HeaderSignature))
+	}
+}
+
+func TestDeliverRetry(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	c, s, jobs := newTestController(receiver.URL)
+	d, err := c.enqueueDelivery(context.Background(), s, webhook_s.EventPing, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := jobs.jobs[0]
+
+	// The delivery stays pending while the job has attempts left.
+	for j.Attempts = 1; j.Attempts <= j.MaxAttempts; j.Attempts++ {
+		if err := c.Deliver(context.Background(), j); err == nil {
+			t.Fatalf("expected attempt %v to fail", j.Attempts)
+		}
+		expected := int8(webhook_s.DeliveryStatusPending)
+		if j.Attempts == j.MaxAttempts {
+			expected = webhook_s.DeliveryStatusFailed
+		}
+		if d.Status != expected {
+			t.Errorf("expected status %v after attempt %v but got %v", expected, j.Attempts, d.Status)
+		}
+	}
+	if len(d.Attempts) != j.MaxAttempts || d.Attempts[0].StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected %v attempts to be logged but got %v", j.MaxAttempts, d.Attempts)
+	}
+}
+
+func TestSign(t *testing.T) {
+	expected := "t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if actual := Sign("secret", time.Unix(1700000000, 0), []byte(`{}`)); actual != expected {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// SendTestEvent queues a `ping` event to the subscription, whatever events
+// it subscribed to, so the retailer can check their receiver.
+func (c *WebhookControllerImpl) SendTestEvent(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) (*webhook_s.Delivery, error) {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	s, err := c.getSubscription(ctx, organizationID, id)
+	if err != nil {
+		return nil, err
+	}
+	if s.Status != webhook_s.SubscriptionStatusActive {
+		return nil, httperror.NewForBadRequestWithSingleField("status", "webhook is disabled")
+	}
+
+	d, err := c.enqueueDelivery(ctx, s, webhook_s.EventPing, map[string]string{
+		"webhook_id": s.ID.Hex(),
+		"message":    "This is a test event.",
+	})
+	if err != nil {
+		c.Logger.Error("enqueue webhook delivery error", slog.Any("error", err))
+		return nil, err
+	}
+	return d, nil
+}
+
+func (c *WebhookControllerImpl) ListDeliveriesByFilter(ctx context.Context, organizationID primitive.ObjectID, f *webhook_s.DeliveryListFilter) (*webhook_s.DeliveryListResult, error) {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	if _, err := c.getSubscription(ctx, organizationID, f.SubscriptionID); err != nil {
+		return nil, err
+	}
+
+	res, err := c.DeliveryStorer.ListByFilter(ctx, f)
+	if err != nil {
+		c.Logger.Error("database list by filter error", slog.Any("error", err))
+		return nil, err
+	}
+	return res, nil
+}
+
+// ReplayDeliveryByID sends a failed delivery again with the same payload; the
+// attempts made so far are kept in the delivery log.
+func (c *WebhookControllerImpl) ReplayDeliveryByID(ctx context.Context, organizationID primitive.ObjectID, subscriptionID primitive.ObjectID, id primitive.ObjectID) (*webhook_s.Delivery, error) {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	if _, err := c.getSubscription(ctx, organizationID, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	d, err := c.DeliveryStorer.GetByID(ctx, id)
+	if err != nil {
+		c.Logger.Error("database get by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if d == nil || d.SubscriptionID != subscriptionID {
+		return nil, httperror.NewForNotFoundWithSingleField("delivery_id", "delivery does not exist")
+	}
+	if d.Status != webhook_s.DeliveryStatusFailed {
+		return nil, httperror.NewForSingleField(http.StatusConflict, "status", "only failed deliveries can be replayed")
+	}
+
+	j := &job_s.Job{
+		ID:          primitive.NewObjectID(),
+		Type:        job_s.TypeDeliverWebhook,
+		ReferenceID: d.ID,
+		MaxAttempts: c.Config.Webhook.MaxAttempts,
+	}
+	d.Status = webhook_s.DeliveryStatusPending
+	d.JobID = j.ID
+	d.ModifiedAt = time.Now()
+	if err := c.DeliveryStorer.UpdateByID(ctx, d); err != nil {
+		c.Logger.Error("database update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	if err := c.JobStorer.Create(ctx, j); err != nil {
+		c.Logger.Error("database create job error", slog.Any("error", err))
+		return nil, err
+	}
+
+	c.Logger.Info("webhook delivery replayed", slog.Any("id", d.ID), slog.Any("job_id", j.ID))
+	return d, nil
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	s_d "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+)
+
+// Event is the JSON body posted to the url of a subscription.
+type Event struct {
+	ID             primitive.ObjectID `json:"id"` // The id of the delivery, ex: to ignore duplicates.
+	Event          string             `json:"event"`
+	OrganizationID primitive.ObjectID `json:"organization_id"`
+	CreatedAt      time.Time          `json:"created_at"`
+	Data           interface{}        `json:"data"`
+}
+
+func (c *WebhookControllerImpl) onSubmissionCreated(ctx context.Context, m *s_d.ComicSubmission) {
+	c.publish(ctx, m.OrganizationID, webhook_s.EventSubmissionCreated, m)
+}
+
+func (c *WebhookControllerImpl) onSubmissionTransition(ctx context.Context, m *s_d.ComicSubmission, t *s_d.StatusTransition) {
+	switch t.To {
+	case s_d.StatusGraded:
+		c.publish(ctx, m.OrganizationID, webhook_s.EventSubmissionGraded, m)
+	case s_d.StatusArchived:
+		c.publish(ctx, m.OrganizationID, webhook_s.EventSubmissionArchived, m)
+	}
+}
+
+func (c *WebhookControllerImpl) onCertificateIssued(ctx context.Context, m *s_d.ComicSubmission) {
+	c.publish(ctx, m.OrganizationID, webhook_s.EventSubmissionPDFReady, m)
+}
+
+// publish queues a delivery of the event to every subscription of the
+// organization interested in it. Errors are only logged as the change which
+// raised the event was already saved.
+func (c *WebhookControllerImpl) publish(ctx context.Context, organizationID primitive.ObjectID, event string, data interface{}) {
+	ss, err := c.SubscriptionStorer.ListByOrganizationID(ctx, organizationID)
+	if err != nil {
+		c.Logger.Error("database list by organization id error", slog.Any("error", err))
+		return
+	}
+	for _, s := range ss {
+		if !s.IsSubscribedTo(event) {
+			continue
+		}
+		if _, err := c.enqueueDelivery(ctx, s, event, data); err != nil {
+			c.Logger.Error("enqueue webhook delivery error",
+				slog.Any("subscription_id", s.ID),
+				slog.String("event", event),
+				slog.Any("error", err))
+		}
+	}
+}
+
+// enqueueDelivery saves the event to the delivery log of the subscription and
+// schedules it to be sent by our job workers.
+func (c *WebhookControllerImpl) enqueueDelivery(ctx context.Context, s *webhook_s.Subscription, event string, data interface{}) (*webhook_s.Delivery, error) {
+	d := &webhook_s.Delivery{
+		ID:             primitive.NewObjectID(),
+		SubscriptionID: s.ID,
+		OrganizationID: s.OrganizationID,
+		Event:          event,
+		Status:         webhook_s.DeliveryStatusPending,
+		Attempts:       []*webhook_s.DeliveryAttempt{},
+		CreatedAt:      time.Now(),
+		ModifiedAt:     time.Now(),
+	}
+	payload, err := json.Marshal(&Event{
+		ID:             d.ID,
+		Event:          event,
+		OrganizationID: s.OrganizationID,
+		CreatedAt:      d.CreatedAt,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = string(payload)
+
+	// The delivery is saved before its job so a worker never picks up a job
+	// whose delivery does not exist yet.
+	j := &job_s.Job{
+		ID:          primitive.NewObjectID(),
+		Type:        job_s.TypeDeliverWebhook,
+		ReferenceID: d.ID,
+		MaxAttempts: c.Config.Webhook.MaxAttempts,
+	}
+	d.JobID = j.ID
+	if err := c.DeliveryStorer.Create(ctx, d); err != nil {
+		return nil, err
+	}
+	if err := c.JobStorer.Create(ctx, j); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
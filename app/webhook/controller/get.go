@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+)
+
+func (c *WebhookControllerImpl) GetByID(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) (*webhook_s.Subscription, error) {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	return c.getSubscription(ctx, organizationID, id)
+}
+
+func (c *WebhookControllerImpl) ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*webhook_s.Subscription, error) {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	ms, err := c.SubscriptionStorer.ListByOrganizationID(ctx, organizationID)
+	if err != nil {
+		c.Logger.Error("database list by organization id error", slog.Any("error", err))
+		return nil, err
+	}
+	return ms, nil
+}
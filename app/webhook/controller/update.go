@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slog"
+
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+)
+
+type WebhookUpdateRequestIDO struct {
+	ID             primitive.ObjectID `json:"id"`
+	OrganizationID primitive.ObjectID `json:"organization_id"`
+	URL            string             `json:"url"`
+	Description    string             `json:"description"`
+	Events         []string           `json:"events"`
+	Status         int8               `json:"status"`
+}
+
+func (c *WebhookControllerImpl) UpdateByID(ctx context.Context, req *WebhookUpdateRequestIDO) (*webhook_s.Subscription, error) {
+	if err := checkManage(ctx, req.OrganizationID); err != nil {
+		return nil, err
+	}
+	if err := c.checkURL(req.URL); err != nil {
+		return nil, err
+	}
+	m, err := c.getSubscription(ctx, req.OrganizationID, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.URL = req.URL
+	m.Description = req.Description
+	m.Events = req.Events
+	m.Status = req.Status
+	m.ModifiedAt = time.Now()
+	if err := c.SubscriptionStorer.UpdateByID(ctx, m); err != nil {
+		c.Logger.Error("database update by id error", slog.Any("error", err))
+		return nil, err
+	}
+	return m, nil
+}
+
+// DeleteByID removes the subscription; deliveries which are still pending
+// are marked as failed when their job runs.
+func (c *WebhookControllerImpl) DeleteByID(ctx context.Context, organizationID primitive.ObjectID, id primitive.ObjectID) error {
+	if err := checkManage(ctx, organizationID); err != nil {
+		return err
+	}
+	m, err := c.getSubscription(ctx, organizationID, id)
+	if err != nil {
+		return err
+	}
+	if err := c.SubscriptionStorer.DeleteByID(ctx, m.ID); err != nil {
+		c.Logger.Error("database delete by id error", slog.Any("error", err))
+		return err
+	}
+
+	c.Logger.Info("webhook deleted", slog.Any("id", m.ID), slog.Any("organization_id", m.OrganizationID))
+	return nil
+}
@@ -0,0 +1,187 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/exp/slog"
+
+	c "github.com/LuchaComics/cps-backend/config"
+)
+
+const (
+	SubscriptionStatusActive   = 1
+	SubscriptionStatusDisabled = 2
+
+	DeliveryStatusPending   = 1 // Waiting on its first attempt or on a retry.
+	DeliveryStatusSucceeded = 2
+	DeliveryStatusFailed    = 3 // Ran out of attempts; can be replayed.
+
+	EventSubmissionCreated  = "submission.created"
+	EventSubmissionGraded   = "submission.graded"
+	EventSubmissionPDFReady = "submission.pdf_ready"
+	EventSubmissionArchived = "submission.archived"
+	EventPing               = "ping" // Sent by the "send test event" endpoint only.
+)
+
+// Events are the events an organization may subscribe to.
+var Events = []string{
+	EventSubmissionCreated,
+	EventSubmissionGraded,
+	EventSubmissionPDFReady,
+	EventSubmissionArchived,
+}
+
+// IsEvent returns true if the event can be subscribed to.
+func IsEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription is an endpoint of a retailer which is sent the events it
+// subscribed to. The secret signs every payload; unlike a password it cannot
+// be hashed as we need it to sign.
+type Subscription struct {
+	ID              primitive.ObjectID `bson:"_id" json:"id"`
+	OrganizationID  primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	URL             string             `bson:"url" json:"url"`
+	Description     string             `bson:"description" json:"description"`
+	Events          []string           `bson:"events" json:"events"`
+	Secret          string             `bson:"secret" json:"-"`
+	Status          int8               `bson:"status" json:"status"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	CreatedByUserID primitive.ObjectID `bson:"created_by_user_id" json:"created_by_user_id"`
+	ModifiedAt      time.Time          `bson:"modified_at" json:"modified_at"`
+}
+
+// IsSubscribedTo returns true if the subscription should be sent the event.
+func (m *Subscription) IsSubscribedTo(event string) bool {
+	if m.Status != SubscriptionStatusActive {
+		return false
+	}
+	for _, e := range m.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one event sent to one subscription along with every attempt
+// made at sending it.
+type Delivery struct {
+	ID             primitive.ObjectID `bson:"_id" json:"id"`
+	SubscriptionID primitive.ObjectID `bson:"subscription_id" json:"subscription_id"`
+	OrganizationID primitive.ObjectID `bson:"organization_id" json:"organization_id"`
+	Event          string             `bson:"event" json:"event"`
+	Payload        string             `bson:"payload" json:"payload"` // The JSON body exactly as signed.
+	Status         int8               `bson:"status" json:"status"`
+	Attempts       []*DeliveryAttempt `bson:"attempts" json:"attempts"`
+	JobID          primitive.ObjectID `bson:"job_id,omitempty" json:"job_id,omitempty"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	ModifiedAt     time.Time          `bson:"modified_at" json:"modified_at"`
+	DeliveredAt    time.Time          `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
+
+// DeliveryAttempt is the outcome of one request to the endpoint.
+type DeliveryAttempt struct {
+	AttemptedAt  time.Time `bson:"attempted_at" json:"attempted_at"`
+	StatusCode   int       `bson:"status_code" json:"status_code"`
+	ResponseBody string    `bson:"response_body" json:"response_body"` // Truncated.
+	Error        string    `bson:"error" json:"error,omitempty"`
+	DurationMs   int64     `bson:"duration_ms" json:"duration_ms"`
+}
+
+type DeliveryListFilter struct {
+	// Pagination related.
+	Cursor   primitive.ObjectID
+	PageSize int64
+
+	// Filter related.
+	SubscriptionID primitive.ObjectID
+	Status         int8
+}
+
+type DeliveryListResult struct {
+	Results     []*Delivery        `json:"results"`
+	NextCursor  primitive.ObjectID `json:"next_cursor"`
+	HasNextPage bool               `json:"has_next_page"`
+}
+
+// SubscriptionStorer Interface for the webhook subscriptions.
+type SubscriptionStorer interface {
+	Create(ctx context.Context, m *Subscription) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Subscription, error)
+	ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*Subscription, error)
+	UpdateByID(ctx context.Context, m *Subscription) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+// DeliveryStorer Interface for the delivery log of our webhooks.
+type DeliveryStorer interface {
+	Create(ctx context.Context, m *Delivery) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*Delivery, error)
+	ListByFilter(ctx context.Context, f *DeliveryListFilter) (*DeliveryListResult, error)
+	UpdateByID(ctx context.Context, m *Delivery) error
+}
+
+type SubscriptionStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+type DeliveryStorerImpl struct {
+	Logger     *slog.Logger
+	DbClient   *mongo.Client
+	Collection *mongo.Collection
+}
+
+func NewSubscriptionDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) SubscriptionStorer {
+	uc := client.Database(appCfg.DB.Name).Collection("webhook_subscriptions")
+
+	// The following few lines of code will create the index for our app for this
+	// colleciton.
+	if _, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "organization_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	}); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	return &SubscriptionStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+}
+
+func NewDeliveryDatastore(appCfg *c.Conf, loggerp *slog.Logger, client *mongo.Client) DeliveryStorer {
+	uc := client.Database(appCfg.DB.Name).Collection("webhook_deliveries")
+
+	// The following few lines of code will create the index for our app for this
+	// colleciton.
+	if _, err := uc.Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "status", Value: 1}, {Key: "_id", Value: -1}}},
+	}); err != nil {
+		// It is important that we crash the app on startup to meet the
+		// requirements of `google/wire` framework.
+		log.Fatal(err)
+	}
+
+	return &DeliveryStorerImpl{
+		Logger:     loggerp,
+		DbClient:   client,
+		Collection: uc,
+	}
+}
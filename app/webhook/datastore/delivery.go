@@ -0,0 +1,107 @@
+package datastore
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+func (impl DeliveryStorerImpl) Create(ctx context.Context, m *Delivery) error {
+	if m.ID == primitive.NilObjectID {
+		m.ID = primitive.NewObjectID()
+		impl.Logger.Warn("database insert webhook delivery not included id value, created id now.", slog.Any("id", m.ID))
+	}
+
+	if _, err := impl.Collection.InsertOne(ctx, m); err != nil {
+		impl.Logger.Error("database insert error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl DeliveryStorerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*Delivery, error) {
+	var result Delivery
+	err := impl.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get webhook delivery error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListByFilter returns the deliveries newest first; the payload is left out
+// as it is only needed when looking at a single delivery.
+func (impl DeliveryStorerImpl) ListByFilter(ctx context.Context, f *DeliveryListFilter) (*DeliveryListResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if !f.Cursor.IsZero() {
+		filter["_id"] = bson.M{"$lt": f.Cursor}
+	}
+	if !f.SubscriptionID.IsZero() {
+		filter["subscription_id"] = f.SubscriptionID
+	}
+	if f.Status != 0 {
+		filter["status"] = f.Status
+	}
+
+	options := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetLimit(f.PageSize + 1).
+		SetProjection(bson.M{"payload": 0})
+
+	cursor, err := impl.Collection.Find(ctx, filter, options)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Delivery{}
+	hasNextPage := false
+	for cursor.Next(ctx) {
+		if int64(len(results)) >= f.PageSize {
+			hasNextPage = true
+			break
+		}
+		document := &Delivery{}
+		if err := cursor.Decode(document); err != nil {
+			return nil, err
+		}
+		results = append(results, document)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	nextCursor := primitive.NilObjectID
+	if hasNextPage {
+		nextCursor = results[len(results)-1].ID
+	}
+
+	return &DeliveryListResult{
+		Results:     results,
+		NextCursor:  nextCursor,
+		HasNextPage: hasNextPage,
+	}, nil
+}
+
+func (impl DeliveryStorerImpl) UpdateByID(ctx context.Context, m *Delivery) error {
+	filter := bson.M{"_id": m.ID}
+	update := bson.M{"$set": m}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package datastore
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/exp/slog"
+)
+
+func (impl SubscriptionStorerImpl) Create(ctx context.Context, m *Subscription) error {
+	if m.ID == primitive.NilObjectID {
+		m.ID = primitive.NewObjectID()
+		impl.Logger.Warn("database insert webhook subscription not included id value, created id now.", slog.Any("id", m.ID))
+	}
+
+	if _, err := impl.Collection.InsertOne(ctx, m); err != nil {
+		impl.Logger.Error("database insert error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl SubscriptionStorerImpl) GetByID(ctx context.Context, id primitive.ObjectID) (*Subscription, error) {
+	var result Subscription
+	err := impl.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			// This error means your query did not match any documents.
+			return nil, nil
+		}
+		impl.Logger.Error("database get webhook subscription error", slog.Any("error", err))
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListByOrganizationID returns every subscription of the organization, newest
+// first, including the disabled ones.
+func (impl SubscriptionStorerImpl) ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID) ([]*Subscription, error) {
+	filter := bson.M{"organization_id": organizationID}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+
+	cursor, err := impl.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		impl.Logger.Error("database list webhook subscriptions error", slog.Any("error", err))
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []*Subscription{}
+	if err := cursor.All(ctx, &results); err != nil {
+		impl.Logger.Error("database decode webhook subscriptions error", slog.Any("error", err))
+		return nil, err
+	}
+	return results, nil
+}
+
+func (impl SubscriptionStorerImpl) UpdateByID(ctx context.Context, m *Subscription) error {
+	filter := bson.M{"_id": m.ID}
+	update := bson.M{"$set": m}
+
+	if _, err := impl.Collection.UpdateOne(ctx, filter, update); err != nil {
+		impl.Logger.Error("database update by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func (impl SubscriptionStorerImpl) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := impl.Collection.DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		impl.Logger.Error("database delete by id error", slog.Any("error", err))
+		return err
+	}
+	return nil
+}
@@ -13,6 +13,12 @@ type Conf struct {
 	AWS        awsConfig
 	PDFBuilder pdfBuilderConfig
 	Emailer    mailgunConfig
+	JobQueue   jobQueueConfig
+	Signing    signingConfig
+	Grading    gradingConfig
+	Webhook    webhookConfig
+	Login      loginConfig
+	JWT        jwtConfig
 }
 
 type serverConf struct {
@@ -24,6 +30,7 @@ type serverConf struct {
 	InitialAdminPassword         string
 	InitialAdminOrganizationName string
 	DomainName                   string
+	TrustedProxies               string // Comma separated IP addresses or CIDR ranges of the proxies in front of us whose `X-Forwarded-For` header we trust.
 }
 
 type dbConfig struct {
@@ -45,12 +52,56 @@ type awsConfig struct {
 
 type pdfBuilderConfig struct {
 	CBFFTemplatePath  string
+	CBFFLayoutPath    string
 	PCTemplatePath    string
+	PCLayoutPath      string
 	CCIMGTemplatePath string
+	CCIMGLayoutPath   string
 	CCSCTemplatePath  string
+	CCSCLayoutPath    string
 	CCTemplatePath    string
-	CCUGTemplatePath    string
+	CCLayoutPath      string
+	CCUGTemplatePath  string
+	CCUGLayoutPath    string
 	DataDirectoryPath string
+	DebugOverlay      bool
+}
+
+type jobQueueConfig struct {
+	WorkerCount int
+	MaxAttempts int
+}
+
+type signingConfig struct {
+	KeyID            string // Identifies the key which signs new registry records.
+	PrivateKey       string // Base64 encoded Ed25519 seed.
+	VerificationKeys string // Comma separated `key id:base64 public key` pairs of retired keys.
+}
+
+type gradingConfig struct {
+	RequiredSignOffs    int     // Inspectors who must sign off before the certificate is issued; zero issues it right away.
+	LetterTolerance     int     // Steps between letter grades, ex: "vf" and "nm" are one step apart.
+	NumberTolerance     float64 // Points on the 0.5 to 10 scale.
+	PercentageTolerance float64 // Points on the CPS percentage scale.
+}
+
+type webhookConfig struct {
+	MaxAttempts    int // Attempts before a delivery is marked failed; retries back off exponentially up to an hour.
+	TimeoutSeconds int // How long the endpoint of a retailer has to respond.
+
+	// AllowInsecureURLs accepts `http` urls; for development only.
+	AllowInsecureURLs bool
+}
+
+type loginConfig struct {
+	MaxFailedAttempts      int64 // Failed attempts on an account before it is locked.
+	MaxFailedAttemptsPerIP int64 // Failed attempts from an IP address before it is blocked, across accounts.
+	LockoutMinutes         int   // How long an account or IP address stays locked; failures are counted over the same window.
+}
+
+type jwtConfig struct {
+	KeysDirectoryPath string // Holds the `<kid>.pem` RSA or Ed25519 private keys; the kid starts with the date the key activates, ex: "2024-01-01-rs256".
+	AcceptHMAC        bool   // Accept the tokens signed by the HMAC secret until they have all expired.
 }
 
 type mailgunConfig struct {
@@ -70,6 +121,7 @@ func New() *Conf {
 	c.AppServer.InitialAdminPassword = getEnv("CPS_BACKEND_INITIAL_ADMIN_PASSWORD", true)
 	c.AppServer.InitialAdminOrganizationName = getEnv("CPS_BACKEND_INITIAL_ADMIN_ORG_NAME", true)
 	c.AppServer.DomainName = getEnv("CPS_BACKEND_DOMAIN_NAME", true)
+	c.AppServer.TrustedProxies = getEnv("CPS_BACKEND_TRUSTED_PROXIES", false)
 
 	c.DB.URI = getEnv("CPS_BACKEND_DB_URI", true)
 	c.DB.Name = getEnv("CPS_BACKEND_DB_NAME", true)
@@ -88,13 +140,43 @@ func New() *Conf {
 	c.PDFBuilder.CCSCTemplatePath = getEnv("CPS_BACKEND_PDF_BUILDER_CCSC_TEMPLATE_FILE_PATH", true)
 	c.PDFBuilder.CCTemplatePath = getEnv("CPS_BACKEND_PDF_BUILDER_CC_TEMPLATE_FILE_PATH", true)
 	c.PDFBuilder.CCUGTemplatePath = getEnv("CPS_BACKEND_PDF_BUILDER_CCUG_TEMPLATE_FILE_PATH", true)
+	c.PDFBuilder.CBFFLayoutPath = getEnv("CPS_BACKEND_PDF_BUILDER_CBFF_LAYOUT_FILE_PATH", true)
+	c.PDFBuilder.PCLayoutPath = getEnv("CPS_BACKEND_PDF_BUILDER_PC_LAYOUT_FILE_PATH", true)
+	c.PDFBuilder.CCIMGLayoutPath = getEnv("CPS_BACKEND_PDF_BUILDER_CCIMG_LAYOUT_FILE_PATH", true)
+	c.PDFBuilder.CCSCLayoutPath = getEnv("CPS_BACKEND_PDF_BUILDER_CCSC_LAYOUT_FILE_PATH", true)
+	c.PDFBuilder.CCLayoutPath = getEnv("CPS_BACKEND_PDF_BUILDER_CC_LAYOUT_FILE_PATH", true)
+	c.PDFBuilder.CCUGLayoutPath = getEnv("CPS_BACKEND_PDF_BUILDER_CCUG_LAYOUT_FILE_PATH", true)
 	c.PDFBuilder.DataDirectoryPath = getEnv("CPS_BACKEND_PDF_BUILDER_DATA_DIRECTORY_PATH", true)
+	c.PDFBuilder.DebugOverlay = getEnvBool("CPS_BACKEND_PDF_BUILDER_DEBUG_OVERLAY", false, false)
 
 	c.Emailer.APIKey = getEnv("CPS_BACKEND_MAILGUN_API_KEY", true)
 	c.Emailer.Domain = getEnv("CPS_BACKEND_MAILGUN_DOMAIN", true)
 	c.Emailer.APIBase = getEnv("CPS_BACKEND_MAILGUN_API_BASE", true)
 	c.Emailer.SenderEmail = getEnv("CPS_BACKEND_MAILGUN_SENDER_EMAIL", true)
 
+	c.JobQueue.WorkerCount = getEnvInt("CPS_BACKEND_JOB_QUEUE_WORKER_COUNT", false, 2)
+	c.JobQueue.MaxAttempts = getEnvInt("CPS_BACKEND_JOB_QUEUE_MAX_ATTEMPTS", false, 5)
+
+	c.Signing.KeyID = getEnv("CPS_BACKEND_REGISTRY_SIGNING_KEY_ID", true)
+	c.Signing.PrivateKey = getEnv("CPS_BACKEND_REGISTRY_SIGNING_PRIVATE_KEY", true)
+	c.Signing.VerificationKeys = getEnv("CPS_BACKEND_REGISTRY_VERIFICATION_KEYS", false)
+
+	c.Grading.RequiredSignOffs = getEnvInt("CPS_BACKEND_GRADING_REQUIRED_SIGN_OFFS", false, 0)
+	c.Grading.LetterTolerance = getEnvInt("CPS_BACKEND_GRADING_LETTER_TOLERANCE", false, 1)
+	c.Grading.NumberTolerance = getEnvFloat("CPS_BACKEND_GRADING_NUMBER_TOLERANCE", false, 1)
+	c.Grading.PercentageTolerance = getEnvFloat("CPS_BACKEND_GRADING_PERCENTAGE_TOLERANCE", false, 10)
+
+	c.Webhook.MaxAttempts = getEnvInt("CPS_BACKEND_WEBHOOK_MAX_ATTEMPTS", false, 10)
+	c.Webhook.TimeoutSeconds = getEnvInt("CPS_BACKEND_WEBHOOK_TIMEOUT_SECONDS", false, 10)
+	c.Webhook.AllowInsecureURLs = getEnvBool("CPS_BACKEND_WEBHOOK_ALLOW_INSECURE_URLS", false, false)
+
+	c.Login.MaxFailedAttempts = int64(getEnvInt("CPS_BACKEND_LOGIN_MAX_FAILED_ATTEMPTS", false, 5))
+	c.Login.MaxFailedAttemptsPerIP = int64(getEnvInt("CPS_BACKEND_LOGIN_MAX_FAILED_ATTEMPTS_PER_IP", false, 50))
+	c.Login.LockoutMinutes = getEnvInt("CPS_BACKEND_LOGIN_LOCKOUT_MINUTES", false, 15)
+
+	c.JWT.KeysDirectoryPath = getEnv("CPS_BACKEND_JWT_KEYS_DIRECTORY_PATH", false)
+	c.JWT.AcceptHMAC = getEnvBool("CPS_BACKEND_JWT_ACCEPT_HMAC", false, true)
+
 	return &c
 }
 
@@ -117,3 +199,27 @@ func getEnvBool(key string, required bool, defaultValue bool) bool {
 	}
 	return value
 }
+
+func getEnvInt(key string, required bool, defaultValue int) int {
+	valueStr := getEnv(key, required)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Fatalf("Invalid integer value for environment variable %s", key)
+	}
+	return value
+}
+
+func getEnvFloat(key string, required bool, defaultValue float64) float64 {
+	valueStr := getEnv(key, required)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		log.Fatalf("Invalid float value for environment variable %s", key)
+	}
+	return value
+}
@@ -18,4 +18,7 @@ const (
 	SessionUserLastName
 	SessionUserOrganizationID
 	SessionUserOrganizationName
+	SessionAPIKeyID
+	SessionAPIKeyScopes
+	SessionUserAgent
 )
@@ -43,3 +43,24 @@ var SubmissionPrimaryLabelDetails = map[int8]string{
 	7: "Facsimile",
 	8: "Reprint",
 }
+
+var SubmissionServiceTypes = map[int8]string{
+	1: "Pre-Screening Service",
+	2: "Pedigree Service",
+	3: "CPS Capsule",
+	4: "CPS Capsule Indie Mint Gem",
+	5: "CPS Capsule Signature Collection",
+	6: "CPS Capsule U-Grade",
+}
+
+var SubmissionStatuses = map[int8]string{
+	4:   "Received",
+	5:   "In Grading",
+	6:   "Graded",
+	7:   "Encapsulated",
+	8:   "Shipped",
+	9:   "Completed",
+	10:  "On Hold",
+	11:  "Rejected",
+	100: "Archived",
+}
@@ -0,0 +1,93 @@
+package apikey
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_c "github.com/LuchaComics/cps-backend/app/apikey/controller"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func UnmarshalCreateRequest(ctx context.Context, r *http.Request) (*apikey_c.APIKeyCreateRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData apikey_c.APIKeyCreateRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		log.Println(err)
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	if err := ValidateCreateRequest(&requestData); err != nil {
+		return nil, err
+	}
+	return &requestData, nil
+}
+
+func ValidateCreateRequest(dirtyData *apikey_c.APIKeyCreateRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.Name == "" {
+		e["name"] = "missing value"
+	}
+	if len(dirtyData.Scopes) == 0 {
+		e["scopes"] = "missing value"
+	}
+	for _, scope := range dirtyData.Scopes {
+		if !permission.IsAPIKeyScope(permission.Permission(scope)) {
+			e["scopes"] = "invalid value"
+		}
+	}
+	if !dirtyData.ExpiresAt.IsZero() && dirtyData.ExpiresAt.Before(time.Now()) {
+		e["expires_at"] = "invalid value"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request, organizationID string) {
+	ctx := r.Context()
+
+	orgID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	data, err := UnmarshalCreateRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	data.OrganizationID = orgID
+
+	res, err := h.Controller.Create(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	MarshalCreateResponse(res, w)
+}
+
+func MarshalCreateResponse(res *apikey_c.APIKeyCreateResponseIDO, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
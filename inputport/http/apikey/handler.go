@@ -0,0 +1,17 @@
+package apikey
+
+import (
+	apikey_c "github.com/LuchaComics/cps-backend/app/apikey/controller"
+)
+
+// Handler Creates http request handler
+type Handler struct {
+	Controller apikey_c.APIKeyController
+}
+
+// NewHandler Constructor
+func NewHandler(c apikey_c.APIKeyController) *Handler {
+	return &Handler{
+		Controller: c,
+	}
+}
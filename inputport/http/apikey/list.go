@@ -0,0 +1,40 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+type APIKeyListResponse struct {
+	Results []*apikey_s.APIKey `json:"results"`
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request, organizationID string) {
+	ctx := r.Context()
+
+	orgID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	m, err := h.Controller.ListByOrganizationID(ctx, orgID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalListResponse(m, w)
+}
+
+func MarshalListResponse(res []*apikey_s.APIKey, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&APIKeyListResponse{Results: res}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,30 @@
+package apikey
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) RevokeByID(w http.ResponseWriter, r *http.Request, organizationID string, id string) {
+	ctx := r.Context()
+
+	orgID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("api_key_id", "invalid value"))
+		return
+	}
+
+	if err := h.Controller.RevokeByID(ctx, orgID, objectID); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,17 @@
+package audit
+
+import (
+	audit_c "github.com/LuchaComics/cps-backend/app/audit/controller"
+)
+
+// Handler Creates http request handler
+type Handler struct {
+	Controller audit_c.AuditController
+}
+
+// NewHandler Constructor
+func NewHandler(c audit_c.AuditController) *Handler {
+	return &Handler{
+		Controller: c,
+	}
+}
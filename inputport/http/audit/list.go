@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	audit_s "github.com/LuchaComics/cps-backend/app/audit/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	f := &audit_s.EventListFilter{
+		PageSize: 25,
+	}
+
+	// Here is where you extract url parameters.
+	query := r.URL.Query()
+	cursor := query.Get("cursor")
+	if cursor != "" {
+		cursor, err := primitive.ObjectIDFromHex(cursor)
+		if err != nil {
+			httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("cursor", "invalid value"))
+			return
+		}
+		f.Cursor = cursor
+	}
+
+	pageSize := query.Get("page_size")
+	if pageSize != "" {
+		pageSize, _ := strconv.ParseInt(pageSize, 10, 64)
+		if pageSize == 0 || pageSize > 250 {
+			pageSize = 250
+		}
+		f.PageSize = pageSize
+	}
+
+	userID := query.Get("user_id")
+	if userID != "" {
+		userID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("user_id", "invalid value"))
+			return
+		}
+		f.UserID = userID
+	}
+	f.Type = query.Get("type")
+
+	m, err := h.Controller.ListByFilter(ctx, f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&m); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
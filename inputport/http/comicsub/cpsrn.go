@@ -40,6 +40,10 @@ type RegistryReponse struct {
 	OverallLetterGrade                 string    `bson:"overall_letter_grade" json:"overall_letter_grade"`
 	OverallNumberGrade                 float64   `bson:"overall_number_grade" json:"overall_number_grade"`
 	CpsPercentageGrade                 float64   `bson:"cps_percentage_grade" json:"cps_percentage_grade"`
+	Signature                          string    `bson:"signature" json:"signature"`
+	SignatureKeyID                     string    `bson:"signature_key_id" json:"signature_key_id"`
+	SignedAt                           time.Time `bson:"signed_at" json:"signed_at,omitempty"`
+	Verified                           bool      `bson:"verified" json:"verified"` // False if the grade changed since the certificate was issued.
 }
 
 func MarshalRegistryResponse(s *sub_s.ComicSubmission, w http.ResponseWriter) {
@@ -61,6 +65,10 @@ func MarshalRegistryResponse(s *sub_s.ComicSubmission, w http.ResponseWriter) {
 		OverallLetterGrade:                 s.OverallLetterGrade,
 		OverallNumberGrade:                 s.OverallNumberGrade,
 		CpsPercentageGrade:                 s.CpsPercentageGrade,
+		Signature:                          s.RegistrySignature,
+		SignatureKeyID:                     s.RegistrySignatureKeyID,
+		SignedAt:                           s.RegistrySignedAt,
+		Verified:                           s.RegistrySignatureVerified,
 	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -132,9 +132,6 @@ func ValidateCreateRequest(dirtyData *sub_c.ComicSubmissionCreateRequestIDO) err
 	if dirtyData.GradingNotes != "" && len(dirtyData.GradingNotes) > 638 {
 		e["grading_notes"] = "over 638 characters"
 	}
-	if dirtyData.Status == 0 {
-		e["status"] = "missing choice"
-	}
 	if dirtyData.ServiceType == 0 {
 		e["service_type"] = "missing choice"
 	}
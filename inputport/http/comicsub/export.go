@@ -0,0 +1,95 @@
+package comicsub
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	sub_s "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/export"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// exportHeader are the columns of the CSV export; they are named like the
+// columns of our import so an export can be edited and imported again.
+var exportHeader = []string{
+	"id",
+	"cpsrn",
+	"organization_id",
+	"organization_name",
+	"order_id",
+	"status",
+	"service_type",
+	"series_title",
+	"issue_vol",
+	"issue_no",
+	"issue_cover_year",
+	"issue_cover_month",
+	"publisher_name",
+	"publisher_name_other",
+	"grading_scale",
+	"overall_letter_grade",
+	"overall_number_grade",
+	"cps_percentage_grade",
+	"customer_name",
+	"customer_email",
+	"created_at",
+}
+
+func exportRow(m *sub_s.ComicSubmission) []string {
+	var orderID, customerName, customerEmail string
+	if !m.OrderID.IsZero() {
+		orderID = m.OrderID.Hex()
+	}
+	if m.User != nil {
+		customerName = m.User.Name
+		customerEmail = m.User.Email
+	}
+	return []string{
+		m.ID.Hex(),
+		m.CPSRN,
+		m.OrganizationID.Hex(),
+		m.OrganizationName,
+		orderID,
+		constants.SubmissionStatuses[m.Status],
+		strconv.Itoa(int(m.ServiceType)),
+		m.SeriesTitle,
+		m.IssueVol,
+		m.IssueNo,
+		strconv.FormatInt(m.IssueCoverYear, 10),
+		strconv.Itoa(int(m.IssueCoverMonth)),
+		constants.SubmissionPublisherNames[m.PublisherName],
+		m.PublisherNameOther,
+		strconv.Itoa(int(m.GradingScale)),
+		m.OverallLetterGrade,
+		strconv.FormatFloat(m.OverallNumberGrade, 'f', -1, 64),
+		strconv.FormatFloat(m.CpsPercentageGrade, 'f', -1, 64),
+		customerName,
+		customerEmail,
+		m.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// Export streams every submission of the list filters as a CSV or NDJSON
+// file, ex: `/v1/comic-submissions/export?format=ndjson&status=6`.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	f, err := unmarshalListFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	ew := export.NewWriter(w, format, "comic-submissions", exportHeader)
+	err = h.Controller.ExportByFilter(ctx, f, func(m *sub_s.ComicSubmission) error {
+		return ew.Write(m, func() []string { return exportRow(m) })
+	})
+	ew.Close(err)
+}
@@ -0,0 +1,65 @@
+package comicsub
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sub_s "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) ListGradeRevisions(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	submissionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	revisions, err := h.Controller.ListGradeRevisionsBySubmissionID(ctx, submissionID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalGradeRevisionListResponse(revisions, w)
+}
+
+type GradeRevisionListResponse struct {
+	Results []*sub_s.GradeRevision `json:"results"`
+}
+
+func MarshalGradeRevisionListResponse(res []*sub_s.GradeRevision, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&GradeRevisionListResponse{Results: res}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// DownloadGradeRevisionCertificate redirects to the certificate which was
+// issued before the grade revision.
+func (h *Handler) DownloadGradeRevisionCertificate(w http.ResponseWriter, r *http.Request, id string, revisionID string) {
+	ctx := r.Context()
+
+	submissionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+	revisionObjectID, err := primitive.ObjectIDFromHex(revisionID)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("revision_id", "invalid value"))
+		return
+	}
+
+	url, err := h.Controller.GetGradeRevisionCertificateURL(ctx, submissionID, revisionObjectID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
+}
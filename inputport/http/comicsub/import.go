@@ -0,0 +1,253 @@
+package comicsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+	"github.com/LuchaComics/cps-backend/utils/spreadsheet"
+)
+
+// MaxImportSize is the largest request body an import may send.
+const MaxImportSize = 10 << 20
+
+// importColumns are the columns of `ComicSubmissionCreateRequestIDO` which
+// can be imported, keyed by their json name, ex: `series_title`. The header
+// of the spreadsheet is matched case insensitively so `Series Title` works too.
+var importColumns = func() map[string]int {
+	columns := make(map[string]int)
+	t := reflect.TypeOf(sub_c.ComicSubmissionCreateRequestIDO{})
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		switch name {
+		case "", "-", "submission_date", "signatures":
+			continue // Set by our system or not representable in a cell.
+		}
+		columns[name] = i
+	}
+	return columns
+}()
+
+func importColumnName(header string) string {
+	name := strings.ToLower(strings.TrimSpace(header))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+}
+
+// resolvePublisherName returns the choice of the publisher with the name,
+// ex: `Marvel`, or the `other` choice with the name when we do not know it.
+func resolvePublisherName(value string) (int8, string) {
+	if i, err := strconv.ParseInt(value, 10, 8); err == nil {
+		if _, ok := constants.SubmissionPublisherNames[int8(i)]; ok {
+			return int8(i), ""
+		}
+	}
+	for choice, name := range constants.SubmissionPublisherNames {
+		if strings.EqualFold(name, value) {
+			return choice, ""
+		}
+	}
+	if strings.EqualFold(value, "other") {
+		return constants.SubmissionPublisherNameOther, ""
+	}
+	return constants.SubmissionPublisherNameOther, value
+}
+
+// setImportColumn converts the value of the cell into the type of the field.
+func setImportColumn(req *sub_c.ComicSubmissionCreateRequestIDO, column string, value string) error {
+	switch column {
+	case "organization_id":
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return err
+		}
+		req.OrganizationID = id
+		return nil
+	case "publisher_name":
+		choice, other := resolvePublisherName(value)
+		req.PublisherName = choice
+		if other != "" && req.PublisherNameOther == "" {
+			req.PublisherNameOther = other
+		}
+		return nil
+	}
+
+	f := reflect.ValueOf(req).Elem().Field(importColumns[column])
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Int8, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(i)
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(v)
+	case reflect.Bool:
+		switch strings.ToLower(value) {
+		case "yes", "y":
+			f.SetBool(true)
+		case "no", "n":
+			f.SetBool(false)
+		default:
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return err
+			}
+			f.SetBool(v)
+		}
+	default:
+		return fmt.Errorf("unsupported column: %v", column)
+	}
+	return nil
+}
+
+func UnmarshalImportRequest(ctx context.Context, r *http.Request) (*sub_c.ComicSubmissionImportRequestIDO, error) {
+	defer r.Body.Close()
+
+	// Parse the multipart form data
+	if err := r.ParseMultipartForm(32 << 20); err != nil { // Limit the maximum memory used for parsing to 32MB
+		log.Println("UnmarshalImportRequest:ParseMultipartForm:err:", err)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, httperror.NewForSingleField(http.StatusRequestEntityTooLarge, "file", fmt.Sprintf("must be at most %v MB", MaxImportSize>>20))
+		}
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+	isDryRun, _ := strconv.ParseBool(r.FormValue("dry_run"))
+
+	// Rows without an organization are assigned to the one picked in the form;
+	// users limited to their organization always import into it.
+	var defaultOrganizationID primitive.ObjectID
+	if v := r.FormValue("organization_id"); v != "" {
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			return nil, httperror.NewForBadRequestWithSingleField("organization_id", "invalid value")
+		}
+		defaultOrganizationID = id
+	}
+	if organizationID, ok := permission.OrganizationScope(ctx); ok {
+		defaultOrganizationID = organizationID
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, httperror.NewForBadRequestWithSingleField("file", "missing value")
+	}
+	defer file.Close()
+	b, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := spreadsheet.Read(b, sub_c.MaxImportRows+1) // The header and our rows.
+	if err == spreadsheet.ErrTooManyRows {
+		return nil, httperror.NewForBadRequestWithSingleField("file", fmt.Sprintf("spreadsheet has over %v rows", sub_c.MaxImportRows))
+	}
+	if err != nil {
+		log.Println("UnmarshalImportRequest:spreadsheet.Read:err:", err)
+		return nil, httperror.NewForBadRequestWithSingleField("file", "must be a csv or xlsx file")
+	}
+	if len(rows) < 2 {
+		return nil, httperror.NewForBadRequestWithSingleField("file", "spreadsheet has no rows")
+	}
+
+	// The first row is the header which names the column of every cell.
+	header := make([]string, len(rows[0]))
+	for i, cell := range rows[0] {
+		name := importColumnName(cell)
+		if name == "" {
+			continue
+		}
+		if _, ok := importColumns[name]; !ok {
+			return nil, httperror.NewForBadRequestWithSingleField("file", fmt.Sprintf("unknown column: %v", cell))
+		}
+		header[i] = name
+	}
+
+	requestData := &sub_c.ComicSubmissionImportRequestIDO{IsDryRun: isDryRun}
+	for i, cells := range rows[1:] {
+		if isBlankRow(cells) {
+			continue
+		}
+		row := &sub_c.ComicSubmissionImportRowIDO{
+			Row:     i + 2,
+			Request: &sub_c.ComicSubmissionCreateRequestIDO{OrganizationID: defaultOrganizationID},
+			Errors:  make(map[string]string),
+		}
+		for j, value := range cells {
+			value = strings.TrimSpace(value)
+			if j >= len(header) || header[j] == "" || value == "" {
+				continue
+			}
+			if err := setImportColumn(row.Request, header[j], value); err != nil {
+				row.Errors[header[j]] = "invalid value"
+			}
+		}
+		if err := ValidateCreateRequest(row.Request); err != nil {
+			for field, message := range *err.(httperror.HTTPError).Errors {
+				if _, ok := row.Errors[field]; !ok {
+					row.Errors[field] = message
+				}
+			}
+		}
+		if len(row.Errors) == 0 {
+			row.Errors = nil
+		}
+		requestData.Rows = append(requestData.Rows, row)
+	}
+	return requestData, nil
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Import creates the submissions of the uploaded CSV or XLSX spreadsheet, or
+// only reports the errors of every row when `dry_run` is set.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	r.Body = http.MaxBytesReader(w, r.Body, MaxImportSize)
+
+	data, err := UnmarshalImportRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.Import(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalImportResponse(res, w)
+}
+
+func MarshalImportResponse(res *sub_c.ComicSubmissionImportResponseIDO, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
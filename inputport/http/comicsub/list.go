@@ -11,9 +11,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
+// unmarshalListFilter returns the filter of the url parameters which is
+// shared by our list and export endpoints.
+func unmarshalListFilter(r *http.Request) (*sub_s.ComicSubmissionListFilter, error) {
 	// Initialize the list filter with base results and then override them with the URL parameters.
 	f := &sub_s.ComicSubmissionListFilter{
 		Cursor:          primitive.NilObjectID,
@@ -29,18 +29,25 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	if organizationID != "" {
 		organizationID, err := primitive.ObjectIDFromHex(organizationID)
 		if err != nil {
-			httperror.ResponseError(w, err)
-			return
+			return nil, err
 		}
 		f.OrganizationID = organizationID
 	}
 
+	orderID := query.Get("order_id")
+	if orderID != "" {
+		orderID, err := primitive.ObjectIDFromHex(orderID)
+		if err != nil {
+			return nil, err
+		}
+		f.OrderID = orderID
+	}
+
 	userID := query.Get("user_id")
 	if userID != "" {
 		userID, err := primitive.ObjectIDFromHex(userID)
 		if err != nil {
-			httperror.ResponseError(w, err)
-			return
+			return nil, err
 		}
 		f.UserID = userID
 	}
@@ -49,8 +56,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	if cursor != "" {
 		cursor, err := primitive.ObjectIDFromHex(cursor)
 		if err != nil {
-			httperror.ResponseError(w, err)
-			return
+			return nil, err
 		}
 		f.Cursor = cursor
 	}
@@ -78,12 +84,23 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	if createdAtGTEStr != "" {
 		createdAtGTE, err := timekit.ParseJavaScriptTimeString(createdAtGTEStr)
 		if err != nil {
-			httperror.ResponseError(w, err)
-			return
+			return nil, err
 		}
 		f.CreatedAtGTE = createdAtGTE
 	}
 
+	return f, nil
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	f, err := unmarshalListFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
 	// Fet
 	m, err := h.Controller.ListByFilter(ctx, f)
 	if err != nil {
@@ -0,0 +1,28 @@
+package comicsub
+
+import (
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) RegeneratePDF(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	m, err := h.Controller.RegeneratePDF(ctx, objectID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	MarshalDetailResponse(m, w)
+}
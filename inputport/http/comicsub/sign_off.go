@@ -0,0 +1,108 @@
+package comicsub
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	sub_s "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func UnmarshalSignOffRequest(ctx context.Context, r *http.Request) (*sub_c.ComicSubmissionSignOffRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData sub_c.ComicSubmissionSignOffRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		log.Println(err)
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	if err := ValidateSignOffRequest(&requestData); err != nil {
+		return nil, err
+	}
+	return &requestData, nil
+}
+
+func ValidateSignOffRequest(dirtyData *sub_c.ComicSubmissionSignOffRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.Signature == "" {
+		e["signature"] = "missing value"
+	}
+	findings := map[string]string{
+		"creases_finding":       dirtyData.CreasesFinding,
+		"tears_finding":         dirtyData.TearsFinding,
+		"missing_parts_finding": dirtyData.MissingPartsFinding,
+		"stains_finding":        dirtyData.StainsFinding,
+		"distortion_finding":    dirtyData.DistortionFinding,
+		"paper_quality_finding": dirtyData.PaperQualityFinding,
+		"spine_finding":         dirtyData.SpineFinding,
+		"cover_finding":         dirtyData.CoverFinding,
+	}
+	for field, value := range findings {
+		if value == "" {
+			e[field] = "missing choice"
+		}
+	}
+	if dirtyData.ShowsSignsOfTamperingOrRestoration != sub_s.YesItShowsSignsOfTamperingOrRestoration && dirtyData.ShowsSignsOfTamperingOrRestoration != sub_s.NoItDoesNotShowsSignsOfTamperingOrRestoration {
+		e["shows_signs_of_tampering_or_restoration"] = "missing choice"
+	}
+	switch dirtyData.GradingScale {
+	case sub_s.GradingScaleLetter:
+		if dirtyData.OverallLetterGrade == "" {
+			e["overall_letter_grade"] = "missing value"
+		}
+	case sub_s.GradingScaleNumber:
+		if dirtyData.OverallNumberGrade <= 0 || dirtyData.OverallNumberGrade > 10 {
+			e["overall_number_grade"] = "must be between 0.5 and 10"
+		}
+	case sub_s.GradingScaleCPSPercentage:
+		if dirtyData.CpsPercentageGrade <= 0 || dirtyData.CpsPercentageGrade > 100 {
+			e["cps_percentage_grade"] = "must be between 1 and 100"
+		}
+	default:
+		e["grading_scale"] = "missing choice"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+// SignOff submits the grade of the logged in inspector for the submission.
+func (h *Handler) SignOff(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	submissionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	reqData, err := UnmarshalSignOffRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	reqData.SubmissionID = submissionID
+
+	m, err := h.Controller.SignOff(ctx, reqData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalDetailResponse(m, w)
+}
@@ -0,0 +1,76 @@
+package comicsub
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	sub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func UnmarshalTransitionRequest(ctx context.Context, r *http.Request) (*sub_c.ComicSubmissionTransitionRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData sub_c.ComicSubmissionTransitionRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		log.Println(err)
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	if err := ValidateTransitionRequest(&requestData); err != nil {
+		return nil, err
+	}
+	return &requestData, nil
+}
+
+func ValidateTransitionRequest(dirtyData *sub_c.ComicSubmissionTransitionRequestIDO) error {
+	e := make(map[string]string)
+
+	if dirtyData.Status == 0 {
+		e["status"] = "missing choice"
+	}
+	if len(dirtyData.Reason) > 500 {
+		e["reason"] = "over 500 characters"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+// Transition moves the submission into the status of the request.
+func (h *Handler) Transition(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	submissionID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	reqData, err := UnmarshalTransitionRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	reqData.SubmissionID = submissionID
+
+	m, err := h.Controller.Transition(ctx, reqData)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalDetailResponse(m, w)
+}
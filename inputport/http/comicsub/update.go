@@ -134,9 +134,6 @@ func ValidateUpdateRequest(dirtyData *sub_c.ComicSubmissionUpdateRequestIDO) err
 	if dirtyData.GradingNotes != "" && len(dirtyData.GradingNotes) > 638 {
 		e["grading_notes"] = "over 638 characters"
 	}
-	if dirtyData.Status == 0 {
-		e["status"] = "missing choice"
-	}
 	if dirtyData.ServiceType == 0 {
 		e["service_type"] = "missing choice"
 	}
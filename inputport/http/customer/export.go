@@ -0,0 +1,73 @@
+package customer
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/utils/export"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+var exportHeader = []string{
+	"id",
+	"organization_id",
+	"organization_name",
+	"first_name",
+	"last_name",
+	"email",
+	"phone",
+	"country",
+	"region",
+	"city",
+	"postal_code",
+	"address_line_1",
+	"address_line_2",
+	"agree_promotions_email",
+	"status",
+	"created_at",
+}
+
+func exportRow(m *user_s.User) []string {
+	return []string{
+		m.ID.Hex(),
+		m.OrganizationID.Hex(),
+		m.OrganizationName,
+		m.FirstName,
+		m.LastName,
+		m.Email,
+		m.Phone,
+		m.Country,
+		m.Region,
+		m.City,
+		m.PostalCode,
+		m.AddressLine1,
+		m.AddressLine2,
+		strconv.FormatBool(m.AgreePromotionsEmail),
+		strconv.Itoa(int(m.Status)),
+		m.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// Export streams every customer of the list filters as a CSV or NDJSON file.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	f, err := unmarshalListFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	ew := export.NewWriter(w, format, "customers", exportHeader)
+	err = h.Controller.ExportByFilter(ctx, f, func(m *user_s.User) error {
+		return ew.Write(m, func() []string { return exportRow(m) })
+	})
+	ew.Close(err)
+}
@@ -0,0 +1,25 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// JWKS returns the public keys which verify our tokens so other services can
+// verify them; they pick the key by the `kid` header of the token.
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res, err := h.Controller.JWKS(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	// Keys are published before they sign so a cached set stays valid.
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
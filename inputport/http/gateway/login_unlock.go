@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+type LoginUnlockRequestIDO struct {
+	Code string `json:"code"`
+}
+
+func (h *Handler) LoginUnlock(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData LoginUnlockRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+	if requestData.Code == "" {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("code", "missing value"))
+		return
+	}
+
+	if err := h.Controller.LoginUnlock(ctx, requestData.Code); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) UnlockLoginByUserID(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	if err := h.Controller.UnlockLoginByUserID(ctx, objectID); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	gateway_c "github.com/LuchaComics/cps-backend/app/gateway/controller"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+type LoginOTPEnrollRequestIDO struct {
+	ChallengeToken string `json:"challenge_token"`
+}
+
+func UnmarshalLoginOTPRequest(ctx context.Context, r *http.Request) (*gateway_c.LoginOTPRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData gateway_c.LoginOTPRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	e := make(map[string]string)
+	if requestData.ChallengeToken == "" {
+		e["challenge_token"] = "missing value"
+	}
+	if requestData.Code == "" {
+		e["code"] = "missing value"
+	}
+	if len(e) != 0 {
+		return nil, httperror.NewForBadRequest(&e)
+	}
+	return &requestData, nil
+}
+
+func (h *Handler) LoginOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalLoginOTPRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.LoginOTP(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalLoginResponse(res, w)
+}
+
+func (h *Handler) LoginOTPEnroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var requestData LoginOTPEnrollRequestIDO
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
+		httperror.ResponseError(w, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong"))
+		return
+	}
+	if requestData.ChallengeToken == "" {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("challenge_token", "missing value"))
+		return
+	}
+
+	res, err := h.Controller.LoginOTPEnroll(ctx, requestData.ChallengeToken)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalOTPResponse(res, w)
+}
+
+func UnmarshalOTPCodeRequest(ctx context.Context, r *http.Request) (*gateway_c.OTPCodeRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData gateway_c.OTPCodeRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+	if requestData.Code == "" {
+		return nil, httperror.NewForBadRequestWithSingleField("code", "missing value")
+	}
+	return &requestData, nil
+}
+
+func (h *Handler) ProfileOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res, err := h.Controller.ProfileOTP(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalOTPResponse(res, w)
+}
+
+func (h *Handler) ProfileOTPEnroll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res, err := h.Controller.ProfileOTPEnroll(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalOTPResponse(res, w)
+}
+
+func (h *Handler) ProfileOTPConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalOTPCodeRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.ProfileOTPConfirm(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalOTPResponse(res, w)
+}
+
+func (h *Handler) ProfileOTPRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalOTPCodeRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.ProfileOTPRegenerateRecoveryCodes(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalOTPResponse(res, w)
+}
+
+func (h *Handler) ProfileOTPDisable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalOTPCodeRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	if err := h.Controller.ProfileOTPDisable(ctx, data); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func MarshalOTPResponse(responseData interface{}, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&responseData); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,38 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) ProfileSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	res, err := h.Controller.ProfileSessions(ctx)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalSessionsResponse(res, w)
+}
+
+func MarshalSessionsResponse(res []*session_s.Session, w http.ResponseWriter) {
+	if res == nil {
+		res = []*session_s.Session{}
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) ProfileRevokeSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := h.Controller.ProfileRevokeSessions(ctx); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
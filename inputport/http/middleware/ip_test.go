@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trustedProxies, err := parseTrustedProxies("10.0.0.0/8, 192.168.1.4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		remoteAddr   string
+		forwardedFor string
+		realIP       string
+		expected     string
+	}{
+		// The port changes with every connection so it must not be kept.
+		{"203.0.113.7:51234", "", "", "203.0.113.7"},
+		{"203.0.113.7:61000", "", "", "203.0.113.7"},
+		{"[2001:db8::1]:51234", "", "", "2001:db8::1"},
+		// Anyone but our proxies could set the headers to anything.
+		{"203.0.113.7:51234", "198.51.100.9", "198.51.100.10", "203.0.113.7"},
+		// Our proxies forward the client.
+		{"10.0.0.2:51234", "198.51.100.9", "", "198.51.100.9"},
+		{"192.168.1.4:51234", "", "198.51.100.10", "198.51.100.10"},
+		{"10.0.0.2:51234", "1.2.3.4, 198.51.100.9, 10.0.0.3", "", "198.51.100.9"},
+		{"10.0.0.2:51234", "", "", "10.0.0.2"},
+	}
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/v1/login", nil)
+		r.RemoteAddr = test.remoteAddr
+		if test.forwardedFor != "" {
+			r.Header.Set("X-Forwarded-For", test.forwardedFor)
+		}
+		if test.realIP != "" {
+			r.Header.Set("X-Real-Ip", test.realIP)
+		}
+		if actual := clientIP(r, trustedProxies); actual != test.expected {
+			t.Errorf("%v %q %q: expected %v but got %v", test.remoteAddr, test.forwardedFor, test.realIP, test.expected, actual)
+		}
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	if proxies, err := parseTrustedProxies(""); err != nil || len(proxies) != 0 {
+		t.Errorf("expected no proxies but got %v and %v", proxies, err)
+	}
+	if _, err := parseTrustedProxies("10.0.0.0/8,nope"); err == nil {
+		t.Error("expected an invalid proxy to be rejected")
+	}
+}
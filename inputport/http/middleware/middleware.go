@@ -3,17 +3,25 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"log"
 	"net"
 	"net/http"
 	"strings"
 	"sync"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/ratelimit"
 	"golang.org/x/exp/slog"
 
+	apikey_c "github.com/LuchaComics/cps-backend/app/apikey/controller"
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
 	gateway_c "github.com/LuchaComics/cps-backend/app/gateway/controller"
+	"github.com/LuchaComics/cps-backend/app/permission"
+	session_c "github.com/LuchaComics/cps-backend/app/session/controller"
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/config"
 	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/inputport/http/router"
 	"github.com/LuchaComics/cps-backend/provider/jwt"
 	"github.com/LuchaComics/cps-backend/provider/time"
 	"github.com/LuchaComics/cps-backend/provider/uuid"
@@ -30,6 +38,9 @@ type middleware struct {
 	JWT               jwt.Provider
 	UUID              uuid.Provider
 	GatewayController gateway_c.GatewayController
+	APIKeyController  apikey_c.APIKeyController
+	SessionController session_c.SessionController
+	TrustedProxies    []*net.IPNet
 }
 
 func NewMiddleware(
@@ -39,13 +50,23 @@ func NewMiddleware(
 	timep time.Provider,
 	jwtp jwt.Provider,
 	gatewayController gateway_c.GatewayController,
+	apiKeyController apikey_c.APIKeyController,
+	sessionController session_c.SessionController,
 ) Middleware {
+	trustedProxies, err := parseTrustedProxies(configp.AppServer.TrustedProxies)
+	if err != nil {
+		log.Fatalf("middleware: %v", err)
+	}
 	return &middleware{
+		Config:            configp,
 		Logger:            loggerp,
 		UUID:              uuidp,
 		Time:              timep,
 		JWT:               jwtp,
 		GatewayController: gatewayController,
+		APIKeyController:  apiKeyController,
+		SessionController: sessionController,
+		TrustedProxies:    trustedProxies,
 	}
 }
 
@@ -53,14 +74,13 @@ func NewMiddleware(
 func (mid *middleware) Attach(fn http.HandlerFunc) http.HandlerFunc {
 	// Attach our middleware handlers here. Please note that all our middleware
 	// will start from the bottom and proceed upwards.
-	// Ex: `URLProcessorMiddleware` will be executed first and
-	//     `PostJWTProcessorMiddleware` will be executed last.
+	// Ex: `RateLimitMiddleware` will be executed first and
+	//     `ProtectedURLsMiddleware` will be executed last.
 	fn = mid.ProtectedURLsMiddleware(fn)
 	fn = mid.IPAddressMiddleware(fn)
 	fn = mid.PostJWTProcessorMiddleware(fn) // Note: Must be above `JWTProcessorMiddleware`.
 	fn = mid.JWTProcessorMiddleware(fn)     // Note: Must be above `PreJWTProcessorMiddleware`.
-	fn = mid.PreJWTProcessorMiddleware(fn)  // Note: Must be above `URLProcessorMiddleware`.
-	fn = mid.URLProcessorMiddleware(fn)
+	fn = mid.PreJWTProcessorMiddleware(fn)
 	fn = mid.RateLimitMiddleware(fn)
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -105,74 +125,23 @@ func (mid *middleware) RateLimitMiddleware(fn http.HandlerFunc) http.HandlerFunc
 	}
 }
 
-//URLProcessorMiddleware Middleware will split the full URL path into slash-sperated parts and save to
-// the context to flow downstream in the app for this particular request.
-func (mid *middleware) URLProcessorMiddleware(fn http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Split path into slash-separated parts, for example, path "/foo/bar"
-		// gives p==["foo", "bar"] and path "/" gives p==[""]. Our API starts with
-		// "/api", as a result we will start the array slice at "1".
-		p := strings.Split(r.URL.Path, "/")[1:]
-
-		// log.Println(p) // For debugging purposes only.
-
-		// Open our program's context based on the request and save the
-		// slash-seperated array from our URL path.
-		ctx := r.Context()
-		ctx = context.WithValue(ctx, "url_split", p)
-
-		// Flow to the next middleware.
-		fn(w, r.WithContext(ctx))
-	}
+// isPublicRoute returns true if the route of the request, which was resolved
+// by our router, can be visited without being logged in.
+func isPublicRoute(ctx context.Context) bool {
+	route := router.RouteFromContext(ctx)
+	return route != nil && route.IsPublic
 }
 
-// PreJWTProcessorMiddleware checks to see if we are visiting an unprotected URL and if so then
+// PreJWTProcessorMiddleware checks to see if we are visiting a public route and if so then
 // let the system know we need to skip authorization handling.
 func (mid *middleware) PreJWTProcessorMiddleware(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Open our program's context based on the request and save the
-		// slash-seperated array from our URL path.
 		ctx := r.Context()
 
-		// The following code will lookup the URL path in a whitelist and
-		// if the visited path matches then we will skip URL protection.
-		// We do this because a majority of API endpoints are protected
-		// by authorization.
-
-		urlSplit := ctx.Value("url_split").([]string)
-		skipPath := map[string]bool{
-			"version":         true,
-			"greeting":        true,
-			"login":           true,
-			"refresh-token":   true,
-			"register":        true,
-			"verify":          true,
-			"forgot-password": true,
-			"password-reset":  true,
-			"cpsrn":           true,
-		}
-
-		// DEVELOPERS NOTE:
-		// If the URL cannot be split into the size then do not skip authorization.
-		if len(urlSplit) < 3 {
-			// mid.Logger.Warn("Skipping authorization | len less then 3")
-			ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, false)
-			fn(w, r.WithContext(ctx)) // Flow to the next middleware.
-			return
-		}
-
-		// Skip authorization if the URL matches the whitelist else we need to
-		// run authorization check.
-		if skipPath[urlSplit[2]] {
-			// mid.Logger.Warn("Skipping authorization | skipPath found")
-			ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, true)
-		} else {
-			// For debugging purposes only.
-			// log.Println("PreJWTProcessorMiddleware | Protected URL detected")
-			// log.Println("PreJWTProcessorMiddleware | urlSplit:", urlSplit)
-			// log.Println("PreJWTProcessorMiddleware | urlSplit[2]:", urlSplit[2])
-			ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, false)
-		}
+		// Skip authorization if the route is public else we need to run
+		// authorization check. We do this because a majority of API endpoints
+		// are protected by authorization.
+		ctx = context.WithValue(ctx, constants.SessionSkipAuthorization, isPublicRoute(ctx))
 
 		// Flow to the next middleware.
 		fn(w, r.WithContext(ctx))
@@ -201,6 +170,21 @@ func (mid *middleware) JWTProcessorMiddleware(fn http.HandlerFunc) http.HandlerF
 		// step!
 		if reqToken != "" && strings.Contains(reqToken, "undefined") == false {
 
+			// Point-of-sale systems of our retailers authenticate with the
+			// API key of their organization instead of a login.
+			if key, ok := strings.CutPrefix(reqToken, "ApiKey "); ok {
+				apiKey, err := mid.APIKeyController.Authenticate(ctx, key)
+				if err != nil {
+					mid.Logger.Warn("api key authentication error", slog.Any("err", err))
+					http.Error(w, err.Error(), http.StatusUnauthorized)
+					return
+				}
+
+				// Flow to the next middleware with our API key saved.
+				fn(w, r.WithContext(withAPIKeySession(ctx, apiKey)))
+				return
+			}
+
 			// Special thanks to "poise" via https://stackoverflow.com/a/44700761
 			splitToken := strings.Split(reqToken, "JWT ")
 			if len(splitToken) < 2 {
@@ -225,38 +209,12 @@ func (mid *middleware) JWTProcessorMiddleware(fn http.HandlerFunc) http.HandlerF
 				return
 			}
 
-			// The following code will lookup the URL path in a whitelist and
-			// if the visited path matches then we will skip any token errors.
-			// We do this because a majority of API endpoints are protected
-			// by authorization.
-
-			urlSplit := ctx.Value("url_split").([]string)
-			skipPath := map[string]bool{
-				"version":         true,
-				"greeting":        true,
-				"login":           true,
-				"refresh-token":   true,
-				"register":        true,
-				"verify":          true,
-				"forgot-password": true,
-				"password-reset":  true,
-				"cpsrn":           true,
-			}
-
-			// DEVELOPERS NOTE:
-			// If the URL cannot be split into the size we want then skip running
-			// this middleware.
-			if len(urlSplit) >= 3 {
-				if skipPath[urlSplit[2]] {
-					mid.Logger.Warn("Skipping expired or error token")
-				} else {
-					// For debugging purposes only.
-					// log.Println("JWTProcessorMiddleware | ProcessJWT | err", err, "for reqToken:", reqToken)
-					// log.Println("JWTProcessorMiddleware | ProcessJWT | urlSplit:", urlSplit)
-					// log.Println("JWTProcessorMiddleware | ProcessJWT | urlSplit[2]:", urlSplit[2])
-					http.Error(w, err.Error(), http.StatusUnauthorized)
-					return
-				}
+			// Skip any token errors if the route is public.
+			if isPublicRoute(ctx) {
+				mid.Logger.Warn("Skipping expired or error token")
+			} else {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
 			}
 		}
 
@@ -266,6 +224,28 @@ func (mid *middleware) JWTProcessorMiddleware(fn http.HandlerFunc) http.HandlerF
 	}
 }
 
+// withAPIKeySession saves the API key to the context under the same session
+// keys as a login. The key acts as a retailer of its organization limited to
+// the scopes of the key, and is recorded as the user who created it.
+func withAPIKeySession(ctx context.Context, apiKey *apikey_s.APIKey) context.Context {
+	scopes := make([]permission.Permission, 0, len(apiKey.Scopes))
+	for _, scope := range apiKey.Scopes {
+		scopes = append(scopes, permission.Permission(scope))
+	}
+
+	ctx = context.WithValue(ctx, constants.SessionIsAuthorized, true)
+	ctx = context.WithValue(ctx, constants.SessionAPIKeyID, apiKey.ID)
+	ctx = context.WithValue(ctx, constants.SessionAPIKeyScopes, scopes)
+	ctx = context.WithValue(ctx, constants.SessionUserID, apiKey.CreatedByUserID)
+	ctx = context.WithValue(ctx, constants.SessionUserRole, int8(u_d.UserRoleRetailer))
+	ctx = context.WithValue(ctx, constants.SessionUserName, apiKey.Name)
+	ctx = context.WithValue(ctx, constants.SessionUserFirstName, apiKey.Name)
+	ctx = context.WithValue(ctx, constants.SessionUserLastName, "")
+	ctx = context.WithValue(ctx, constants.SessionUserOrganizationID, apiKey.OrganizationID)
+	ctx = context.WithValue(ctx, constants.SessionUserOrganizationName, apiKey.OrganizationName)
+	return ctx
+}
+
 func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -278,6 +258,12 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 			return
 		}
 
+		// Requests made with an API key already have their session.
+		if _, ok := ctx.Value(constants.SessionAPIKeyID).(primitive.ObjectID); ok {
+			fn(w, r.WithContext(ctx)) // Flow to the next middleware.
+			return
+		}
+
 		// Get our authorization information.
 		isAuthorized, ok := ctx.Value(constants.SessionIsAuthorized).(bool)
 		if ok && isAuthorized {
@@ -299,13 +285,17 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 				return
 			}
 
-			// // If system administrator disabled the user account then we need
-			// // to generate a 403 error letting the user know their account has
-			// // been disabled and you cannot access the protected API endpoint.
-			// if user.State == 0 {
-			// 	http.Error(w, "Account disabled - please contact admin", http.StatusForbidden)
-			// 	return
-			// }
+			// If system administrator disabled the user account then we need
+			// to generate a 403 error letting the user know their account has
+			// been disabled and you cannot access the protected API endpoint.
+			if user.Status == u_d.UserStatusArchived {
+				mid.Logger.Warn("Account disabled", slog.Any("user_id", user.ID))
+				http.Error(w, "Account disabled - please contact admin", http.StatusForbidden)
+				return
+			}
+
+			// Record where and when the session was last used.
+			mid.SessionController.Touch(ctx, sessionID)
 
 			// Save our user information to the context.
 			// Save our user.
@@ -336,18 +326,13 @@ func (mid *middleware) PostJWTProcessorMiddleware(fn http.HandlerFunc) http.Hand
 
 func (mid *middleware) IPAddressMiddleware(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// Extract the IPAddress. Code taken from: https://stackoverflow.com/a/55738279
-		IPAddress := r.Header.Get("X-Real-Ip")
-		if IPAddress == "" {
-			IPAddress = r.Header.Get("X-Forwarded-For")
-		}
-		if IPAddress == "" {
-			IPAddress = r.RemoteAddr
-		}
+		// Extract the IPAddress; only our proxies may forward it.
+		IPAddress := clientIP(r, mid.TrustedProxies)
 
 		// Save our IP address to the context.
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, constants.SessionIPAddress, IPAddress)
+		ctx = context.WithValue(ctx, constants.SessionUserAgent, r.UserAgent())
 		fn(w, r.WithContext(ctx)) // Flow to the next middleware.
 	}
 }
@@ -366,45 +351,16 @@ func (mid *middleware) ProtectedURLsMiddleware(fn http.HandlerFunc) http.Handler
 			return
 		}
 
-		// The following code will lookup the URL path in a whitelist and
-		// if the visited path matches then we will skip URL protection.
-		// We do this because a majority of API endpoints are protected
-		// by authorization.
-
-		urlSplit := ctx.Value("url_split").([]string)
-		skipPath := map[string]bool{
-			"version":         true,
-			"greeting":        true,
-			"login":           true,
-			"refresh-token":   true,
-			"verify":          true,
-			"forgot-password": true,
-			"password-reset":  true,
-			"cpsrn":           true,
-		}
-
-		// DEVELOPERS NOTE:
-		// If the URL cannot be split into the size we want then skip running
-		// this middleware.
-		if len(urlSplit) < 3 {
-			fn(w, r.WithContext(ctx)) // Flow to the next middleware.
-			return
-		}
+		// Get our authorization information.
+		isAuthorized, ok := ctx.Value(constants.SessionIsAuthorized).(bool)
 
-		if skipPath[urlSplit[2]] {
+		// Either accept continuing execution or return 401 error.
+		if ok && isAuthorized {
 			fn(w, r.WithContext(ctx)) // Flow to the next middleware.
 		} else {
-			// Get our authorization information.
-			isAuthorized, ok := ctx.Value(constants.SessionIsAuthorized).(bool)
-
-			// Either accept continuing execution or return 401 error.
-			if ok && isAuthorized {
-				fn(w, r.WithContext(ctx)) // Flow to the next middleware.
-			} else {
-				mid.Logger.Warn("unauthorized api call")
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
-				return
-			}
+			mid.Logger.Warn("unauthorized api call")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
 		}
 	}
 }
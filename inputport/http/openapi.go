@@ -0,0 +1,235 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	apikey_c "github.com/LuchaComics/cps-backend/app/apikey/controller"
+	a_s "github.com/LuchaComics/cps-backend/app/attachment/datastore"
+	audit_s "github.com/LuchaComics/cps-backend/app/audit/datastore"
+	sub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	sub_s "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
+	cust_c "github.com/LuchaComics/cps-backend/app/customer/controller"
+	gateway_c "github.com/LuchaComics/cps-backend/app/gateway/controller"
+	gateway_s "github.com/LuchaComics/cps-backend/app/gateway/datastore"
+	order_c "github.com/LuchaComics/cps-backend/app/order/controller"
+	order_s "github.com/LuchaComics/cps-backend/app/order/datastore"
+	org_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	report_s "github.com/LuchaComics/cps-backend/app/report/datastore"
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
+	usr_c "github.com/LuchaComics/cps-backend/app/user/controller"
+	usr_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	webhook_c "github.com/LuchaComics/cps-backend/app/webhook/controller"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/inputport/http/apikey"
+	"github.com/LuchaComics/cps-backend/inputport/http/comicsub"
+	"github.com/LuchaComics/cps-backend/inputport/http/customer"
+	"github.com/LuchaComics/cps-backend/inputport/http/gateway"
+	"github.com/LuchaComics/cps-backend/inputport/http/organization"
+	"github.com/LuchaComics/cps-backend/inputport/http/report"
+	"github.com/LuchaComics/cps-backend/inputport/http/router"
+	"github.com/LuchaComics/cps-backend/inputport/http/user"
+	"github.com/LuchaComics/cps-backend/inputport/http/webhook"
+	"github.com/LuchaComics/cps-backend/provider/jwt"
+	"github.com/LuchaComics/cps-backend/utils/export"
+	"github.com/LuchaComics/cps-backend/utils/openapi"
+)
+
+// The form fields of our `multipart/form-data` requests.
+type (
+	attachmentForm struct {
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		OwnershipID   string `json:"ownership_id"`
+		OwnershipType int8   `json:"ownership_type"`
+		File          []byte `json:"file"`
+	}
+	comicSubmissionImportForm struct {
+		OrganizationID string `json:"organization_id"`
+		IsDryRun       bool   `json:"dry_run"`
+		File           []byte `json:"file"`
+	}
+)
+
+// The bodies of our reports which differ only by their results.
+type (
+	submissionVolumeReport struct {
+		report.ReportResponse
+		Results []*report_s.VolumePoint `json:"results"`
+	}
+	gradeDistributionReport struct {
+		report.ReportResponse
+		Results []*report_s.GradeDistribution `json:"results"`
+	}
+	turnaroundReport struct {
+		report.ReportResponse
+		Results []*report_s.TurnaroundPoint `json:"results"`
+	}
+	topRetailersReport struct {
+		report.ReportResponse
+		Results []*report_s.RetailerTotal `json:"results"`
+	}
+)
+
+var (
+	listQuery   = []string{"cursor", "page_size", "search"}
+	exportQuery = []string{"format"}
+	reportQuery = []string{"organization_id", "service_type", "publisher_name", "bucket", "created_at_gte", "created_at_lt"}
+	exportTypes = []string{"text/csv", "application/x-ndjson"}
+	// exportHeaders document how a client tells an export which failed
+	// midway from a complete one.
+	exportHeaders = map[string]string{
+		export.ErrorTrailer: "Trailer set if the export failed after it started, in which case the file is incomplete; an NDJSON export then ends with an `export_error` line.",
+	}
+)
+
+// operations documents every route of `routes` by its method and pattern; a
+// route without an entry fails our tests.
+var operations = map[string]*openapi.Operation{
+	// --- GATEWAY & PROFILE & DASHBOARD --- //
+	"GET /v1/version":                     {Summary: "Returns the version of the server.", ContentTypes: []string{"text/plain"}},
+	"GET /v1/openapi.json":                {Summary: "Returns this document.", Response: map[string]interface{}{}},
+	"GET /.well-known/jwks.json":          {Summary: "Returns the public keys which verify our tokens as a JSON Web Key Set.", Response: &jwt.JWKS{}},
+	"POST /v1/greeting":                   {Summary: "Greets the visitor.", Request: &gateway.GreetingRequest{}, Response: &gateway.GreetingResponse{}},
+	"POST /v1/login":                      {Summary: "Logs in the user by email and password; users with two-factor authentication get a challenge token instead of a session.", Request: &gateway.LoginRequestIDO{}, Response: &gateway_s.LoginResponseIDO{}},
+	"POST /v1/login/2fa":                  {Summary: "Completes the login with the challenge token and a code of the authenticator app or a recovery code.", Request: &gateway_c.LoginOTPRequestIDO{}, Response: &gateway_s.LoginResponseIDO{}},
+	"POST /v1/login/2fa/enroll":           {Summary: "Returns a new two-factor secret for a user whose organization requires it; the next code confirms it.", Request: &gateway.LoginOTPEnrollRequestIDO{}, Response: &gateway_c.OTPEnrollResponseIDO{}},
+	"POST /v1/login/unlock":               {Summary: "Unlocks an account locked after too many failed logins with the code of the email.", Request: &gateway.LoginUnlockRequestIDO{}},
+	"POST /v1/register":                   {Summary: "Registers a retailer and its organization.", Request: &gateway_s.RegisterRequestIDO{}, Status: http.StatusCreated},
+	"POST /v1/refresh-token":              {Summary: "Exchanges the refresh token for new tokens.", Request: &gateway.RefreshTokenRequestIDO{}, Response: &gateway.RefreshTokenResponseIDO{}},
+	"POST /v1/verify":                     {Summary: "Verifies the email of the user.", Request: &gateway.VerifyRequestIDO{}, Status: http.StatusOK},
+	"POST /v1/logout":                     {Summary: "Logs out the user."},
+	"GET /v1/profile":                     {Summary: "Returns the logged in user.", Response: &usr_s.User{}},
+	"PUT /v1/profile":                     {Summary: "Updates the logged in user.", Request: &gateway.ProfileUpdateRequestIDO{}, Response: &usr_s.User{}},
+	"PUT /v1/profile/change-password":     {Summary: "Changes the password of the logged in user.", Request: &gateway_c.ProfileChangePasswordRequestIDO{}, Response: &usr_s.User{}},
+	"GET /v1/profile/2fa":                 {Summary: "Returns whether two-factor authentication is enabled and required.", Response: &gateway_c.OTPStatusResponseIDO{}},
+	"POST /v1/profile/2fa":                {Summary: "Returns a new two-factor secret to add to an authenticator app.", Response: &gateway_c.OTPEnrollResponseIDO{}},
+	"POST /v1/profile/2fa/confirm":        {Summary: "Enables two-factor authentication with a code of the new secret; the recovery codes are only returned this once.", Request: &gateway_c.OTPCodeRequestIDO{}, Response: &gateway_c.OTPRecoveryCodesResponseIDO{}},
+	"POST /v1/profile/2fa/recovery-codes": {Summary: "Replaces the recovery codes.", Request: &gateway_c.OTPCodeRequestIDO{}, Response: &gateway_c.OTPRecoveryCodesResponseIDO{}},
+	"POST /v1/profile/2fa/disable":        {Summary: "Disables two-factor authentication unless the organization requires it.", Request: &gateway_c.OTPCodeRequestIDO{}},
+	"GET /v1/profile/sessions":            {Summary: "Lists where the user is logged in.", Response: []*session_s.Session{}},
+	"DELETE /v1/profile/sessions":         {Summary: "Logs the user out everywhere else."},
+	"POST /v1/forgot-password":            {Summary: "Emails a link to reset the password.", Request: &gateway.ForgotPasswordRequestIDO{}},
+	"POST /v1/password-reset":             {Summary: "Resets the password with the code of the email.", Request: &gateway.PasswordResetRequestIDO{}},
+
+	// --- REGISTRY --- //
+	"GET /v1/cpsrn/{cpsrn}": {Summary: "Returns the registry entry of the CPS registry number.", Response: &comicsub.RegistryReponse{}},
+
+	// --- SUBMISSIONS --- //
+	"GET /v1/comic-submissions":                                               {Summary: "Lists the submissions.", Query: append([]string{"status", "organization_id", "user_id", "order_id", "created_at_gte"}, listQuery...), Response: &sub_s.ComicSubmissionListResult{}},
+	"POST /v1/comic-submissions":                                              {Summary: "Creates a submission.", Request: &sub_c.ComicSubmissionCreateRequestIDO{}, Response: &sub_s.ComicSubmission{}},
+	"POST /v1/comic-submissions/import":                                       {Summary: "Creates the submissions of a CSV or XLSX spreadsheet.", Request: &comicSubmissionImportForm{}, IsMultipart: true, Response: &sub_c.ComicSubmissionImportResponseIDO{}},
+	"GET /v1/comic-submissions/export":                                        {Summary: "Exports the submissions.", Query: append([]string{"status", "organization_id", "user_id", "created_at_gte"}, exportQuery...), ContentTypes: exportTypes, Headers: exportHeaders},
+	"GET /v1/comic-submissions/select-options":                                {Summary: "Lists the submissions as select options.", Query: []string{"organization_id", "user_id"}, Response: []*sub_s.ComicSubmissionAsSelectOption{}},
+	"POST /v1/comic-submissions/operation/set-user":                           {Summary: "Assigns the submission to the user.", Request: &comicsub.ComicSubmissionOperationSetUserRequest{}, Response: &sub_s.ComicSubmission{}},
+	"POST /v1/comic-submissions/operation/create-comment":                     {Summary: "Comments on the submission.", Request: &comicsub.ComicSubmissionOperationCreateCommentRequest{}, Response: &sub_s.ComicSubmission{}},
+	"GET /v1/comic-submission/{id}":                                           {Summary: "Returns the submission.", Response: &sub_s.ComicSubmission{}},
+	"PUT /v1/comic-submission/{id}":                                           {Summary: "Updates the submission.", Request: &sub_c.ComicSubmissionUpdateRequestIDO{}, Response: &sub_s.ComicSubmission{}},
+	"DELETE /v1/comic-submission/{id}":                                        {Summary: "Archives the submission."},
+	"DELETE /v1/comic-submission/{id}/perma-delete":                           {Summary: "Deletes the submission."},
+	"POST /v1/comic-submission/{id}/regenerate-pdf":                           {Summary: "Regenerates the certificate of the submission.", Response: &sub_s.ComicSubmission{}, Status: http.StatusAccepted},
+	"POST /v1/comic-submission/{id}/sign-off":                                 {Summary: "Signs off the grade of the submission.", Request: &sub_c.ComicSubmissionSignOffRequestIDO{}, Response: &sub_s.ComicSubmission{}},
+	"POST /v1/comic-submission/{id}/transition":                               {Summary: "Changes the status of the submission.", Request: &sub_c.ComicSubmissionTransitionRequestIDO{}, Response: &sub_s.ComicSubmission{}},
+	"GET /v1/comic-submission/{id}/grade-revisions":                           {Summary: "Lists the grade revisions of the submission.", Response: &comicsub.GradeRevisionListResponse{}},
+	"GET /v1/comic-submission/{id}/grade-revisions/{revision_id}/certificate": {Summary: "Redirects to the certificate of the grade revision.", Status: http.StatusTemporaryRedirect},
+
+	// --- ORGANIZATION --- //
+	"GET /v1/organizations":                                                            {Summary: "Lists the organizations.", Query: append([]string{"status"}, listQuery...), Response: &org_s.OrganizationListResult{}},
+	"POST /v1/organizations":                                                           {Summary: "Creates an organization.", Request: &org_s.Organization{}, Response: &org_s.Organization{}},
+	"GET /v1/organizations/export":                                                     {Summary: "Exports the organizations.", Query: exportQuery, ContentTypes: exportTypes, Headers: exportHeaders},
+	"GET /v1/organizations/select-options":                                             {Summary: "Lists the organizations as select options.", Response: []*org_s.OrganizationAsSelectOption{}},
+	"POST /v1/organizations/operation/create-comment":                                  {Summary: "Comments on the organization.", Request: &organization.OrganizationOperationCreateCommentRequest{}, Response: &org_s.Organization{}},
+	"GET /v1/organization/{id}":                                                        {Summary: "Returns the organization.", Response: &org_s.Organization{}},
+	"PUT /v1/organization/{id}":                                                        {Summary: "Updates the organization.", Request: &org_s.Organization{}, Response: &org_s.Organization{}},
+	"DELETE /v1/organization/{id}":                                                     {Summary: "Deletes the organization."},
+	"GET /v1/organization/{id}/api-keys":                                               {Summary: "Lists the API keys of the organization.", Response: &apikey.APIKeyListResponse{}},
+	"POST /v1/organization/{id}/api-keys":                                              {Summary: "Creates an API key; the key is only returned this once.", Request: &apikey_c.APIKeyCreateRequestIDO{}, Response: &apikey_c.APIKeyCreateResponseIDO{}, Status: http.StatusCreated},
+	"DELETE /v1/organization/{id}/api-keys/{api_key_id}":                               {Summary: "Revokes the API key."},
+	"GET /v1/organization/{id}/webhooks":                                               {Summary: "Lists the webhooks of the organization.", Response: &webhook.WebhookListResponse{}},
+	"POST /v1/organization/{id}/webhooks":                                              {Summary: "Creates a webhook; the signing secret is only returned this once.", Request: &webhook_c.WebhookCreateRequestIDO{}, Response: &webhook_c.WebhookCreateResponseIDO{}, Status: http.StatusCreated},
+	"GET /v1/organization/{id}/webhooks/{webhook_id}":                                  {Summary: "Gets the webhook.", Response: &webhook_s.Subscription{}},
+	"PUT /v1/organization/{id}/webhooks/{webhook_id}":                                  {Summary: "Updates the url, events and status of the webhook.", Request: &webhook_c.WebhookUpdateRequestIDO{}, Response: &webhook_s.Subscription{}},
+	"DELETE /v1/organization/{id}/webhooks/{webhook_id}":                               {Summary: "Deletes the webhook."},
+	"POST /v1/organization/{id}/webhooks/{webhook_id}/test":                            {Summary: "Queues a `ping` event to the webhook.", Response: &webhook_s.Delivery{}, Status: http.StatusAccepted},
+	"GET /v1/organization/{id}/webhooks/{webhook_id}/deliveries":                       {Summary: "Lists the delivery log of the webhook, newest first.", Query: []string{"cursor", "page_size", "status"}, Response: &webhook_s.DeliveryListResult{}},
+	"POST /v1/organization/{id}/webhooks/{webhook_id}/deliveries/{delivery_id}/replay": {Summary: "Sends a failed delivery again.", Response: &webhook_s.Delivery{}, Status: http.StatusAccepted},
+
+	// --- CUSTOMERS --- //
+	"GET /v1/customers":                           {Summary: "Lists the customers.", Query: append([]string{"organization_id", "first_name", "email", "phone"}, listQuery...), Response: &usr_s.UserListResult{}},
+	"POST /v1/customers":                          {Summary: "Creates a customer.", Request: &cust_c.CustomerCreateRequestIDO{}, Response: &usr_s.User{}},
+	"GET /v1/customers/export":                    {Summary: "Exports the customers.", Query: append([]string{"organization_id"}, exportQuery...), ContentTypes: exportTypes, Headers: exportHeaders},
+	"POST /v1/customers/operation/create-comment": {Summary: "Comments on the customer.", Request: &customer.CustomerOperationCreateCommentRequest{}, Response: &usr_s.User{}},
+	"GET /v1/customer/{id}":                       {Summary: "Returns the customer.", Response: &usr_s.User{}},
+	"PUT /v1/customer/{id}":                       {Summary: "Updates the customer.", Request: &usr_s.User{}, Response: &usr_s.User{}},
+	"DELETE /v1/customer/{id}":                    {Summary: "Deletes the customer."},
+
+	// --- USERS --- //
+	"GET /v1/users":                           {Summary: "Lists the users.", Query: append([]string{"organization_id", "role", "status"}, listQuery...), Response: &usr_s.UserListResult{}},
+	"POST /v1/users":                          {Summary: "Creates a user.", Request: &usr_c.UserCreateRequestIDO{}, Response: &usr_s.User{}},
+	"GET /v1/users/select-options":            {Summary: "Lists the users as select options.", Query: []string{"organization_id", "role"}, Response: []*usr_s.UserAsSelectOption{}},
+	"POST /v1/users/operation/create-comment": {Summary: "Comments on the user.", Request: &user.UserOperationCreateCommentRequest{}, Response: &usr_s.User{}},
+	"GET /v1/user/{id}":                       {Summary: "Returns the user.", Response: &usr_s.User{}},
+	"PUT /v1/user/{id}":                       {Summary: "Updates the user.", Request: &usr_c.UserUpdateRequestIDO{}, Response: &usr_s.User{}},
+	"DELETE /v1/user/{id}":                    {Summary: "Deletes the user."},
+	"DELETE /v1/user/{id}/sessions":           {Summary: "Logs the user out everywhere."},
+	"POST /v1/user/{id}/unlock":               {Summary: "Lifts the lockout of the user after too many failed logins."},
+
+	// --- AUDIT TRAIL --- //
+	"GET /v1/audit-events": {Summary: "Lists the audit trail, newest first.", Query: []string{"cursor", "page_size", "user_id", "type"}, Response: &audit_s.EventListResult{}},
+
+	// --- ATTACHMENTS --- //
+	"GET /v1/attachments":        {Summary: "Lists the attachments.", Query: append([]string{"ownership_id"}, listQuery...), Response: &a_s.AttachmentListResult{}},
+	"POST /v1/attachments":       {Summary: "Uploads an attachment.", Request: &attachmentForm{}, IsMultipart: true, Response: &a_s.Attachment{}},
+	"GET /v1/attachment/{id}":    {Summary: "Returns the attachment.", Response: &a_s.Attachment{}},
+	"PUT /v1/attachment/{id}":    {Summary: "Updates the attachment.", Request: &attachmentForm{}, IsMultipart: true, Response: &a_s.Attachment{}},
+	"DELETE /v1/attachment/{id}": {Summary: "Deletes the attachment."},
+
+	// --- ORDERS --- //
+	"GET /v1/orders":     {Summary: "Lists the orders.", Query: append([]string{"organization_id", "status"}, listQuery...), Response: &order_s.OrderListResult{}},
+	"POST /v1/orders":    {Summary: "Creates an order and its submissions.", Request: &order_c.OrderCreateRequestIDO{}, Response: &order_s.Order{}},
+	"GET /v1/order/{id}": {Summary: "Returns the order.", Response: &order_s.Order{}},
+	"POST /v1/order/{id}/regenerate-packing-slip": {Summary: "Regenerates the packing slip of the order.", Response: &order_s.Order{}},
+
+	// --- REPORTS --- //
+	"GET /v1/reports/submission-volume":  {Summary: "Counts the submissions received per bucket and service type.", Query: reportQuery, Response: &submissionVolumeReport{}},
+	"GET /v1/reports/grade-distribution": {Summary: "Counts the grades given per publisher.", Query: reportQuery, Response: &gradeDistributionReport{}},
+	"GET /v1/reports/turnaround":         {Summary: "Averages the hours between receiving and shipping per bucket.", Query: reportQuery, Response: &turnaroundReport{}},
+	"GET /v1/reports/top-retailers":      {Summary: "Ranks the retailers by submissions received.", Query: append([]string{"limit"}, reportQuery...), Response: &topRetailersReport{}},
+}
+
+// NewOpenAPIDocument returns the document of the routes; routes without an
+// entry in `operations` are documented by their path only.
+func NewOpenAPIDocument(routes []*router.Route) *openapi.Document {
+	doc := openapi.NewDocument("CPS Backend", "v1.0")
+	for _, route := range routes {
+		var op openapi.Operation
+		if documented, ok := operations[route.Method+" "+route.Pattern]; ok {
+			op = *documented
+		}
+		if op.Tag == "" {
+			op.Tag = operationTag(route.Pattern)
+		}
+		doc.AddOperation(route.Method, route.Pattern, route.IsPublic, route.AllowAPIKey, string(route.Permission), &op)
+	}
+	return doc
+}
+
+// operationTag groups the routes by their resource, ex: `comic-submission` for
+// both `/v1/comic-submissions/export` and `/v1/comic-submission/{id}`; the
+// login, profile and other routes of the gateway are grouped together.
+func operationTag(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	if len(segments) < 4 || segments[2] == "profile" || segments[2] == "login" {
+		return "gateway"
+	}
+	return strings.TrimSuffix(segments[2], "s")
+}
+
+// OpenAPI returns the OpenAPI document of our API.
+func (port *httpInputPort) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	if err := json.NewEncoder(w).Encode(port.OpenAPIDocument); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
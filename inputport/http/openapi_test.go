@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/LuchaComics/cps-backend/inputport/http/router"
+)
+
+func TestOperationsDocumentEveryRoute(t *testing.T) {
+	routes := (&httpInputPort{}).routes()
+
+	documented := make(map[string]bool)
+	for _, route := range routes {
+		key := route.Method + " " + route.Pattern
+		if _, ok := operations[key]; !ok {
+			t.Errorf("route %v is missing from the OpenAPI operations", key)
+		}
+		documented[key] = true
+	}
+	for key := range operations {
+		if !documented[key] {
+			t.Errorf("OpenAPI operation %v has no route", key)
+		}
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	port := &httpInputPort{}
+	port.Router = router.New(port.routes())
+	port.OpenAPIDocument = NewOpenAPIDocument(port.Router.Routes())
+
+	w := httptest.NewRecorder()
+	port.OpenAPI(w, httptest.NewRequest(http.MethodGet, "/v1/openapi.json", nil))
+
+	var doc struct {
+		OpenAPI string                                       `json:"openapi"`
+		Paths   map[string]map[string]map[string]interface{} `json:"paths"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed to decode the document: %v", err)
+	}
+	if doc.OpenAPI == "" {
+		t.Error("expected the OpenAPI version")
+	}
+	for _, route := range port.Router.Routes() {
+		if _, ok := doc.Paths[route.Pattern][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("expected the document to have %v %v", route.Method, route.Pattern)
+		}
+	}
+	if _, ok := doc.Paths["/v1/order/{id}"]["get"]["requestBody"]; ok {
+		t.Error("expected no request body for GET /v1/order/{id}")
+	}
+	if _, ok := doc.Paths["/v1/orders"]["post"]["requestBody"]; !ok {
+		t.Error("expected a request body for POST /v1/orders")
+	}
+}
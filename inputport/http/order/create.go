@@ -0,0 +1,116 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	order_c "github.com/LuchaComics/cps-backend/app/order/controller"
+	order_s "github.com/LuchaComics/cps-backend/app/order/datastore"
+	"github.com/LuchaComics/cps-backend/inputport/http/comicsub"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func UnmarshalCreateRequest(ctx context.Context, r *http.Request) (*order_c.OrderCreateRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData order_c.OrderCreateRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		log.Println(err)
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	if err := ValidateCreateRequest(&requestData); err != nil {
+		return nil, err
+	}
+	return &requestData, nil
+}
+
+// ValidateCreateRequest validates the order and every one of its lines; the
+// errors of a line are keyed by its position, ex: `lines[3].series_title`.
+func ValidateCreateRequest(dirtyData *order_c.OrderCreateRequestIDO) error {
+	e := make(map[string]string)
+
+	if s := dirtyData.Shipping; s == nil {
+		e["shipping"] = "missing value"
+	} else {
+		if s.Name == "" {
+			e["shipping.name"] = "missing value"
+		}
+		if s.AddressLine1 == "" {
+			e["shipping.address_line_1"] = "missing value"
+		}
+		if s.City == "" {
+			e["shipping.city"] = "missing value"
+		}
+		if s.Country == "" {
+			e["shipping.country"] = "missing value"
+		}
+	}
+	if len(dirtyData.Lines) == 0 {
+		e["lines"] = "missing value"
+	} else if len(dirtyData.Lines) > order_c.MaxOrderLines {
+		e["lines"] = fmt.Sprintf("over %v submissions", order_c.MaxOrderLines)
+	}
+
+	for i, line := range dirtyData.Lines {
+		if line == nil {
+			e[fmt.Sprintf("lines[%d]", i)] = "missing value"
+			continue
+		}
+		if line.DeclaredValue < 0 {
+			e[fmt.Sprintf("lines[%d].declared_value", i)] = "cannot be negative"
+		}
+
+		// Every line belongs to the organization of the order, which is
+		// checked by the controller as retailers do not pick it.
+		line.OrganizationID = dirtyData.OrganizationID
+		var httpErr httperror.HTTPError
+		if err := comicsub.ValidateCreateRequest(&line.ComicSubmissionCreateRequestIDO); errors.As(err, &httpErr) {
+			for field, message := range *httpErr.Errors {
+				if field != "organization_id" {
+					e[fmt.Sprintf("lines[%d].%v", i, field)] = message
+				}
+			}
+		}
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := UnmarshalCreateRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	res, err := h.Controller.Create(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalCreateResponse(res, w)
+}
+
+func MarshalCreateResponse(res *order_s.Order, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
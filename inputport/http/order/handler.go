@@ -0,0 +1,17 @@
+package order
+
+import (
+	order_c "github.com/LuchaComics/cps-backend/app/order/controller"
+)
+
+// Handler Creates http request handler
+type Handler struct {
+	Controller order_c.OrderController
+}
+
+// NewHandler Constructor
+func NewHandler(c order_c.OrderController) *Handler {
+	return &Handler{
+		Controller: c,
+	}
+}
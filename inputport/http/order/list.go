@@ -0,0 +1,96 @@
+package order
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/bartmika/timekit"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	order_s "github.com/LuchaComics/cps-backend/app/order/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	f := &order_s.OrderListFilter{
+		Cursor:          primitive.NilObjectID,
+		PageSize:        25,
+		SortField:       "_id",
+		SortOrder:       1, // 1=ascending | -1=descending
+		ExcludeArchived: true,
+	}
+
+	// Here is where you extract url parameters.
+	query := r.URL.Query()
+	organizationID := query.Get("organization_id")
+	if organizationID != "" {
+		organizationID, err := primitive.ObjectIDFromHex(organizationID)
+		if err != nil {
+			httperror.ResponseError(w, err)
+			return
+		}
+		f.OrganizationID = organizationID
+	}
+
+	userID := query.Get("user_id")
+	if userID != "" {
+		userID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			httperror.ResponseError(w, err)
+			return
+		}
+		f.UserID = userID
+	}
+
+	cursor := query.Get("cursor")
+	if cursor != "" {
+		cursor, err := primitive.ObjectIDFromHex(cursor)
+		if err != nil {
+			httperror.ResponseError(w, err)
+			return
+		}
+		f.Cursor = cursor
+	}
+
+	pageSize := query.Get("page_size")
+	if pageSize != "" {
+		pageSize, _ := strconv.ParseInt(pageSize, 10, 64)
+		if pageSize == 0 || pageSize > 250 {
+			pageSize = 250
+		}
+		f.PageSize = pageSize
+	}
+
+	statusStr := query.Get("status")
+	if statusStr != "" {
+		status, _ := strconv.ParseInt(statusStr, 10, 64)
+		f.Status = int8(status)
+	}
+	createdAtGTEStr := query.Get("created_at_gte")
+	if createdAtGTEStr != "" {
+		createdAtGTE, err := timekit.ParseJavaScriptTimeString(createdAtGTEStr)
+		if err != nil {
+			httperror.ResponseError(w, err)
+			return
+		}
+		f.CreatedAtGTE = createdAtGTE
+	}
+
+	m, err := h.Controller.ListByFilter(ctx, f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalListResponse(m, w)
+}
+
+func MarshalListResponse(res *order_s.OrderListResult, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
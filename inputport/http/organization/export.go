@@ -0,0 +1,54 @@
+package organization
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	org_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	"github.com/LuchaComics/cps-backend/utils/export"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+var exportHeader = []string{
+	"id",
+	"name",
+	"type",
+	"status",
+	"created_at",
+	"modified_at",
+}
+
+func exportRow(m *org_s.Organization) []string {
+	return []string{
+		m.ID.Hex(),
+		m.Name,
+		strconv.Itoa(int(m.Type)),
+		strconv.Itoa(int(m.Status)),
+		m.CreatedAt.Format(time.RFC3339),
+		m.ModifiedAt.Format(time.RFC3339),
+	}
+}
+
+// Export streams every organization of the list filters as a CSV or NDJSON
+// file.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	f, err := unmarshalListFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	ew := export.NewWriter(w, format, "organizations", exportHeader)
+	err = h.Controller.ExportByFilter(ctx, f, func(m *org_s.Organization) error {
+		return ew.Write(m, func() []string { return exportRow(m) })
+	})
+	ew.Close(err)
+}
@@ -11,9 +11,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
+// unmarshalListFilter returns the filter of the url parameters which is
+// shared by our list and export endpoints.
+func unmarshalListFilter(r *http.Request) (*sub_s.OrganizationListFilter, error) {
+	// Initialize the list filter with base results and then override them with the URL parameters.
 	f := &sub_s.OrganizationListFilter{
 		Cursor:          primitive.NilObjectID,
 		PageSize:        25,
@@ -29,8 +30,7 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	if cursor != "" {
 		cursor, err := primitive.ObjectIDFromHex(cursor)
 		if err != nil {
-			httperror.ResponseError(w, err)
-			return
+			return nil, err
 		}
 		f.Cursor = cursor
 	}
@@ -58,12 +58,23 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	if createdAtGTEStr != "" {
 		createdAtGTE, err := timekit.ParseJavaScriptTimeString(createdAtGTEStr)
 		if err != nil {
-			httperror.ResponseError(w, err)
-			return
+			return nil, err
 		}
 		f.CreatedAtGTE = createdAtGTE
 	}
 
+	return f, nil
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	f, err := unmarshalListFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
 	m, err := h.Controller.ListByFilter(ctx, f)
 	if err != nil {
 		httperror.ResponseError(w, err)
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	sub_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	"github.com/LuchaComics/cps-backend/app/permission"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -40,6 +41,11 @@ func ValidateUpdateRequest(dirtyData *sub_s.Organization) error {
 	if dirtyData.Name == "" {
 		e["name"] = "missing value"
 	}
+	for _, role := range dirtyData.OTPRequiredRoles {
+		if _, ok := permission.Roles[role]; !ok {
+			e["otp_required_roles"] = "invalid value"
+		}
+	}
 	if len(e) != 0 {
 		return httperror.NewForBadRequest(&e)
 	}
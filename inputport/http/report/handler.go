@@ -0,0 +1,17 @@
+package report
+
+import (
+	report_c "github.com/LuchaComics/cps-backend/app/report/controller"
+)
+
+// Handler Creates http request handler
+type Handler struct {
+	Controller report_c.ReportController
+}
+
+// NewHandler Constructor
+func NewHandler(c report_c.ReportController) *Handler {
+	return &Handler{
+		Controller: c,
+	}
+}
@@ -0,0 +1,174 @@
+package report
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bartmika/timekit"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	report_s "github.com/LuchaComics/cps-backend/app/report/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// defaultReportPeriod is how far back our reports go unless the range is
+// picked in the url parameters.
+const defaultReportPeriod = 90 * 24 * time.Hour
+
+// ReportResponse is the body of every report; the filter is returned so the
+// dashboard can label the time series.
+type ReportResponse struct {
+	Bucket       string      `json:"bucket"`
+	CreatedAtGTE time.Time   `json:"created_at_gte"`
+	CreatedAtLT  time.Time   `json:"created_at_lt"`
+	Results      interface{} `json:"results"`
+}
+
+func UnmarshalReportFilter(r *http.Request) (*report_s.ReportFilter, error) {
+	f := &report_s.ReportFilter{
+		Bucket:       report_s.BucketWeek,
+		CreatedAtGTE: time.Now().Add(-defaultReportPeriod),
+		CreatedAtLT:  time.Now(),
+		Limit:        10,
+	}
+	e := make(map[string]string)
+
+	// Here is where you extract url parameters.
+	query := r.URL.Query()
+	if v := query.Get("organization_id"); v != "" {
+		organizationID, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			e["organization_id"] = "invalid value"
+		}
+		f.OrganizationID = organizationID
+	}
+	if v := query.Get("service_type"); v != "" {
+		serviceType, err := strconv.ParseInt(v, 10, 8)
+		if err != nil {
+			e["service_type"] = "invalid value"
+		}
+		f.ServiceType = int8(serviceType)
+	}
+	if v := query.Get("publisher_name"); v != "" {
+		publisherName, err := strconv.ParseInt(v, 10, 8)
+		if err != nil {
+			e["publisher_name"] = "invalid value"
+		}
+		f.PublisherName = int8(publisherName)
+	}
+	if v := query.Get("bucket"); v != "" {
+		switch v {
+		case report_s.BucketDay, report_s.BucketWeek, report_s.BucketMonth:
+			f.Bucket = v
+		default:
+			e["bucket"] = "must be day, week or month"
+		}
+	}
+	if v := query.Get("created_at_gte"); v != "" {
+		createdAtGTE, err := timekit.ParseJavaScriptTimeString(v)
+		if err != nil {
+			e["created_at_gte"] = "invalid value"
+		}
+		f.CreatedAtGTE = createdAtGTE
+	}
+	if v := query.Get("created_at_lt"); v != "" {
+		createdAtLT, err := timekit.ParseJavaScriptTimeString(v)
+		if err != nil {
+			e["created_at_lt"] = "invalid value"
+		}
+		f.CreatedAtLT = createdAtLT
+	}
+	if !f.CreatedAtLT.After(f.CreatedAtGTE) {
+		e["created_at_lt"] = "must be after created_at_gte"
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, _ := strconv.ParseInt(v, 10, 64)
+		if limit <= 0 || limit > 100 {
+			limit = 100
+		}
+		f.Limit = limit
+	}
+
+	if len(e) != 0 {
+		return nil, httperror.NewForBadRequest(&e)
+	}
+	return f, nil
+}
+
+// SubmissionVolume returns the number of submissions per bucket and service
+// type.
+func (h *Handler) SubmissionVolume(w http.ResponseWriter, r *http.Request) {
+	f, err := UnmarshalReportFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	res, err := h.Controller.SubmissionVolume(r.Context(), f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalReportResponse(f, res, w)
+}
+
+// GradeDistribution returns the number of submissions per publisher and
+// grade.
+func (h *Handler) GradeDistribution(w http.ResponseWriter, r *http.Request) {
+	f, err := UnmarshalReportFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	res, err := h.Controller.GradeDistribution(r.Context(), f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalReportResponse(f, res, w)
+}
+
+// Turnaround returns the average hours between receiving and shipping the
+// submissions per bucket.
+func (h *Handler) Turnaround(w http.ResponseWriter, r *http.Request) {
+	f, err := UnmarshalReportFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	res, err := h.Controller.Turnaround(r.Context(), f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalReportResponse(f, res, w)
+}
+
+// TopRetailers returns the organizations which sent us the most submissions.
+func (h *Handler) TopRetailers(w http.ResponseWriter, r *http.Request) {
+	f, err := UnmarshalReportFilter(r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	res, err := h.Controller.TopRetailers(r.Context(), f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	MarshalReportResponse(f, res, w)
+}
+
+func MarshalReportResponse(f *report_s.ReportFilter, results interface{}, w http.ResponseWriter) {
+	res := &ReportResponse{
+		Bucket:       f.Bucket,
+		CreatedAtGTE: f.CreatedAtGTE,
+		CreatedAtLT:  f.CreatedAtLT,
+		Results:      results,
+	}
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
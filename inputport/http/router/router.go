@@ -0,0 +1,194 @@
+// Package router matches the requests of our API against a table of routes.
+// Every route declares whether it is public and the permission it requires so
+// our middleware and the OpenAPI document are derived from the same table.
+package router
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// Route is one endpoint of our API.
+type Route struct {
+	Method string
+
+	// Pattern is the slash-separated path of the route where a `{name}`
+	// segment matches any value, ex: `/v1/comic-submission/{id}`.
+	Pattern string
+
+	Handler http.HandlerFunc
+
+	// IsPublic is true if the route can be visited without being logged in.
+	IsPublic bool
+
+	// Permission is checked before the handler is called unless empty, in
+	// which case any logged in user may visit the route.
+	Permission permission.Permission
+
+	// AllowAPIKey is true if requests made with an API key may visit the
+	// route. Only routes whose permission is an API key scope should allow
+	// them; everything else, like the profile, is for logged in users.
+	AllowAPIKey bool
+
+	segments []string
+}
+
+type contextKey int
+
+const (
+	routeContextKey contextKey = iota
+	paramsContextKey
+)
+
+// Router holds the route table of our API.
+type Router struct {
+	routes []*Route
+}
+
+// New returns a router of the routes. It panics on a malformed pattern as the
+// table is part of our code.
+func New(routes []*Route) *Router {
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Pattern, "/") || route.Handler == nil {
+			panic("router: invalid route " + route.Method + " " + route.Pattern)
+		}
+		route.segments = strings.Split(route.Pattern, "/")[1:]
+	}
+	return &Router{routes: routes}
+}
+
+// Routes returns the route table.
+func (rt *Router) Routes() []*Route {
+	return rt.routes
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// match returns the values of the `{name}` segments and how many segments
+// matched literally, or false if the path does not match the route.
+func (route *Route) match(segments []string) (map[string]string, int, bool) {
+	if len(segments) != len(route.segments) {
+		return nil, 0, false
+	}
+	var params map[string]string
+	literals := 0
+	for i, s := range route.segments {
+		if isParam(s) {
+			if segments[i] == "" {
+				return nil, 0, false
+			}
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[s[1:len(s)-1]] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, 0, false
+		}
+		literals++
+	}
+	return params, literals, true
+}
+
+// Match returns the route of the method and path along with the values of its
+// `{name}` segments. If only the method does not match then the methods the
+// path allows are returned instead. When several routes match, the one with
+// the most literal segments wins, ex: `/v1/orders/export` over `/v1/orders/{id}`.
+func (rt *Router) Match(method string, path string) (*Route, map[string]string, []string) {
+	segments := strings.Split(path, "/")[1:]
+
+	var matched *Route
+	var matchedParams map[string]string
+	matchedLiterals := -1
+	allowed := make(map[string]bool)
+	for _, route := range rt.routes {
+		params, literals, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		allowed[route.Method] = true
+		if route.Method == method && literals > matchedLiterals {
+			matched, matchedParams, matchedLiterals = route, params, literals
+		}
+	}
+	if matched != nil {
+		return matched, matchedParams, nil
+	}
+
+	allow := make([]string, 0, len(allowed))
+	for m := range allowed {
+		allow = append(allow, m)
+	}
+	sort.Strings(allow)
+	return nil, nil, allow
+}
+
+// Resolve looks up the route of the request and saves it to the context for
+// the middleware and handler downstream. Unknown paths get a `404 Not Found`
+// and known paths visited with the wrong method get a `405 Method Not Allowed`
+// with the `Allow` header.
+func (rt *Router) Resolve(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route, params, allow := rt.Match(r.Method, r.URL.Path)
+		if route == nil {
+			if len(allow) == 0 {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			httperror.ResponseError(w, httperror.NewForSingleField(http.StatusMethodNotAllowed, "message", "method not allowed"))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), routeContextKey, route)
+		ctx = context.WithValue(ctx, paramsContextKey, params)
+		fn(w, r.WithContext(ctx)) // Flow to the next middleware.
+	}
+}
+
+// Dispatch calls the handler of the route of the request once the logged in
+// user was checked to have the permission of the route. Requests made with an
+// API key are forbidden unless the route allows them.
+func (rt *Router) Dispatch(w http.ResponseWriter, r *http.Request) {
+	route := RouteFromContext(r.Context())
+	if route == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if !route.IsPublic && !route.AllowAPIKey {
+		if _, ok := r.Context().Value(constants.SessionAPIKeyID).(primitive.ObjectID); ok {
+			httperror.ResponseError(w, httperror.NewForForbiddenWithSingleField("message", "api keys cannot access this route"))
+			return
+		}
+	}
+	if !route.IsPublic && route.Permission != "" {
+		if err := permission.Check(r.Context(), route.Permission); err != nil {
+			httperror.ResponseError(w, err)
+			return
+		}
+	}
+	route.Handler(w, r)
+}
+
+// RouteFromContext returns the route resolved for the request, if any.
+func RouteFromContext(ctx context.Context) *Route {
+	route, _ := ctx.Value(routeContextKey).(*Route)
+	return route
+}
+
+// Param returns the value of the `{name}` segment of the route.
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsContextKey).(map[string]string)
+	return params[name]
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+)
+
+func newTestRouter(called *string) *Router {
+	handler := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*called = name + ":" + Param(r, "id")
+		}
+	}
+	return New([]*Route{
+		{Method: http.MethodGet, Pattern: "/v1/orders", Handler: handler("list"), Permission: permission.OrderManage, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/profile", Handler: handler("profile")},
+		{Method: http.MethodPost, Pattern: "/v1/orders", Handler: handler("create"), Permission: permission.OrderManage},
+		{Method: http.MethodGet, Pattern: "/v1/order/{id}", Handler: handler("get"), Permission: permission.OrderManage},
+		{Method: http.MethodGet, Pattern: "/v1/order/export", Handler: handler("export"), Permission: permission.OrderManage},
+		{Method: http.MethodGet, Pattern: "/v1/version", Handler: handler("version"), IsPublic: true},
+	})
+}
+
+func serve(rt *Router, method string, path string, role int8) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, nil)
+	if role != 0 {
+		r = r.WithContext(context.WithValue(r.Context(), constants.SessionUserRole, role))
+	}
+	w := httptest.NewRecorder()
+	rt.Resolve(rt.Dispatch)(w, r)
+	return w
+}
+
+func TestResolve(t *testing.T) {
+	var called string
+	rt := newTestRouter(&called)
+
+	tests := []struct {
+		method   string
+		path     string
+		code     int
+		expected string
+	}{
+		{http.MethodGet, "/v1/orders", http.StatusOK, "list:"},
+		{http.MethodPost, "/v1/orders", http.StatusOK, "create:"},
+		{http.MethodGet, "/v1/order/64a1", http.StatusOK, "get:64a1"},
+		{http.MethodGet, "/v1/order/export", http.StatusOK, "export:"},
+		{http.MethodGet, "/v1/order/", http.StatusNotFound, ""},
+		{http.MethodGet, "/v1/orders/64a1/unknown", http.StatusNotFound, ""},
+	}
+	for _, test := range tests {
+		called = ""
+		w := serve(rt, test.method, test.path, u_d.UserRoleRoot)
+		if w.Code != test.code || called != test.expected {
+			t.Errorf("%v %v: expected %v %q but got %v %q", test.method, test.path, test.code, test.expected, w.Code, called)
+		}
+	}
+}
+
+func TestResolveMethodNotAllowed(t *testing.T) {
+	var called string
+	w := serve(newTestRouter(&called), http.MethodDelete, "/v1/orders", u_d.UserRoleRoot)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected %v but got %v", http.StatusMethodNotAllowed, w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, POST" {
+		t.Errorf("expected allow header %q but got %q", "GET, POST", allow)
+	}
+	if called != "" {
+		t.Errorf("expected no handler to be called but got %q", called)
+	}
+}
+
+func TestDispatchChecksPermission(t *testing.T) {
+	var called string
+	rt := newTestRouter(&called)
+
+	if w := serve(rt, http.MethodGet, "/v1/orders", u_d.UserRoleCustomer); w.Code != http.StatusForbidden || called != "" {
+		t.Errorf("expected customer to be forbidden but got %v %q", w.Code, called)
+	}
+	if w := serve(rt, http.MethodGet, "/v1/version", 0); w.Code != http.StatusOK || called != "version:" {
+		t.Errorf("expected public route to be served but got %v %q", w.Code, called)
+	}
+}
+
+func TestDispatchForbidsAPIKeys(t *testing.T) {
+	var called string
+	rt := newTestRouter(&called)
+
+	tests := []struct {
+		method   string
+		path     string
+		code     int
+		expected string
+	}{
+		{http.MethodGet, "/v1/orders", http.StatusOK, "list:"},
+		{http.MethodPost, "/v1/orders", http.StatusForbidden, ""},
+		{http.MethodGet, "/v1/profile", http.StatusForbidden, ""},
+		{http.MethodGet, "/v1/version", http.StatusOK, "version:"},
+	}
+	for _, test := range tests {
+		called = ""
+		r := httptest.NewRequest(test.method, test.path, nil)
+		ctx := context.WithValue(r.Context(), constants.SessionUserRole, int8(u_d.UserRoleRetailer))
+		ctx = context.WithValue(ctx, constants.SessionAPIKeyID, primitive.NewObjectID())
+		ctx = context.WithValue(ctx, constants.SessionAPIKeyScopes, []permission.Permission{permission.OrderManage})
+		w := httptest.NewRecorder()
+		rt.Resolve(rt.Dispatch)(w, r.WithContext(ctx))
+		if w.Code != test.code || called != test.expected {
+			t.Errorf("%v %v: expected %v %q but got %v %q", test.method, test.path, test.code, test.expected, w.Code, called)
+		}
+	}
+}
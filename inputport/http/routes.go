@@ -0,0 +1,152 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	"github.com/LuchaComics/cps-backend/inputport/http/router"
+)
+
+// withID passes the `{id}` segment of the route to handlers which take it as
+// an argument.
+func withID(fn func(w http.ResponseWriter, r *http.Request, id string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fn(w, r, router.Param(r, "id"))
+	}
+}
+
+// routes returns the route table of our API. Routes are private unless marked
+// public; the permission, if any, is checked before the handler is called and
+// the controllers still apply their own ownership and tenancy rules.
+// API keys may only visit the routes whose permission is an API key scope.
+func (port *httpInputPort) routes() []*router.Route {
+	return []*router.Route{
+		// --- GATEWAY & PROFILE & DASHBOARD --- //
+		{Method: http.MethodGet, Pattern: "/v1/version", Handler: port.Gateway.Version, IsPublic: true},
+		{Method: http.MethodGet, Pattern: "/v1/openapi.json", Handler: port.OpenAPI, IsPublic: true},
+		{Method: http.MethodGet, Pattern: "/.well-known/jwks.json", Handler: port.Gateway.JWKS, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/greeting", Handler: port.Gateway.Greet, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/login", Handler: port.Gateway.Login, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/login/2fa", Handler: port.Gateway.LoginOTP, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/login/2fa/enroll", Handler: port.Gateway.LoginOTPEnroll, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/login/unlock", Handler: port.Gateway.LoginUnlock, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/register", Handler: port.Gateway.Register, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/refresh-token", Handler: port.Gateway.RefreshToken, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/verify", Handler: port.Gateway.Verify, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/logout", Handler: port.Gateway.Logout},
+		{Method: http.MethodGet, Pattern: "/v1/profile", Handler: port.Gateway.Profile},
+		{Method: http.MethodPut, Pattern: "/v1/profile", Handler: port.Gateway.ProfileUpdate},
+		{Method: http.MethodPut, Pattern: "/v1/profile/change-password", Handler: port.Gateway.ProfileChangePassword},
+		{Method: http.MethodGet, Pattern: "/v1/profile/2fa", Handler: port.Gateway.ProfileOTP},
+		{Method: http.MethodPost, Pattern: "/v1/profile/2fa", Handler: port.Gateway.ProfileOTPEnroll},
+		{Method: http.MethodPost, Pattern: "/v1/profile/2fa/confirm", Handler: port.Gateway.ProfileOTPConfirm},
+		{Method: http.MethodPost, Pattern: "/v1/profile/2fa/recovery-codes", Handler: port.Gateway.ProfileOTPRegenerateRecoveryCodes},
+		{Method: http.MethodPost, Pattern: "/v1/profile/2fa/disable", Handler: port.Gateway.ProfileOTPDisable},
+		{Method: http.MethodGet, Pattern: "/v1/profile/sessions", Handler: port.Gateway.ProfileSessions},
+		{Method: http.MethodDelete, Pattern: "/v1/profile/sessions", Handler: port.Gateway.ProfileRevokeSessions},
+		{Method: http.MethodPost, Pattern: "/v1/forgot-password", Handler: port.Gateway.ForgotPassword, IsPublic: true},
+		{Method: http.MethodPost, Pattern: "/v1/password-reset", Handler: port.Gateway.PasswordReset, IsPublic: true},
+
+		// --- REGISTRY --- //
+		{Method: http.MethodGet, Pattern: "/v1/cpsrn/{cpsrn}", IsPublic: true, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.ComicSubmission.GetRegistryByCPSRN(w, r, router.Param(r, "cpsrn"))
+		}},
+
+		// --- SUBMISSIONS --- //
+		{Method: http.MethodGet, Pattern: "/v1/comic-submissions", Handler: port.ComicSubmission.List, Permission: permission.SubmissionRead, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/comic-submissions", Handler: port.ComicSubmission.Create, Permission: permission.SubmissionCreate, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/comic-submissions/import", Handler: port.ComicSubmission.Import, Permission: permission.SubmissionCreate, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/comic-submissions/export", Handler: port.ComicSubmission.Export, Permission: permission.SubmissionRead, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/comic-submissions/select-options", Handler: port.ComicSubmission.ListAsSelectOptionByFilter, Permission: permission.SubmissionRead, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/comic-submissions/operation/set-user", Handler: port.ComicSubmission.OperationSetUser, Permission: permission.SubmissionUpdate, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/comic-submissions/operation/create-comment", Handler: port.ComicSubmission.OperationCreateComment, Permission: permission.SubmissionRead, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/comic-submission/{id}", Handler: withID(port.ComicSubmission.GetByID), Permission: permission.SubmissionRead, AllowAPIKey: true},
+		{Method: http.MethodPut, Pattern: "/v1/comic-submission/{id}", Handler: withID(port.ComicSubmission.UpdateByID), Permission: permission.SubmissionUpdate, AllowAPIKey: true},
+		{Method: http.MethodDelete, Pattern: "/v1/comic-submission/{id}", Handler: withID(port.ComicSubmission.ArchiveByID), Permission: permission.SubmissionDelete},
+		{Method: http.MethodDelete, Pattern: "/v1/comic-submission/{id}/perma-delete", Handler: withID(port.ComicSubmission.DeleteByID), Permission: permission.SubmissionDelete},
+		{Method: http.MethodPost, Pattern: "/v1/comic-submission/{id}/regenerate-pdf", Handler: withID(port.ComicSubmission.RegeneratePDF), Permission: permission.SubmissionUpdate, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/comic-submission/{id}/sign-off", Handler: withID(port.ComicSubmission.SignOff), Permission: permission.SubmissionGrade},
+		{Method: http.MethodPost, Pattern: "/v1/comic-submission/{id}/transition", Handler: withID(port.ComicSubmission.Transition), Permission: permission.SubmissionRead},
+		{Method: http.MethodGet, Pattern: "/v1/comic-submission/{id}/grade-revisions", Handler: withID(port.ComicSubmission.ListGradeRevisions), Permission: permission.SubmissionRead, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/comic-submission/{id}/grade-revisions/{revision_id}/certificate", Permission: permission.SubmissionRead, AllowAPIKey: true, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.ComicSubmission.DownloadGradeRevisionCertificate(w, r, router.Param(r, "id"), router.Param(r, "revision_id"))
+		}},
+
+		// --- ORGANIZATION --- //
+		{Method: http.MethodGet, Pattern: "/v1/organizations", Handler: port.Organization.List, Permission: permission.OrganizationAll},
+		{Method: http.MethodPost, Pattern: "/v1/organizations", Handler: port.Organization.Create, Permission: permission.OrganizationManage},
+		{Method: http.MethodGet, Pattern: "/v1/organizations/export", Handler: port.Organization.Export, Permission: permission.OrganizationAll},
+		{Method: http.MethodGet, Pattern: "/v1/organizations/select-options", Handler: port.Organization.ListAsSelectOptionByFilter, Permission: permission.OrganizationAll},
+		{Method: http.MethodPost, Pattern: "/v1/organizations/operation/create-comment", Handler: port.Organization.OperationCreateComment, Permission: permission.OrganizationManage},
+		{Method: http.MethodGet, Pattern: "/v1/organization/{id}", Handler: withID(port.Organization.GetByID)},
+		{Method: http.MethodPut, Pattern: "/v1/organization/{id}", Handler: withID(port.Organization.UpdateByID), Permission: permission.OrganizationManage},
+		{Method: http.MethodDelete, Pattern: "/v1/organization/{id}", Handler: withID(port.Organization.DeleteByID), Permission: permission.OrganizationManage},
+		{Method: http.MethodGet, Pattern: "/v1/organization/{id}/api-keys", Handler: withID(port.APIKey.List), Permission: permission.OrganizationManage},
+		{Method: http.MethodPost, Pattern: "/v1/organization/{id}/api-keys", Handler: withID(port.APIKey.Create), Permission: permission.OrganizationManage},
+		{Method: http.MethodDelete, Pattern: "/v1/organization/{id}/api-keys/{api_key_id}", Permission: permission.OrganizationManage, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.APIKey.RevokeByID(w, r, router.Param(r, "id"), router.Param(r, "api_key_id"))
+		}},
+		{Method: http.MethodGet, Pattern: "/v1/organization/{id}/webhooks", Handler: withID(port.Webhook.List), Permission: permission.OrganizationManage},
+		{Method: http.MethodPost, Pattern: "/v1/organization/{id}/webhooks", Handler: withID(port.Webhook.Create), Permission: permission.OrganizationManage},
+		{Method: http.MethodGet, Pattern: "/v1/organization/{id}/webhooks/{webhook_id}", Permission: permission.OrganizationManage, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.Webhook.GetByID(w, r, router.Param(r, "id"), router.Param(r, "webhook_id"))
+		}},
+		{Method: http.MethodPut, Pattern: "/v1/organization/{id}/webhooks/{webhook_id}", Permission: permission.OrganizationManage, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.Webhook.UpdateByID(w, r, router.Param(r, "id"), router.Param(r, "webhook_id"))
+		}},
+		{Method: http.MethodDelete, Pattern: "/v1/organization/{id}/webhooks/{webhook_id}", Permission: permission.OrganizationManage, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.Webhook.DeleteByID(w, r, router.Param(r, "id"), router.Param(r, "webhook_id"))
+		}},
+		{Method: http.MethodPost, Pattern: "/v1/organization/{id}/webhooks/{webhook_id}/test", Permission: permission.OrganizationManage, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.Webhook.SendTestEvent(w, r, router.Param(r, "id"), router.Param(r, "webhook_id"))
+		}},
+		{Method: http.MethodGet, Pattern: "/v1/organization/{id}/webhooks/{webhook_id}/deliveries", Permission: permission.OrganizationManage, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.Webhook.ListDeliveries(w, r, router.Param(r, "id"), router.Param(r, "webhook_id"))
+		}},
+		{Method: http.MethodPost, Pattern: "/v1/organization/{id}/webhooks/{webhook_id}/deliveries/{delivery_id}/replay", Permission: permission.OrganizationManage, Handler: func(w http.ResponseWriter, r *http.Request) {
+			port.Webhook.ReplayDelivery(w, r, router.Param(r, "id"), router.Param(r, "webhook_id"), router.Param(r, "delivery_id"))
+		}},
+
+		// --- CUSTOMERS --- //
+		{Method: http.MethodGet, Pattern: "/v1/customers", Handler: port.Customer.List, Permission: permission.CustomerManage, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/customers", Handler: port.Customer.Create, Permission: permission.CustomerManage, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/customers/export", Handler: port.Customer.Export, Permission: permission.CustomerManage, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/customers/operation/create-comment", Handler: port.Customer.OperationCreateComment, Permission: permission.CustomerManage, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/customer/{id}", Handler: withID(port.Customer.GetByID), Permission: permission.CustomerManage, AllowAPIKey: true},
+		{Method: http.MethodPut, Pattern: "/v1/customer/{id}", Handler: withID(port.Customer.UpdateByID), Permission: permission.CustomerManage, AllowAPIKey: true},
+		{Method: http.MethodDelete, Pattern: "/v1/customer/{id}", Handler: withID(port.Customer.DeleteByID), Permission: permission.CustomerManage, AllowAPIKey: true},
+
+		// --- USERS --- //
+		{Method: http.MethodGet, Pattern: "/v1/users", Handler: port.User.List, Permission: permission.UserManage},
+		{Method: http.MethodPost, Pattern: "/v1/users", Handler: port.User.Create, Permission: permission.UserManage},
+		{Method: http.MethodGet, Pattern: "/v1/users/select-options", Handler: port.User.ListAsSelectOptions, Permission: permission.UserManage},
+		{Method: http.MethodPost, Pattern: "/v1/users/operation/create-comment", Handler: port.User.OperationCreateComment, Permission: permission.UserManage},
+		{Method: http.MethodGet, Pattern: "/v1/user/{id}", Handler: withID(port.User.GetByID), Permission: permission.UserManage},
+		{Method: http.MethodPut, Pattern: "/v1/user/{id}", Handler: withID(port.User.UpdateByID), Permission: permission.UserManage},
+		{Method: http.MethodDelete, Pattern: "/v1/user/{id}", Handler: withID(port.User.DeleteByID), Permission: permission.UserManage},
+		{Method: http.MethodDelete, Pattern: "/v1/user/{id}/sessions", Handler: withID(port.User.RevokeSessionsByID), Permission: permission.UserManage},
+		{Method: http.MethodPost, Pattern: "/v1/user/{id}/unlock", Handler: withID(port.Gateway.UnlockLoginByUserID), Permission: permission.UserManage},
+
+		// --- AUDIT TRAIL --- //
+		{Method: http.MethodGet, Pattern: "/v1/audit-events", Handler: port.Audit.List, Permission: permission.UserManage},
+
+		// --- ATTACHMENTS --- //
+		{Method: http.MethodGet, Pattern: "/v1/attachments", Handler: port.Attachment.List, Permission: permission.AttachmentManage, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/attachments", Handler: port.Attachment.Create, Permission: permission.AttachmentManage, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/attachment/{id}", Handler: withID(port.Attachment.GetByID), Permission: permission.AttachmentManage, AllowAPIKey: true},
+		{Method: http.MethodPut, Pattern: "/v1/attachment/{id}", Handler: withID(port.Attachment.UpdateByID), Permission: permission.AttachmentManage, AllowAPIKey: true},
+		{Method: http.MethodDelete, Pattern: "/v1/attachment/{id}", Handler: withID(port.Attachment.DeleteByID), Permission: permission.AttachmentManage, AllowAPIKey: true},
+
+		// --- ORDERS --- //
+		{Method: http.MethodGet, Pattern: "/v1/orders", Handler: port.Order.List, Permission: permission.OrderManage, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/orders", Handler: port.Order.Create, Permission: permission.OrderManage, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/order/{id}", Handler: withID(port.Order.GetByID), Permission: permission.OrderManage, AllowAPIKey: true},
+		{Method: http.MethodPost, Pattern: "/v1/order/{id}/regenerate-packing-slip", Handler: withID(port.Order.RegeneratePackingSlip), Permission: permission.OrderManage, AllowAPIKey: true},
+
+		// --- REPORTS --- //
+		{Method: http.MethodGet, Pattern: "/v1/reports/submission-volume", Handler: port.Report.SubmissionVolume, Permission: permission.ReportRead, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/reports/grade-distribution", Handler: port.Report.GradeDistribution, Permission: permission.ReportRead, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/reports/turnaround", Handler: port.Report.Turnaround, Permission: permission.ReportRead, AllowAPIKey: true},
+		{Method: http.MethodGet, Pattern: "/v1/reports/top-retailers", Handler: port.Report.TopRetailers, Permission: permission.ReportRead, AllowAPIKey: true},
+	}
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/LuchaComics/cps-backend/app/permission"
+	u_d "github.com/LuchaComics/cps-backend/app/user/datastore"
+	"github.com/LuchaComics/cps-backend/config/constants"
+	"github.com/LuchaComics/cps-backend/inputport/http/router"
+)
+
+func TestRoutesAllowAPIKeysOnlyWithinTheirScopes(t *testing.T) {
+	for _, route := range (&httpInputPort{}).routes() {
+		if route.AllowAPIKey && (route.IsPublic || !permission.IsAPIKeyScope(route.Permission)) {
+			t.Errorf("route %v %v allows api keys without an api key scope", route.Method, route.Pattern)
+		}
+	}
+}
+
+func TestRoutesForbidReadOnlyAPIKeys(t *testing.T) {
+	rt := router.New((&httpInputPort{}).routes())
+
+	// Moving a submission along needs more than reading it; the handlers
+	// are never reached so the port needs none.
+	for _, path := range []string{
+		"/v1/comic-submission/64a1/transition",
+		"/v1/profile/2fa/disable",
+		"/v1/logout",
+	} {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		ctx := context.WithValue(r.Context(), constants.SessionUserRole, int8(u_d.UserRoleRetailer))
+		ctx = context.WithValue(ctx, constants.SessionAPIKeyID, primitive.NewObjectID())
+		ctx = context.WithValue(ctx, constants.SessionAPIKeyScopes, []permission.Permission{permission.SubmissionRead})
+		w := httptest.NewRecorder()
+		rt.Resolve(rt.Dispatch)(w, r.WithContext(ctx))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("POST %v: expected %v but got %v", path, http.StatusForbidden, w.Code)
+		}
+	}
+}
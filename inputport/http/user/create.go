@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	usr_c "github.com/LuchaComics/cps-backend/app/user/controller"
 	usr_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
@@ -41,6 +42,8 @@ func ValidateCreateRequest(dirtyData *usr_c.UserCreateRequestIDO) error {
 	}
 	if dirtyData.Role == 0 {
 		e["role"] = "missing value"
+	} else if !permission.IsRole(dirtyData.Role) {
+		e["role"] = "invalid value"
 	}
 	if dirtyData.Status == 0 {
 		e["status"] = "missing value"
@@ -8,6 +8,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
+	"github.com/LuchaComics/cps-backend/app/permission"
 	usr_c "github.com/LuchaComics/cps-backend/app/user/controller"
 	usr_s "github.com/LuchaComics/cps-backend/app/user/datastore"
 	"github.com/LuchaComics/cps-backend/utils/httperror"
@@ -43,6 +44,12 @@ func ValidateUpdateRequest(dirtyData *usr_c.UserUpdateRequestIDO) error {
 	}
 	if dirtyData.Role == 0 {
 		e["role"] = "missing value"
+	} else if !permission.IsRole(dirtyData.Role) {
+		e["role"] = "invalid value"
+	}
+	// The status is optional so older clients keep the current one.
+	if dirtyData.Status != 0 && dirtyData.Status != usr_s.UserStatusActive && dirtyData.Status != usr_s.UserStatusArchived {
+		e["status"] = "invalid value"
 	}
 	if dirtyData.FirstName == "" {
 		e["first_name"] = "missing value"
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	webhook_c "github.com/LuchaComics/cps-backend/app/webhook/controller"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func UnmarshalCreateRequest(ctx context.Context, r *http.Request) (*webhook_c.WebhookCreateRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData webhook_c.WebhookCreateRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		log.Println(err)
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	if err := ValidateCreateRequest(&requestData); err != nil {
+		return nil, err
+	}
+	return &requestData, nil
+}
+
+func ValidateCreateRequest(dirtyData *webhook_c.WebhookCreateRequestIDO) error {
+	e := make(map[string]string)
+
+	validateURL(e, dirtyData.URL)
+	validateEvents(e, dirtyData.Events)
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func validateURL(e map[string]string, url string) {
+	if url == "" {
+		e["url"] = "missing value"
+	} else if !webhook_c.IsValidURL(url) {
+		e["url"] = "invalid value"
+	}
+}
+
+func validateEvents(e map[string]string, events []string) {
+	if len(events) == 0 {
+		e["events"] = "missing value"
+	}
+	for _, event := range events {
+		if !webhook_s.IsEvent(event) {
+			e["events"] = "invalid value"
+		}
+	}
+}
+
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request, organizationID string) {
+	ctx := r.Context()
+
+	orgID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	data, err := UnmarshalCreateRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	data.OrganizationID = orgID
+
+	res, err := h.Controller.Create(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	MarshalCreateResponse(res, w)
+}
+
+func MarshalCreateResponse(res *webhook_c.WebhookCreateResponseIDO, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
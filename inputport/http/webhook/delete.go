@@ -0,0 +1,23 @@
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) DeleteByID(w http.ResponseWriter, r *http.Request, organizationID string, id string) {
+	ctx := r.Context()
+
+	orgID, objectID, err := parseIDs(organizationID, id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	if err := h.Controller.DeleteByID(ctx, orgID, objectID); err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
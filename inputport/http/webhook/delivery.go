@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) SendTestEvent(w http.ResponseWriter, r *http.Request, organizationID string, id string) {
+	ctx := r.Context()
+
+	orgID, objectID, err := parseIDs(organizationID, id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	d, err := h.Controller.SendTestEvent(ctx, orgID, objectID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	MarshalDeliveryResponse(d, w)
+}
+
+func (h *Handler) ListDeliveries(w http.ResponseWriter, r *http.Request, organizationID string, id string) {
+	ctx := r.Context()
+
+	orgID, objectID, err := parseIDs(organizationID, id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	f := &webhook_s.DeliveryListFilter{
+		PageSize:       25,
+		SubscriptionID: objectID,
+	}
+
+	// Here is where you extract url parameters.
+	query := r.URL.Query()
+	cursor := query.Get("cursor")
+	if cursor != "" {
+		cursor, err := primitive.ObjectIDFromHex(cursor)
+		if err != nil {
+			httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("cursor", "invalid value"))
+			return
+		}
+		f.Cursor = cursor
+	}
+
+	pageSize := query.Get("page_size")
+	if pageSize != "" {
+		pageSize, _ := strconv.ParseInt(pageSize, 10, 64)
+		if pageSize == 0 || pageSize > 250 {
+			pageSize = 250
+		}
+		f.PageSize = pageSize
+	}
+
+	statusStr := query.Get("status")
+	if statusStr != "" {
+		status, _ := strconv.ParseInt(statusStr, 10, 64)
+		f.Status = int8(status)
+	}
+
+	m, err := h.Controller.ListDeliveriesByFilter(ctx, orgID, f)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&m); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handler) ReplayDelivery(w http.ResponseWriter, r *http.Request, organizationID string, id string, deliveryID string) {
+	ctx := r.Context()
+
+	orgID, objectID, err := parseIDs(organizationID, id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	deliveryObjectID, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("delivery_id", "invalid value"))
+		return
+	}
+
+	d, err := h.Controller.ReplayDeliveryByID(ctx, orgID, objectID, deliveryObjectID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	MarshalDeliveryResponse(d, w)
+}
+
+func MarshalDeliveryResponse(res *webhook_s.Delivery, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request, organizationID string, id string) {
+	ctx := r.Context()
+
+	orgID, objectID, err := parseIDs(organizationID, id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	m, err := h.Controller.GetByID(ctx, orgID, objectID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalDetailResponse(m, w)
+}
+
+func MarshalDetailResponse(res *webhook_s.Subscription, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&res); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
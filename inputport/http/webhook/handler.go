@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	webhook_c "github.com/LuchaComics/cps-backend/app/webhook/controller"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+// Handler Creates http request handler
+type Handler struct {
+	Controller webhook_c.WebhookController
+}
+
+// NewHandler Constructor
+func NewHandler(c webhook_c.WebhookController) *Handler {
+	return &Handler{
+		Controller: c,
+	}
+}
+
+// parseIDs converts the organization and webhook ids of the url.
+func parseIDs(organizationID string, id string) (primitive.ObjectID, primitive.ObjectID, error) {
+	orgID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		return orgID, primitive.NilObjectID, httperror.NewForBadRequestWithSingleField("id", "invalid value")
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return orgID, objectID, httperror.NewForBadRequestWithSingleField("webhook_id", "invalid value")
+	}
+	return orgID, objectID, nil
+}
@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+type WebhookListResponse struct {
+	Results []*webhook_s.Subscription `json:"results"`
+}
+
+func (h *Handler) List(w http.ResponseWriter, r *http.Request, organizationID string) {
+	ctx := r.Context()
+
+	orgID, err := primitive.ObjectIDFromHex(organizationID)
+	if err != nil {
+		httperror.ResponseError(w, httperror.NewForBadRequestWithSingleField("id", "invalid value"))
+		return
+	}
+
+	m, err := h.Controller.ListByOrganizationID(ctx, orgID)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalListResponse(m, w)
+}
+
+func MarshalListResponse(res []*webhook_s.Subscription, w http.ResponseWriter) {
+	if err := json.NewEncoder(w).Encode(&WebhookListResponse{Results: res}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	webhook_c "github.com/LuchaComics/cps-backend/app/webhook/controller"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+func UnmarshalUpdateRequest(ctx context.Context, r *http.Request) (*webhook_c.WebhookUpdateRequestIDO, error) {
+	// Initialize our array which will store all the results from the remote server.
+	var requestData webhook_c.WebhookUpdateRequestIDO
+
+	defer r.Body.Close()
+
+	// Read the JSON string and convert it into our golang stuct else we need
+	// to send a `400 Bad Request` errror message back to the client,
+	err := json.NewDecoder(r.Body).Decode(&requestData) // [1]
+	if err != nil {
+		log.Println(err)
+		return nil, httperror.NewForSingleField(http.StatusBadRequest, "non_field_error", "payload structure is wrong")
+	}
+
+	// Perform our validation and return validation error on any issues detected.
+	if err := ValidateUpdateRequest(&requestData); err != nil {
+		return nil, err
+	}
+	return &requestData, nil
+}
+
+func ValidateUpdateRequest(dirtyData *webhook_c.WebhookUpdateRequestIDO) error {
+	e := make(map[string]string)
+
+	validateURL(e, dirtyData.URL)
+	validateEvents(e, dirtyData.Events)
+	if dirtyData.Status == 0 {
+		e["status"] = "missing value"
+	} else if dirtyData.Status != webhook_s.SubscriptionStatusActive && dirtyData.Status != webhook_s.SubscriptionStatusDisabled {
+		e["status"] = "invalid value"
+	}
+
+	if len(e) != 0 {
+		return httperror.NewForBadRequest(&e)
+	}
+	return nil
+}
+
+func (h *Handler) UpdateByID(w http.ResponseWriter, r *http.Request, organizationID string, id string) {
+	ctx := r.Context()
+
+	orgID, objectID, err := parseIDs(organizationID, id)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	data, err := UnmarshalUpdateRequest(ctx, r)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+	data.OrganizationID = orgID
+	data.ID = objectID
+
+	m, err := h.Controller.UpdateByID(ctx, data)
+	if err != nil {
+		httperror.ResponseError(w, err)
+		return
+	}
+
+	MarshalDetailResponse(m, w)
+}
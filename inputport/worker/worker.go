@@ -0,0 +1,189 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	comicsub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	order_c "github.com/LuchaComics/cps-backend/app/order/controller"
+	webhook_c "github.com/LuchaComics/cps-backend/app/webhook/controller"
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+const (
+	// pollInterval is how long an idle worker waits before checking for jobs.
+	pollInterval = 2 * time.Second
+
+	// leaseDuration is how long a job is reserved for a worker; if the worker
+	// dies the job will be picked up again by another worker afterwards.
+	leaseDuration = 5 * time.Minute
+
+	// backoffBase and backoffMax control the exponential retry delay.
+	backoffBase = 30 * time.Second
+	backoffMax  = 1 * time.Hour
+)
+
+// JobHandlerFunc processes a single job. Returning an error will schedule
+// the job to be retried until it runs out of attempts.
+type JobHandlerFunc func(ctx context.Context, j *job_s.Job) error
+
+type InputPortServer interface {
+	Run()
+	Shutdown()
+}
+
+type workerInputPort struct {
+	Config    *config.Conf
+	Logger    *slog.Logger
+	JobStorer job_s.JobStorer
+	Handlers  map[string]JobHandlerFunc
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func NewInputPort(
+	configp *config.Conf,
+	loggerp *slog.Logger,
+	js job_s.JobStorer,
+	comicsub comicsub_c.ComicSubmissionController,
+	order order_c.OrderController,
+	webhook webhook_c.WebhookController,
+) InputPortServer {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &workerInputPort{
+		Config:    configp,
+		Logger:    loggerp,
+		JobStorer: js,
+		Handlers: map[string]JobHandlerFunc{
+			job_s.TypeGenerateComicSubmissionPDF:       comicsub.GenerateCertificatePDF,
+			job_s.TypeSendComicSubmissionCreatedEmails: comicsub.SendCreatedEmails,
+			job_s.TypeSendComicSubmissionStatusEmails:  comicsub.SendStatusChangedEmails,
+			job_s.TypeGenerateOrderPackingSlip:         order.GeneratePackingSlipPDF,
+			job_s.TypeDeliverWebhook:                   webhook.Deliver,
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	return p
+}
+
+func (port *workerInputPort) Run() {
+	count := port.Config.JobQueue.WorkerCount
+	if count < 1 {
+		count = 1
+	}
+	port.Logger.Info("job workers running", slog.Int("count", count))
+	for i := 0; i < count; i++ {
+		port.wg.Add(1)
+		go port.loop(i)
+	}
+	port.wg.Wait()
+}
+
+func (port *workerInputPort) Shutdown() {
+	port.cancel()
+	port.wg.Wait()
+	port.Logger.Info("job workers shutdown")
+}
+
+func (port *workerInputPort) loop(workerID int) {
+	defer port.wg.Done()
+	for {
+		// Keep processing while there is work before going to sleep.
+		for port.ctx.Err() == nil && port.processNext(workerID) {
+		}
+
+		select {
+		case <-port.ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// processNext claims and processes one job; returns false if no job was due.
+func (port *workerInputPort) processNext(workerID int) bool {
+	j, err := port.JobStorer.ClaimNext(port.ctx, leaseDuration)
+	if err != nil {
+		port.Logger.Error("claim next job error", slog.Any("error", err))
+		return false
+	}
+	if j == nil {
+		return false
+	}
+
+	port.Logger.Debug("job started",
+		slog.Int("worker", workerID),
+		slog.Any("job_id", j.ID),
+		slog.String("type", j.Type),
+		slog.Int("attempt", j.Attempts))
+
+	// Do not let the shutdown signal abort a job mid-way; the lease protects
+	// us if the process gets killed regardless.
+	ctx, cancel := context.WithTimeout(context.Background(), leaseDuration)
+	defer cancel()
+
+	err = port.handle(ctx, j)
+
+	j.ModifiedAt = time.Now()
+	j.LockedUntil = time.Time{}
+	switch {
+	case err == nil:
+		j.Status = job_s.StatusCompleted
+		j.LastError = ""
+		j.CompletedAt = time.Now()
+		port.Logger.Debug("job completed", slog.Any("job_id", j.ID), slog.String("type", j.Type))
+	case j.Attempts >= j.MaxAttempts:
+		j.Status = job_s.StatusFailed
+		j.LastError = err.Error()
+		port.Logger.Error("job failed", slog.Any("job_id", j.ID), slog.String("type", j.Type), slog.Any("error", err))
+	default:
+		j.Status = job_s.StatusPending
+		j.LastError = err.Error()
+		j.RunAt = time.Now().Add(backoff(j.Attempts))
+		port.Logger.Warn("job will be retried",
+			slog.Any("job_id", j.ID),
+			slog.String("type", j.Type),
+			slog.Time("run_at", j.RunAt),
+			slog.Any("error", err))
+	}
+
+	if err := port.JobStorer.UpdateByID(ctx, j); err != nil {
+		port.Logger.Error("database update job error", slog.Any("error", err))
+	}
+	return true
+}
+
+func (port *workerInputPort) handle(ctx context.Context, j *job_s.Job) (err error) {
+	// Defensive code: a single bad job must never bring down the server.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	handler, ok := port.Handlers[j.Type]
+	if !ok {
+		j.Attempts = j.MaxAttempts // No point in retrying.
+		return fmt.Errorf("unsupported job type: %v", j.Type)
+	}
+	return handler(ctx, j)
+}
+
+// backoff returns the exponential delay before the next attempt.
+func backoff(attempts int) time.Duration {
+	d := backoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= backoffMax {
+			return backoffMax
+		}
+	}
+	return d
+}
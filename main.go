@@ -8,21 +8,25 @@ import (
 	"golang.org/x/exp/slog"
 
 	"github.com/LuchaComics/cps-backend/inputport/http"
+	"github.com/LuchaComics/cps-backend/inputport/worker"
 )
 
 type Application struct {
 	Logger     *slog.Logger
 	HttpServer http.InputPortServer
+	Worker     worker.InputPortServer
 }
 
 // NewApplication is application construction function which is automatically called by `Google Wire` dependency injection library.
 func NewApplication(
 	loggerp *slog.Logger,
 	httpServer http.InputPortServer,
+	workerServer worker.InputPortServer,
 ) Application {
 	return Application{
 		Logger:     loggerp,
 		HttpServer: httpServer,
+		Worker:     workerServer,
 	}
 }
 
@@ -33,6 +37,9 @@ func (a Application) Execute() {
 	// Run in background the HTTP server.
 	go a.HttpServer.Run()
 
+	// Run in background the job workers which generate our PDFs, etc.
+	go a.Worker.Run()
+
 	a.Logger.Info("Application started")
 
 	// Run the main loop blocking code while other input ports run in background.
@@ -43,6 +50,7 @@ func (a Application) Execute() {
 
 func (a Application) Shutdown() {
 	a.HttpServer.Shutdown()
+	a.Worker.Shutdown()
 	a.Logger.Info("Application shutdown")
 }
 
@@ -1,10 +1,38 @@
 package cpsrn
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMalformed is returned when the `CPS Registry Number` does not follow
+	// the `A-B-ROLE-SEQUENCE[-CHECK]` format.
+	ErrMalformed = errors.New("malformed cps registry number")
+
+	// ErrInvalidCheckDigit is returned when the `CPS Registry Number` is well
+	// formed but the check digit does not match, ex: it was mistyped.
+	ErrInvalidCheckDigit = errors.New("cps registry number check digit does not match")
+)
+
+// Number represents the sections which make up a `CPS Registry Number`.
+type Number struct {
+	SectionA      int64
+	SectionB      int64
+	RoleID        int8
+	SectionC      int64
+	Sequence      int64 // Zero-based sequence that was used to generate the `SectionC`.
+	CheckDigit    int
+	HasCheckDigit bool // Legacy numbers were issued without a check digit.
+}
 
 // Provider provides an interface for abstracting `CPS Registry Number`.
 type Provider interface {
 	GenerateNumber(roleID int8, currentTotalSubmissionsCount int64) string
+	Parse(cpsrn string) (*Number, error)
+	Validate(cpsrn string) error
 }
 
 type cpsrnProvider struct {
@@ -23,7 +51,91 @@ func NewProvider() Provider {
 }
 
 // Generates the unique `CPS Registry Number` required for tracking submissions.
+// The last section is a Luhn check digit computed over all the other digits so
+// typos can be detected without going to the database.
 func (p cpsrnProvider) GenerateNumber(roleID int8, currentTotalSubmissionsCount int64) string {
 	newSectionC := p.sectionC + currentTotalSubmissionsCount
-	return fmt.Sprintf("%d-%d-%d-%d", p.sectionA, p.sectionB, roleID, newSectionC)
+	base := fmt.Sprintf("%d-%d-%d-%d", p.sectionA, p.sectionB, roleID, newSectionC)
+	return fmt.Sprintf("%s-%d", base, luhnCheckDigit(base))
+}
+
+// Parse breaks the `CPS Registry Number` into its sections. Numbers issued
+// before check digits were introduced (four sections) are still accepted.
+func (p cpsrnProvider) Parse(cpsrn string) (*Number, error) {
+	cpsrn = strings.TrimSpace(cpsrn)
+	parts := strings.Split(cpsrn, "-")
+	if len(parts) != 4 && len(parts) != 5 {
+		return nil, ErrMalformed
+	}
+
+	values := make([]int64, len(parts))
+	for i, part := range parts {
+		if part == "" || strings.TrimLeft(part, "0123456789") != "" {
+			return nil, ErrMalformed
+		}
+		v, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		values[i] = v
+	}
+
+	if values[0] != p.sectionA || values[1] != p.sectionB {
+		return nil, ErrMalformed
+	}
+	if values[2] < 1 || values[2] > 127 {
+		return nil, ErrMalformed
+	}
+	if values[3] < p.sectionC {
+		return nil, ErrMalformed
+	}
+
+	n := &Number{
+		SectionA: values[0],
+		SectionB: values[1],
+		RoleID:   int8(values[2]),
+		SectionC: values[3],
+		Sequence: values[3] - p.sectionC,
+	}
+
+	if len(parts) == 5 {
+		if len(parts[4]) != 1 {
+			return nil, ErrMalformed
+		}
+		n.CheckDigit = int(values[4])
+		n.HasCheckDigit = true
+		if luhnCheckDigit(strings.Join(parts[:4], "-")) != n.CheckDigit {
+			return nil, ErrInvalidCheckDigit
+		}
+	}
+	return n, nil
+}
+
+// Validate returns an error if the `CPS Registry Number` is malformed or its
+// check digit does not match.
+func (p cpsrnProvider) Validate(cpsrn string) error {
+	_, err := p.Parse(cpsrn)
+	return err
+}
+
+// luhnCheckDigit computes the Luhn (mod 10) check digit over every digit in
+// the string; all non-digit characters are ignored.
+func luhnCheckDigit(s string) int {
+	sum := 0
+	double := true // The check digit will be appended to the right.
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
 }
@@ -0,0 +1,79 @@
+package cpsrn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerateNumber(t *testing.T) {
+	p := NewProvider()
+
+	actual := p.GenerateNumber(1, 0)
+	expected := "788346-26649-1-1001-2"
+	if actual != expected {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestParse(t *testing.T) {
+	p := NewProvider()
+
+	n, err := p.Parse(p.GenerateNumber(2, 41))
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if n.RoleID != 2 {
+		t.Errorf("expected role 2 but got %v", n.RoleID)
+	}
+	if n.Sequence != 41 {
+		t.Errorf("expected sequence 41 but got %v", n.Sequence)
+	}
+	if !n.HasCheckDigit {
+		t.Error("expected check digit to exist")
+	}
+
+	// Numbers issued before check digits were introduced must still parse.
+	n, err = p.Parse("788346-26649-1-1042")
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if n.HasCheckDigit {
+		t.Error("expected check digit to not exist")
+	}
+	if n.Sequence != 41 {
+		t.Errorf("expected sequence 41 but got %v", n.Sequence)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	p := NewProvider()
+	valid := p.GenerateNumber(1, 1234)
+
+	if err := p.Validate(valid); err != nil {
+		t.Errorf("received an error %v", err)
+	}
+
+	// Mistype a single digit in the sequence.
+	mistyped := []byte(valid)
+	mistyped[len(mistyped)-3] = '0' + (mistyped[len(mistyped)-3]-'0'+1)%10
+	if err := p.Validate(string(mistyped)); !errors.Is(err, ErrInvalidCheckDigit) {
+		t.Errorf("expected invalid check digit error but got %v", err)
+	}
+
+	malformed := []string{
+		"",
+		"hello",
+		"788346-26649-1",
+		"788346-26649-1-abc",
+		"788346-26649-1-1001-22",
+		"111111-26649-1-1001",
+		"788346-26649-1-0999",
+		"788346-26649-1-+1001",
+		"788346-26649-1-1001-7-1",
+	}
+	for _, s := range malformed {
+		if err := p.Validate(s); !errors.Is(err, ErrMalformed) {
+			t.Errorf("expected malformed error for %q but got %v", s, err)
+		}
+	}
+}
@@ -1,6 +1,16 @@
 package jwt
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/LuchaComics/cps-backend/config"
@@ -10,31 +20,183 @@ import (
 // Provider provides interface for abstracting JWT generation.
 type Provider interface {
 	GenerateJWTToken(uuid string, ad time.Duration) (string, time.Time, error)
-	GenerateJWTTokenPair(uuid string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error)
+	GenerateJWTTokenPair(uuid string, refreshTokenID string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error)
 	ProcessJWTToken(reqToken string) (string, error)
+	ProcessJWTRefreshToken(reqToken string) (string, string, error)
+
+	// JWKS returns the public keys which verify our tokens, including the
+	// keys scheduled to sign in the future, so other services can verify our
+	// tokens without sharing a secret.
+	JWKS() *JWKS
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517).
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// JWK is the public key of an RSA (RFC 7518) or Ed25519 (RFC 8037) key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// Key is a private key which starts signing our tokens on the date it
+// activates and keeps verifying them until it is removed.
+type Key struct {
+	*jwt_utils.Key
+	ActivatesAt time.Time
 }
 
 type jwtProvider struct {
-	hmacSecret []byte
+	hmacKey    *jwt_utils.Key
+	acceptHMAC bool
+	keys       []*Key // Sorted by the date they activate.
+	jwks       *JWKS
+	now        func() time.Time
 }
 
-// NewProvider Constructor that returns the JWT generator.
+// NewProvider Constructor that returns the JWT generator. Tokens are signed
+// by the last activated key of the keys directory or, if none is activated,
+// by the HMAC secret. To rotate, add a key which activates in the future so
+// it is published before it signs, and remove the old key once the refresh
+// tokens it signed have expired.
 func NewProvider(cfg *config.Conf) Provider {
-	return jwtProvider{
-		hmacSecret: cfg.AppServer.HMACSecret,
+	keys, err := LoadKeys(cfg.JWT.KeysDirectoryPath)
+	if err != nil {
+		log.Fatalf("jwt keys: %v", err)
+	}
+	p := newProvider(cfg.AppServer.HMACSecret, cfg.JWT.AcceptHMAC, keys, time.Now)
+	if !p.acceptHMAC && p.signingKey() == p.hmacKey {
+		log.Fatal("jwt keys: no key is activated and HMAC tokens are not accepted")
 	}
+	return p
+}
+
+func newProvider(hmacSecret []byte, acceptHMAC bool, keys []*Key, now func() time.Time) *jwtProvider {
+	sorted := make([]*Key, len(keys))
+	copy(sorted, keys)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ActivatesAt.Before(sorted[j].ActivatesAt)
+	})
+	jwks := &JWKS{Keys: make([]*JWK, 0, len(sorted))}
+	for _, k := range sorted {
+		jwks.Keys = append(jwks.Keys, newJWK(k.Key))
+	}
+	return &jwtProvider{
+		hmacKey:    jwt_utils.NewHMACKey(hmacSecret),
+		acceptHMAC: acceptHMAC,
+		keys:       sorted,
+		jwks:       jwks,
+		now:        now,
+	}
+}
+
+// LoadKeys reads the `<kid>.pem` private keys of the directory. The `kid`
+// starts with the date the key activates, ex: "2024-01-01-rs256.pem".
+func LoadKeys(dir string) ([]*Key, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*Key
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+		if len(kid) < len(time.DateOnly) {
+			return nil, fmt.Errorf("%v: expected the key id to start with the date it activates", entry.Name())
+		}
+		activatesAt, err := time.Parse(time.DateOnly, kid[:len(time.DateOnly)])
+		if err != nil {
+			return nil, fmt.Errorf("%v: expected the key id to start with the date it activates: %v", entry.Name(), err)
+		}
+		b, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		key, err := jwt_utils.ParsePrivateKey(kid, b)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", entry.Name(), err)
+		}
+		keys = append(keys, &Key{Key: key, ActivatesAt: activatesAt})
+	}
+	return keys, nil
+}
+
+func newJWK(key *jwt_utils.Key) *JWK {
+	jwk := &JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+	switch pk := key.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pk.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pk.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pk)
+	}
+	return jwk
+}
+
+// signingKey returns the last activated key or the HMAC secret if none is.
+func (p *jwtProvider) signingKey() *jwt_utils.Key {
+	now := p.now()
+	key := p.hmacKey
+	for _, k := range p.keys {
+		if !k.ActivatesAt.After(now) {
+			key = k.Key
+		}
+	}
+	return key
+}
+
+// verificationKey returns the key of the `kid`; tokens without one were
+// signed by the HMAC secret and are accepted during the migration.
+func (p *jwtProvider) verificationKey(kid string) *jwt_utils.Key {
+	if kid == "" {
+		if p.acceptHMAC {
+			return p.hmacKey
+		}
+		return nil
+	}
+	for _, k := range p.keys {
+		if k.ID == kid {
+			return k.Key
+		}
+	}
+	return nil
 }
 
 // GenerateJWTToken generates a single JWT token.
-func (p jwtProvider) GenerateJWTToken(uuid string, ad time.Duration) (string, time.Time, error) {
-	return jwt_utils.GenerateJWTToken(p.hmacSecret, uuid, ad)
+func (p *jwtProvider) GenerateJWTToken(uuid string, ad time.Duration) (string, time.Time, error) {
+	return jwt_utils.GenerateJWTTokenWithKey(p.signingKey(), uuid, ad)
+}
+
+// GenerateJWTTokenPair Generate the `access token` and `refresh token` for the signing key.
+func (p *jwtProvider) GenerateJWTTokenPair(uuid string, refreshTokenID string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error) {
+	return jwt_utils.GenerateJWTTokenPairWithKey(p.signingKey(), uuid, refreshTokenID, ad, rd)
+}
+
+func (p *jwtProvider) ProcessJWTToken(reqToken string) (string, error) {
+	return jwt_utils.ProcessJWTTokenWithKeys(p.verificationKey, reqToken)
 }
 
-// GenerateJWTTokenPair Generate the `access token` and `refresh token` for the secret key.
-func (p jwtProvider) GenerateJWTTokenPair(uuid string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error) {
-	return jwt_utils.GenerateJWTTokenPair(p.hmacSecret, uuid, ad, rd)
+// ProcessJWTRefreshToken returns the `uuid` and the id of the refresh token.
+func (p *jwtProvider) ProcessJWTRefreshToken(reqToken string) (string, string, error) {
+	return jwt_utils.ProcessJWTRefreshTokenWithKeys(p.verificationKey, reqToken)
 }
 
-func (p jwtProvider) ProcessJWTToken(reqToken string) (string, error) {
-	return jwt_utils.ProcessJWTToken(p.hmacSecret, reqToken)
+func (p *jwtProvider) JWKS() *JWKS {
+	return p.jwks
 }
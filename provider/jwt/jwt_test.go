@@ -0,0 +1,168 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+
+	"github.com/LuchaComics/cps-backend/provider/jwt_utils"
+)
+
+func testKeys(t *testing.T) (*rsa.PrivateKey, ed25519.PrivateKey) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rsaKey, edKey
+}
+
+func kidOf(t *testing.T, token string) string {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestRotation(t *testing.T) {
+	rsaKey, edKey := testKeys(t)
+	hmacSecret := []byte("123secret")
+	keys := []*Key{
+		{
+			Key:         &jwt_utils.Key{ID: "2024-02-01-ed25519", Method: jwt.SigningMethodEdDSA, PrivateKey: edKey, PublicKey: edKey.Public()},
+			ActivatesAt: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Key:         &jwt_utils.Key{ID: "2024-01-01-rs256", Method: jwt.SigningMethodRS256, PrivateKey: rsaKey, PublicKey: &rsaKey.PublicKey},
+			ActivatesAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	now := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	p := newProvider(hmacSecret, true, keys, func() time.Time { return now })
+
+	// Before any key activates the HMAC secret signs as it did before.
+	hmacToken, _, err := p.GenerateJWTToken("xxx", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := kidOf(t, hmacToken); kid != "" {
+		t.Errorf("got kid %v but was expecting none", kid)
+	}
+
+	expected := map[time.Time]string{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC):  "2024-01-01-rs256",
+		time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC): "2024-01-01-rs256",
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC):  "2024-02-01-ed25519",
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC):  "2024-02-01-ed25519",
+	}
+	for at, expectedKid := range expected {
+		now = at
+		accessToken, _, refreshToken, _, err := p.GenerateJWTTokenPair("xxx", "yyy", time.Hour, 2*time.Hour)
+		if err != nil {
+			t.Fatalf("%v: received an error %v", at, err)
+		}
+		if kid := kidOf(t, accessToken); kid != expectedKid {
+			t.Errorf("%v: got kid %v but was expecting %v", at, kid, expectedKid)
+		}
+		if uuid, err := p.ProcessJWTToken(accessToken); err != nil || uuid != "xxx" {
+			t.Errorf("%v: got %v and %v", at, uuid, err)
+		}
+		if _, refreshTokenID, err := p.ProcessJWTRefreshToken(refreshToken); err != nil || refreshTokenID != "yyy" {
+			t.Errorf("%v: got %v and %v", at, refreshTokenID, err)
+		}
+	}
+
+	// Tokens signed by the HMAC secret keep validating during the migration.
+	if _, err := p.ProcessJWTToken(hmacToken); err != nil {
+		t.Errorf("received an error %v", err)
+	}
+	strict := newProvider(hmacSecret, false, keys, func() time.Time { return now })
+	if _, err := strict.ProcessJWTToken(hmacToken); err == nil {
+		t.Error("token signed by the HMAC secret was accepted after the migration")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, edKey := testKeys(t)
+	keys := []*Key{
+		{
+			Key:         &jwt_utils.Key{ID: "2024-01-01-rs256", Method: jwt.SigningMethodRS256, PrivateKey: rsaKey, PublicKey: &rsaKey.PublicKey},
+			ActivatesAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			Key:         &jwt_utils.Key{ID: "2999-01-01-ed25519", Method: jwt.SigningMethodEdDSA, PrivateKey: edKey, PublicKey: edKey.Public()},
+			ActivatesAt: time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	p := newProvider([]byte("123secret"), true, keys, time.Now)
+
+	// Keys which activate in the future are published too.
+	jwks := p.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("got %v keys but was expecting 2", len(jwks.Keys))
+	}
+	rsaJWK, edJWK := jwks.Keys[0], jwks.Keys[1]
+	if rsaJWK.Kid != "2024-01-01-rs256" || rsaJWK.Kty != "RSA" || rsaJWK.Alg != "RS256" || rsaJWK.Use != "sig" || rsaJWK.E != "AQAB" || rsaJWK.N == "" {
+		t.Errorf("got %+v", rsaJWK)
+	}
+	if edJWK.Kid != "2999-01-01-ed25519" || edJWK.Kty != "OKP" || edJWK.Crv != "Ed25519" || edJWK.Alg != "EdDSA" || len(edJWK.X) != 43 {
+		t.Errorf("got %+v", edJWK)
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	rsaKey, edKey := testKeys(t)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"2024-01-01-rs256.pem":   pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+		"2024-02-01-ed25519.pem": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+		"README.md":              []byte("not a key"),
+	}
+	for name, b := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := LoadKeys(dir)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("got %v keys but was expecting 2", len(keys))
+	}
+	for _, k := range keys {
+		if k.ActivatesAt.Format(time.DateOnly) != k.ID[:len(time.DateOnly)] {
+			t.Errorf("%v: activates at %v", k.ID, k.ActivatesAt)
+		}
+	}
+
+	// The kid must start with the date the key activates.
+	if err := os.WriteFile(filepath.Join(dir, "rs256.pem"), files["2024-01-01-rs256.pem"], 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadKeys(dir); err == nil {
+		t.Error("key without an activation date was accepted")
+	}
+
+	if keys, err := LoadKeys(""); err != nil || keys != nil {
+		t.Errorf("got %v and %v", keys, err)
+	}
+}
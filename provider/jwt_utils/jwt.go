@@ -1,20 +1,93 @@
 package jwt_utils
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"time"
 
-	jwt "github.com/dgrijalva/jwt-go"
+	jwt "github.com/golang-jwt/jwt/v4"
 )
 
+// The `typ` claim of our tokens so one kind cannot be used as the other.
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// Key signs and verifies our tokens; the `ID` is saved in the `kid` header of
+// the tokens so we know which key verifies them. The HMAC secret has no id.
+type Key struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey interface{}
+	PublicKey  interface{}
+}
+
+// KeyFunc returns the key with the id or nil if we do not accept it.
+type KeyFunc func(kid string) *Key
+
+// NewHMACKey returns the key of the secret which both signs and verifies.
+func NewHMACKey(hmacSecret []byte) *Key {
+	return &Key{
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: hmacSecret,
+		PublicKey:  hmacSecret,
+	}
+}
+
+// ParsePrivateKey decodes the PEM encoded RSA (PKCS #1 or #8) or Ed25519
+// (PKCS #8) private key, ex: the output of `openssl genpkey -algorithm ed25519`,
+// and returns the key for RS256 or EdDSA respectively.
+func ParsePrivateKey(kid string, b []byte) (*Key, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	var privateKey interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch pk := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if pk.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("expected an RSA key of at least 2048 bits but got %v", pk.N.BitLen())
+		}
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, PrivateKey: pk, PublicKey: &pk.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, PrivateKey: pk, PublicKey: pk.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+}
+
 // GenerateJWTToken Generate the `access token` for the secret key.
 func GenerateJWTToken(hmacSecret []byte, uuid string, ad time.Duration) (string, time.Time, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+	return GenerateJWTTokenWithKey(NewHMACKey(hmacSecret), uuid, ad)
+}
+
+// GenerateJWTTokenWithKey Generate the `access token` signed by the key.
+func GenerateJWTTokenWithKey(key *Key, uuid string, ad time.Duration) (string, time.Time, error) {
+	token := newToken(key)
 	expiresIn := time.Now().Add(ad)
 	claims := token.Claims.(jwt.MapClaims)
 	claims["session_uuid"] = uuid
+	claims["typ"] = TokenTypeAccess
 	claims["exp"] = expiresIn.Unix()
 
-	tokenString, err := token.SignedString(hmacSecret)
+	tokenString, err := token.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", expiresIn, err
 	}
@@ -22,18 +95,17 @@ func GenerateJWTToken(hmacSecret []byte, uuid string, ad time.Duration) (string,
 	return tokenString, expiresIn, nil
 }
 
-// GenerateJWTTokenPair Generate the `access token` and `refresh token` for the secret key.
-func GenerateJWTTokenPair(hmacSecret []byte, uuid string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error) {
+// GenerateJWTTokenPair Generate the `access token` and `refresh token` for the secret key. The `refreshTokenID` is saved in the refresh token so it can only be used once.
+func GenerateJWTTokenPair(hmacSecret []byte, uuid string, refreshTokenID string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error) {
+	return GenerateJWTTokenPairWithKey(NewHMACKey(hmacSecret), uuid, refreshTokenID, ad, rd)
+}
+
+// GenerateJWTTokenPairWithKey Generate the `access token` and `refresh token` signed by the key.
+func GenerateJWTTokenPairWithKey(key *Key, uuid string, refreshTokenID string, ad time.Duration, rd time.Duration) (string, time.Time, string, time.Time, error) {
 	//
 	// Generate token.
 	//
-	token := jwt.New(jwt.SigningMethodHS256)
-	expiresIn := time.Now().Add(ad)
-	claims := token.Claims.(jwt.MapClaims)
-	claims["session_uuid"] = uuid
-	claims["exp"] = expiresIn.Unix()
-
-	tokenString, err := token.SignedString(hmacSecret)
+	tokenString, expiresIn, err := GenerateJWTTokenWithKey(key, uuid, ad)
 	if err != nil {
 		return "", time.Now(), "", time.Now(), err
 	}
@@ -41,13 +113,15 @@ func GenerateJWTTokenPair(hmacSecret []byte, uuid string, ad time.Duration, rd t
 	//
 	// Generate refresh token.
 	//
-	refreshToken := jwt.New(jwt.SigningMethodHS256)
+	refreshToken := newToken(key)
 	refreshExpiresIn := time.Now().Add(rd)
 	rtClaims := refreshToken.Claims.(jwt.MapClaims)
 	rtClaims["session_uuid"] = uuid
+	rtClaims["typ"] = TokenTypeRefresh
+	rtClaims["jti"] = refreshTokenID
 	rtClaims["exp"] = refreshExpiresIn.Unix()
 
-	refreshTokenString, err := refreshToken.SignedString(hmacSecret)
+	refreshTokenString, err := refreshToken.SignedString(key.PrivateKey)
 	if err != nil {
 		return "", time.Now(), "", time.Now(), err
 	}
@@ -55,18 +129,82 @@ func GenerateJWTTokenPair(hmacSecret []byte, uuid string, ad time.Duration, rd t
 	return tokenString, expiresIn, refreshTokenString, refreshExpiresIn, nil
 }
 
-// ProcessJWTToken validates either the `access token` or `refresh token` and returns either the `uuid` if success or error on failure.
+func newToken(key *Key) *jwt.Token {
+	token := jwt.New(key.Method)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token
+}
+
+// ProcessJWTToken validates the `access token` and returns either the `uuid` if success or error on failure.
 func ProcessJWTToken(hmacSecret []byte, reqToken string) (string, error) {
+	return ProcessJWTTokenWithKeys(hmacKeyFunc(hmacSecret), reqToken)
+}
+
+// ProcessJWTTokenWithKeys validates the `access token` with the key of its `kid`.
+func ProcessJWTTokenWithKeys(keys KeyFunc, reqToken string) (string, error) {
+	claims, err := processJWTToken(keys, reqToken, TokenTypeAccess)
+	if err != nil {
+		return "", err
+	}
+	return claims["session_uuid"].(string), nil
+}
+
+// ProcessJWTRefreshToken validates the `refresh token` and returns either the `uuid` and the `refreshTokenID` if success or error on failure.
+func ProcessJWTRefreshToken(hmacSecret []byte, reqToken string) (string, string, error) {
+	return ProcessJWTRefreshTokenWithKeys(hmacKeyFunc(hmacSecret), reqToken)
+}
+
+// ProcessJWTRefreshTokenWithKeys validates the `refresh token` with the key of its `kid`.
+func ProcessJWTRefreshTokenWithKeys(keys KeyFunc, reqToken string) (string, string, error) {
+	claims, err := processJWTToken(keys, reqToken, TokenTypeRefresh)
+	if err != nil {
+		return "", "", err
+	}
+	refreshTokenID, ok := claims["jti"].(string)
+	if !ok || refreshTokenID == "" {
+		return "", "", errors.New("token has no id")
+	}
+	return claims["session_uuid"].(string), refreshTokenID, nil
+}
+
+func hmacKeyFunc(hmacSecret []byte) KeyFunc {
+	key := NewHMACKey(hmacSecret)
+	return func(kid string) *Key {
+		if kid != "" {
+			return nil
+		}
+		return key
+	}
+}
+
+func processJWTToken(keys KeyFunc, reqToken string, typ string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(reqToken, func(t *jwt.Token) (interface{}, error) {
-		return hmacSecret, nil
-	})
-	if err == nil && token.Valid {
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			uuid := claims["session_uuid"].(string)
-			// m["exp"] := string(claims["exp"].(float64))
-			return uuid, nil
+		kid, _ := t.Header["kid"].(string)
+		key := keys(kid)
+		if key == nil {
+			return nil, errors.New("unknown key")
 		}
-		return "", err
+		// The algorithm must be the one of the key or else, for example, an
+		// RSA public key from our JWKS could be used as an HMAC secret.
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.PublicKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("token is invalid")
+	}
+	if claims["typ"] != typ {
+		return nil, errors.New("token type is not " + typ)
+	}
+	if _, ok := claims["session_uuid"].(string); !ok {
+		return nil, errors.New("token has no session")
 	}
-	return "", err
+	return claims, nil
 }
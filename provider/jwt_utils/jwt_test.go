@@ -1,8 +1,15 @@
 package jwt_utils
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
 )
 
 // func GenerateJWTTokenPair(hmacSecret []byte, uuid string, d time.Duration) (string, string, error) {
@@ -13,7 +20,7 @@ func TestGenerateJWTTokenPair(t *testing.T) {
 	sampleAccessDuration := 100 * time.Second
 	sampleRefreshDuration := sampleAccessDuration + 72*time.Hour
 
-	actualAccessToken, _, actualRefreshToken, _, err := GenerateJWTTokenPair(sampleHMACSecret, sampleUUID, sampleAccessDuration, sampleRefreshDuration)
+	actualAccessToken, _, actualRefreshToken, _, err := GenerateJWTTokenPair(sampleHMACSecret, sampleUUID, "yyy", sampleAccessDuration, sampleRefreshDuration)
 	if err != nil {
 		t.Errorf("received an error %v", err)
 	}
@@ -31,7 +38,7 @@ func TestProcessJWTToken(t *testing.T) {
 	sampleAccessDuration := 100 * time.Second
 	sampleRefreshDuration := sampleAccessDuration + 72*time.Hour
 
-	actualAccessToken, _, actualRefreshToken, _, err := GenerateJWTTokenPair(sampleHMACSecret, sampleUUID, sampleAccessDuration, sampleRefreshDuration)
+	actualAccessToken, _, actualRefreshToken, _, err := GenerateJWTTokenPair(sampleHMACSecret, sampleUUID, "yyy", sampleAccessDuration, sampleRefreshDuration)
 	if err != nil {
 		t.Errorf("received an error %v", err)
 	}
@@ -53,3 +60,121 @@ func TestProcessJWTToken(t *testing.T) {
 		t.Errorf("jwt claim is wrong, got %v but was expecting %v", actualUUID, sampleUUID)
 	}
 }
+
+func TestProcessJWTTokenType(t *testing.T) {
+	sampleHMACSecret := []byte("123secret")
+	sampleUUID := "xxx-xxx-xxx-xxx"
+	sampleRefreshTokenID := "yyy"
+
+	accessToken, _, refreshToken, _, err := GenerateJWTTokenPair(sampleHMACSecret, sampleUUID, sampleRefreshTokenID, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+
+	// Neither token can be used as the other.
+	if _, err := ProcessJWTToken(sampleHMACSecret, refreshToken); err == nil {
+		t.Error("refresh token was accepted as an access token")
+	}
+	if _, _, err := ProcessJWTRefreshToken(sampleHMACSecret, accessToken); err == nil {
+		t.Error("access token was accepted as a refresh token")
+	}
+
+	actualUUID, actualRefreshTokenID, err := ProcessJWTRefreshToken(sampleHMACSecret, refreshToken)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if actualUUID != sampleUUID || actualRefreshTokenID != sampleRefreshTokenID {
+		t.Errorf("jwt claims are wrong, got %v and %v", actualUUID, actualRefreshTokenID)
+	}
+}
+
+func TestProcessJWTTokenWithKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := map[string]*Key{
+		"rsa": {ID: "rsa", Method: jwt.SigningMethodRS256, PrivateKey: rsaKey, PublicKey: &rsaKey.PublicKey},
+		"ed":  {ID: "ed", Method: jwt.SigningMethodEdDSA, PrivateKey: edKey, PublicKey: edKey.Public()},
+	}
+	keyFunc := func(kid string) *Key { return keys[kid] }
+
+	for kid, key := range keys {
+		accessToken, _, refreshToken, _, err := GenerateJWTTokenPairWithKey(key, "xxx", "yyy", time.Minute, time.Hour)
+		if err != nil {
+			t.Fatalf("%v: received an error %v", kid, err)
+		}
+		if uuid, err := ProcessJWTTokenWithKeys(keyFunc, accessToken); err != nil || uuid != "xxx" {
+			t.Errorf("%v: got %v and %v", kid, uuid, err)
+		}
+		if uuid, refreshTokenID, err := ProcessJWTRefreshTokenWithKeys(keyFunc, refreshToken); err != nil || uuid != "xxx" || refreshTokenID != "yyy" {
+			t.Errorf("%v: got %v, %v and %v", kid, uuid, refreshTokenID, err)
+		}
+		// The token of a key we no longer have is rejected.
+		if _, err := ProcessJWTTokenWithKeys(func(string) *Key { return nil }, accessToken); err == nil {
+			t.Errorf("%v: token of an unknown key was accepted", kid)
+		}
+	}
+
+	// A token signed with the public RSA key as an HMAC secret is rejected.
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"session_uuid": "xxx",
+		"typ":          TokenTypeAccess,
+		"exp":          time.Now().Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = "rsa"
+	forged, err := token.SignedString(x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessJWTTokenWithKeys(keyFunc, forged); err == nil {
+		t.Error("token signed with a different algorithm than the key was accepted")
+	}
+
+	// Tokens with a kid are not accepted by the HMAC secret.
+	hmacToken, _, err := GenerateJWTTokenWithKey(&Key{ID: "hmac", Method: jwt.SigningMethodHS256, PrivateKey: []byte("123secret")}, "xxx", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessJWTToken([]byte("123secret"), hmacToken); err == nil {
+		t.Error("token with a kid was accepted by the HMAC secret")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smallKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ParsePrivateKey("rsa", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}))
+	if err != nil || key.Method.Alg() != "RS256" || key.ID != "rsa" {
+		t.Errorf("got %v and %v", key, err)
+	}
+	key, err = ParsePrivateKey("ed", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}))
+	if err != nil || key.Method.Alg() != "EdDSA" || key.ID != "ed" {
+		t.Errorf("got %v and %v", key, err)
+	}
+	if _, err := ParsePrivateKey("small", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(smallKey)})); err == nil {
+		t.Error("RSA key of 1024 bits was accepted")
+	}
+	if _, err := ParsePrivateKey("bad", []byte("not a key")); err == nil {
+		t.Error("invalid PEM was accepted")
+	}
+}
@@ -0,0 +1,133 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/LuchaComics/cps-backend/config"
+)
+
+// Provider provides interface for signing records with Ed25519 so anyone
+// with our public keys can detect if a record was changed after it was signed.
+type Provider interface {
+	// KeyID returns the id of the key which signs new records.
+	KeyID() string
+
+	// Sign returns the base64 encoded signature of the message and the id of
+	// the key which signed it.
+	Sign(message []byte) (string, string)
+
+	// Verify returns true if the base64 encoded signature of the message was
+	// made by the key with the id. Signatures of retired keys still verify.
+	Verify(keyID string, message []byte, signature string) bool
+
+	// PublicKeys returns the base64 encoded public key of every key id.
+	PublicKeys() map[string]string
+}
+
+type signingProvider struct {
+	keyID      string
+	privateKey ed25519.PrivateKey
+	publicKeys map[string]ed25519.PublicKey
+}
+
+// NewProvider Constructor that returns the Ed25519 signer. To rotate the key,
+// move the public key of the current key id into the verification keys and
+// then configure a new key id and private key.
+func NewProvider(cfg *config.Conf) Provider {
+	privateKey, err := ParsePrivateKey(cfg.Signing.PrivateKey)
+	if err != nil {
+		log.Fatalf("signing private key: %v", err)
+	}
+	verificationKeys, err := ParseVerificationKeys(cfg.Signing.VerificationKeys)
+	if err != nil {
+		log.Fatalf("signing verification keys: %v", err)
+	}
+	return newProvider(cfg.Signing.KeyID, privateKey, verificationKeys)
+}
+
+func newProvider(keyID string, privateKey ed25519.PrivateKey, verificationKeys map[string]ed25519.PublicKey) *signingProvider {
+	publicKeys := map[string]ed25519.PublicKey{}
+	for id, pub := range verificationKeys {
+		publicKeys[id] = pub
+	}
+	publicKeys[keyID] = privateKey.Public().(ed25519.PublicKey)
+	return &signingProvider{
+		keyID:      keyID,
+		privateKey: privateKey,
+		publicKeys: publicKeys,
+	}
+}
+
+// ParsePrivateKey decodes the base64 encoded 32 byte seed (ex: the output of
+// `openssl rand -base64 32`) or 64 byte private key.
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	switch len(b) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(b), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(b), nil
+	default:
+		return nil, fmt.Errorf("expected %v or %v bytes but got %v", ed25519.SeedSize, ed25519.PrivateKeySize, len(b))
+	}
+}
+
+// ParseVerificationKeys decodes the comma separated list of `key id:base64
+// encoded public key` pairs, ex: "2023-01:MCowBQ...,2023-06:ZmFrZ...".
+func ParseVerificationKeys(s string) (map[string]ed25519.PublicKey, error) {
+	keys := map[string]ed25519.PublicKey{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("expected `key id:public key` but got %q", pair)
+		}
+		b, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key id %v: %v", id, err)
+		}
+		if len(b) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key id %v: expected %v bytes but got %v", id, ed25519.PublicKeySize, len(b))
+		}
+		keys[id] = ed25519.PublicKey(b)
+	}
+	return keys, nil
+}
+
+func (p *signingProvider) KeyID() string {
+	return p.keyID
+}
+
+func (p *signingProvider) Sign(message []byte) (string, string) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(p.privateKey, message)), p.keyID
+}
+
+func (p *signingProvider) Verify(keyID string, message []byte, signature string) bool {
+	pub, ok := p.publicKeys[keyID]
+	if !ok {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, message, sig)
+}
+
+func (p *signingProvider) PublicKeys() map[string]string {
+	keys := make(map[string]string, len(p.publicKeys))
+	for id, pub := range p.publicKeys {
+		keys[id] = base64.StdEncoding.EncodeToString(pub)
+	}
+	return keys
+}
@@ -0,0 +1,76 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) ed25519.PrivateKey {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = b
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+func TestSignAndVerify(t *testing.T) {
+	p := newProvider("2023-01", testKey(1), nil)
+
+	sig, keyID := p.Sign([]byte("hello"))
+	if keyID != "2023-01" {
+		t.Errorf("expected key id 2023-01 but got %v", keyID)
+	}
+	if !p.Verify(keyID, []byte("hello"), sig) {
+		t.Error("expected signature to verify")
+	}
+	if p.Verify(keyID, []byte("hellO"), sig) {
+		t.Error("expected changed message to not verify")
+	}
+	if p.Verify("2022-12", []byte("hello"), sig) {
+		t.Error("expected unknown key id to not verify")
+	}
+	if p.Verify(keyID, []byte("hello"), "not base64!") {
+		t.Error("expected malformed signature to not verify")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	old := newProvider("2023-01", testKey(1), nil)
+	sig, keyID := old.Sign([]byte("hello"))
+
+	// Retire the old key by moving its public key into the verification keys.
+	verificationKeys, err := ParseVerificationKeys("2023-01:" + old.PublicKeys()["2023-01"])
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	p := newProvider("2023-06", testKey(2), verificationKeys)
+
+	if p.KeyID() != "2023-06" {
+		t.Errorf("expected key id 2023-06 but got %v", p.KeyID())
+	}
+	if !p.Verify(keyID, []byte("hello"), sig) {
+		t.Error("expected signature of retired key to verify")
+	}
+	if newSig, _ := p.Sign([]byte("hello")); p.Verify(keyID, []byte("hello"), newSig) {
+		t.Error("expected signature of new key to not verify with retired key")
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	seed := base64.StdEncoding.EncodeToString(testKey(3).Seed())
+	k, err := ParsePrivateKey(seed)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if !k.Equal(testKey(3)) {
+		t.Error("expected the key of the seed")
+	}
+	if _, err := ParsePrivateKey("xxx"); err == nil {
+		t.Error("expected malformed key error")
+	}
+	if _, err := ParseVerificationKeys("2023-01"); err == nil || !strings.Contains(err.Error(), "key id:public key") {
+		t.Errorf("expected malformed pair error but got %v", err)
+	}
+}
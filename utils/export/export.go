@@ -0,0 +1,135 @@
+// Package export streams records to the client as CSV or newline delimited
+// JSON while they are read from the database.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LuchaComics/cps-backend/utils/httperror"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// flushEvery is how many records are written before they are pushed to the
+// client.
+const flushEvery = 100
+
+// ErrorTrailer is the HTTP trailer set when an export fails after it started,
+// so the client can tell the file it received is incomplete.
+const ErrorTrailer = "X-Export-Error"
+
+// errorMessage is what the client is told of an export which failed after it
+// started; the cause stays in our logs.
+const errorMessage = "export failed, the file is incomplete"
+
+// ParseFormat returns the format of the `format` url parameter; CSV is the
+// default.
+func ParseFormat(format string) (string, error) {
+	switch format {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	}
+	return "", httperror.NewForBadRequestWithSingleField("format", "must be csv or ndjson")
+}
+
+// Writer writes the records of an export. Nothing is sent until the first
+// record so an error raised before then is still returned as a normal error
+// response.
+type Writer struct {
+	w        http.ResponseWriter
+	format   string
+	fileName string
+	header   []string
+	csv      *csv.Writer
+	json     *json.Encoder
+	started  bool
+	count    int
+}
+
+// NewWriter returns a writer of the records in the format; the header are
+// the columns of the CSV file and the file name is used without extension.
+func NewWriter(w http.ResponseWriter, format string, fileName string, header []string) *Writer {
+	return &Writer{
+		w:        w,
+		format:   format,
+		fileName: fileName,
+		header:   header,
+	}
+}
+
+func (e *Writer) start() error {
+	e.started = true
+	name := fmt.Sprintf("%s-%s.%s", e.fileName, time.Now().Format("20060102-150405"), e.format)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	e.w.Header().Set("Trailer", ErrorTrailer)
+	if e.format == FormatNDJSON {
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+		e.json = json.NewEncoder(e.w)
+		return nil
+	}
+	e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	e.csv = csv.NewWriter(e.w)
+	return e.csv.Write(e.header)
+}
+
+// Write writes the record as one JSON line or as the CSV row returned by
+// `row`, which has the same columns as the header.
+func (e *Writer) Write(record interface{}, row func() []string) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	if e.format == FormatNDJSON {
+		if err := e.json.Encode(record); err != nil {
+			return err
+		}
+	} else if err := e.csv.Write(row()); err != nil {
+		return err
+	}
+
+	e.count++
+	if e.count%flushEvery == 0 {
+		e.flush()
+	}
+	return nil
+}
+
+// Close finishes the export. If the export failed before anything was
+// written the error is returned to the client. Otherwise the response was
+// already sent so the client receives a truncated file along with the
+// `ErrorTrailer`; an NDJSON export also ends with an `export_error` line.
+func (e *Writer) Close(err error) {
+	if err != nil && !e.started {
+		httperror.ResponseError(e.w, err)
+		return
+	}
+	if !e.started {
+		_ = e.start() // An empty export still has the header.
+	}
+	if err != nil && e.format == FormatNDJSON {
+		_ = e.json.Encode(map[string]string{"export_error": errorMessage})
+	}
+	e.flush()
+	if err != nil {
+		e.w.Header().Set(ErrorTrailer, errorMessage)
+	}
+}
+
+func (e *Writer) flush() {
+	if e.csv != nil {
+		e.csv.Flush()
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type record struct {
+	Name string `json:"name"`
+}
+
+func TestWriterCSV(t *testing.T) {
+	w := httptest.NewRecorder()
+	ew := NewWriter(w, FormatCSV, "records", []string{"name"})
+	for _, name := range []string{"Spawn", "X-Men, The"} {
+		r := &record{Name: name}
+		if err := ew.Write(r, func() []string { return []string{r.Name} }); err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+	}
+	ew.Close(nil)
+
+	if expected := "name\nSpawn\n\"X-Men, The\"\n"; w.Body.String() != expected {
+		t.Errorf("expected %q but got %q", expected, w.Body.String())
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Disposition"), `attachment; filename="records-`) {
+		t.Errorf("unexpected content disposition %q", w.Header().Get("Content-Disposition"))
+	}
+}
+
+func TestWriterNDJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	ew := NewWriter(w, FormatNDJSON, "records", nil)
+	for _, name := range []string{"Spawn", "X-Men"} {
+		if err := ew.Write(&record{Name: name}, nil); err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+	}
+	ew.Close(nil)
+
+	if expected := "{\"name\":\"Spawn\"}\n{\"name\":\"X-Men\"}\n"; w.Body.String() != expected {
+		t.Errorf("expected %q but got %q", expected, w.Body.String())
+	}
+}
+
+func TestWriterErrorBeforeFirstRecord(t *testing.T) {
+	w := httptest.NewRecorder()
+	ew := NewWriter(w, FormatCSV, "records", []string{"name"})
+	ew.Close(errors.New("database is down"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %v but got %v", http.StatusInternalServerError, w.Code)
+	}
+	if w.Header().Get("Content-Disposition") != "" {
+		t.Errorf("expected no attachment but got %q", w.Header().Get("Content-Disposition"))
+	}
+}
+
+func TestWriterErrorAfterFirstRecord(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatNDJSON} {
+		w := httptest.NewRecorder()
+		ew := NewWriter(w, format, "records", []string{"name"})
+		r := &record{Name: "Spawn"}
+		if err := ew.Write(r, func() []string { return []string{r.Name} }); err != nil {
+			t.Fatalf("received an error %v", err)
+		}
+		ew.Close(errors.New("cursor died"))
+
+		res := w.Result()
+		if res.Trailer.Get(ErrorTrailer) == "" {
+			t.Errorf("%v: expected the %v trailer", format, ErrorTrailer)
+		}
+		if isNDJSON := strings.HasSuffix(w.Body.String(), "{\"export_error\":\""+errorMessage+"\"}\n"); isNDJSON != (format == FormatNDJSON) {
+			t.Errorf("%v: unexpected body %q", format, w.Body.String())
+		}
+	}
+
+	// A complete export has no error.
+	w := httptest.NewRecorder()
+	ew := NewWriter(w, FormatCSV, "records", []string{"name"})
+	ew.Close(nil)
+	if w.Result().Trailer.Get(ErrorTrailer) != "" {
+		t.Errorf("expected no %v trailer", ErrorTrailer)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, _ := ParseFormat(""); format != FormatCSV {
+		t.Errorf("expected %v but got %v", FormatCSV, format)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected an error")
+	}
+}
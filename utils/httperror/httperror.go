@@ -54,6 +54,14 @@ func NewForForbiddenWithSingleField(field string, message string) error {
 	}
 }
 
+// NewForNotFoundWithSingleField create a new HTTPError instance pertaining to 404 not found for a single field. This is a convinience constructor.
+func NewForNotFoundWithSingleField(field string, message string) error {
+	return HTTPError{
+		Code:   http.StatusNotFound,
+		Errors: &map[string]string{field: message},
+	}
+}
+
 // Error function used to implement the `error` interface for returning errors.
 func (err HTTPError) Error() string {
 	b, e := json.Marshal(err.Errors)
@@ -0,0 +1,338 @@
+// Package openapi builds an OpenAPI 3 document out of our route table where
+// the request and response schemas are generated from the structs our
+// handlers decode and encode.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const Version = "3.0.3"
+
+// Operation describes the request and response of one route.
+type Operation struct {
+	Summary string
+	Tag     string
+
+	// Query are the names of the optional query parameters.
+	Query []string
+
+	// Request is a value of the JSON body, ex: `&OrderCreateRequestIDO{}`, or
+	// of the form fields when `IsMultipart` is set; nil if there is no body.
+	Request     interface{}
+	IsMultipart bool
+
+	// Response is a value of the JSON body of the successful response; when
+	// nil the response has no content unless `ContentTypes` is set.
+	Response interface{}
+
+	// Status of the successful response; defaults to `200 OK`, or to
+	// `204 No Content` if there is neither response nor content type.
+	Status int
+
+	// ContentTypes of a successful response which is not JSON, ex: `text/csv`.
+	ContentTypes []string
+
+	// Headers of a successful response, trailers included, by name with
+	// their description.
+	Headers map[string]string
+}
+
+// Schema is a JSON schema object of the document.
+type Schema map[string]interface{}
+
+type Document struct {
+	OpenAPI    string                            `json:"openapi"`
+	Info       map[string]string                 `json:"info"`
+	Paths      map[string]map[string]interface{} `json:"paths"`
+	Components map[string]interface{}            `json:"components"`
+
+	schemas map[string]Schema
+	names   map[reflect.Type]string
+}
+
+// NewDocument returns an empty document with our security scheme and field
+// error format of `httperror.HTTPError`.
+func NewDocument(title string, version string) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info:    map[string]string{"title": title, "version": version},
+		Paths:   make(map[string]map[string]interface{}),
+		schemas: map[string]Schema{
+			"HTTPError": {
+				"type":                 "object",
+				"description":          "Errors keyed by the field of the request, ex: `{\"email\": \"missing value\"}`; errors which are not about a field use `non_field_error` or `message`.",
+				"additionalProperties": Schema{"type": "string"},
+			},
+		},
+		names: make(map[reflect.Type]string),
+	}
+	errorResponse := func(description string) Schema {
+		return Schema{
+			"description": description,
+			"content": Schema{"application/json": Schema{
+				"schema": Schema{"$ref": "#/components/schemas/HTTPError"},
+			}},
+		}
+	}
+	d.Components = map[string]interface{}{
+		"schemas": d.schemas,
+		"securitySchemes": Schema{
+			"JWT": Schema{
+				"type":        "apiKey",
+				"in":          "header",
+				"name":        "Authorization",
+				"description": "The access token of the login prefixed with `JWT `.",
+			},
+			"ApiKey": Schema{
+				"type":        "apiKey",
+				"in":          "header",
+				"name":        "Authorization",
+				"description": "The API key of an organization prefixed with `ApiKey `.",
+			},
+		},
+		"responses": Schema{
+			"BadRequest":       errorResponse("The request is invalid."),
+			"Unauthorized":     errorResponse("The user is not logged in."),
+			"Forbidden":        errorResponse("The user does not have permission."),
+			"MethodNotAllowed": errorResponse("The route does not support the method; see the `Allow` header."),
+		},
+	}
+	return d
+}
+
+// AddOperation documents the route; `pattern` is in the format of our router,
+// ex: `/v1/order/{id}`, which is also the format of OpenAPI. Private routes
+// accept API keys only if `allowAPIKey` is true.
+func (d *Document) AddOperation(method string, pattern string, isPublic bool, allowAPIKey bool, permission string, op *Operation) {
+	o := Schema{
+		"summary":     op.Summary,
+		"operationId": operationID(method, pattern),
+	}
+	if op.Tag != "" {
+		o["tags"] = []string{op.Tag}
+	}
+
+	var params []Schema
+	for _, segment := range strings.Split(pattern, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, Schema{
+				"name":     segment[1 : len(segment)-1],
+				"in":       "path",
+				"required": true,
+				"schema":   Schema{"type": "string"},
+			})
+		}
+	}
+	for _, name := range op.Query {
+		params = append(params, Schema{
+			"name":   name,
+			"in":     "query",
+			"schema": Schema{"type": "string"},
+		})
+	}
+	if len(params) != 0 {
+		o["parameters"] = params
+	}
+
+	if op.Request != nil {
+		contentType := "application/json"
+		if op.IsMultipart {
+			contentType = "multipart/form-data"
+		}
+		o["requestBody"] = Schema{
+			"required": true,
+			"content":  Schema{contentType: Schema{"schema": d.SchemaOf(reflect.TypeOf(op.Request))}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+		if op.Response == nil && len(op.ContentTypes) == 0 {
+			status = http.StatusNoContent
+		}
+	}
+	success := Schema{"description": http.StatusText(status)}
+	content := Schema{}
+	if op.Response != nil {
+		content["application/json"] = Schema{"schema": d.SchemaOf(reflect.TypeOf(op.Response))}
+	}
+	for _, contentType := range op.ContentTypes {
+		content[contentType] = Schema{"schema": Schema{"type": "string"}}
+	}
+	if len(content) != 0 {
+		success["content"] = content
+	}
+	if len(op.Headers) != 0 {
+		headers := Schema{}
+		for name, description := range op.Headers {
+			headers[name] = Schema{"description": description, "schema": Schema{"type": "string"}}
+		}
+		success["headers"] = headers
+	}
+	responses := Schema{
+		fmt.Sprint(status): success,
+		"400":              Schema{"$ref": "#/components/responses/BadRequest"},
+		"405":              Schema{"$ref": "#/components/responses/MethodNotAllowed"},
+	}
+
+	if isPublic {
+		o["security"] = []Schema{}
+	} else {
+		security := []Schema{{"JWT": []string{}}}
+		if allowAPIKey {
+			security = append(security, Schema{"ApiKey": []string{}})
+		}
+		o["security"] = security
+		responses["401"] = Schema{"$ref": "#/components/responses/Unauthorized"}
+		responses["403"] = Schema{"$ref": "#/components/responses/Forbidden"}
+	}
+	if permission != "" {
+		o["x-permission"] = permission
+	}
+	o["responses"] = responses
+
+	if d.Paths[pattern] == nil {
+		d.Paths[pattern] = make(map[string]interface{})
+	}
+	d.Paths[pattern][strings.ToLower(method)] = o
+}
+
+// operationID returns a unique identifier of the route, ex:
+// `get_v1_order_id` for `GET /v1/order/{id}`.
+func operationID(method string, pattern string) string {
+	id := strings.NewReplacer("{", "", "}", "", "-", "_", "/", "_").Replace(pattern)
+	return strings.ToLower(method) + id
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// SchemaOf returns the schema of the type; named structs are added to the
+// components of the document and referenced.
+func (d *Document) SchemaOf(t reflect.Type) Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case objectIDType:
+		return Schema{"type": "string", "pattern": "^[0-9a-f]{24}$"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return Schema{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return Schema{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "format": "binary"}
+		}
+		return Schema{"type": "array", "items": d.SchemaOf(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": d.SchemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.structSchema(t)
+		}
+		name, ok := d.names[t]
+		if !ok {
+			name = d.componentName(t)
+			d.names[t] = name
+			d.schemas[name] = Schema{} // Reserve the name for recursive types.
+			d.schemas[name] = d.structSchema(t)
+		}
+		return Schema{"$ref": "#/components/schemas/" + name}
+	}
+	return Schema{} // Any value, ex: `interface{}`.
+}
+
+// componentName returns the name of the struct inside the components; if
+// another package has a struct of the same name then the name of the package
+// directory is prefixed, ex: `CustomerUser`.
+func (d *Document) componentName(t reflect.Type) string {
+	name := capitalize(t.Name())
+	if _, taken := d.schemas[name]; !taken {
+		return name
+	}
+	var prefix string
+	for _, word := range strings.Split(path.Base(path.Dir(t.PkgPath())), "-") {
+		prefix += capitalize(word)
+	}
+	for i := 2; ; i++ {
+		candidate := prefix + name
+		if i > 2 {
+			candidate = fmt.Sprintf("%s%s%d", prefix, name, i)
+		}
+		if _, taken := d.schemas[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// structSchema returns the schema of the fields encoded by `encoding/json`.
+// Fields of embedded structs are promoted unless a shallower field has the
+// same name.
+func (d *Document) structSchema(t reflect.Type) Schema {
+	properties := Schema{}
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = d.SchemaOf(f.Type)
+	}
+	for _, et := range embedded {
+		promoted := d.structSchema(et)["properties"].(Schema)
+		for name, schema := range promoted {
+			if _, ok := properties[name]; !ok {
+				properties[name] = schema
+			}
+		}
+	}
+	return Schema{"type": "object", "properties": properties}
+}
@@ -0,0 +1,71 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type customer struct {
+	address
+	ID        primitive.ObjectID `json:"id"`
+	City      string             `json:"town"`
+	Name      string             `json:"name,omitempty"`
+	Password  string             `json:"-"`
+	CreatedAt time.Time          `json:"created_at"`
+	Friends   []*customer        `json:"friends"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	d := NewDocument("Test", "v1")
+
+	ref := d.SchemaOf(reflect.TypeOf(&customer{}))
+	if ref["$ref"] != "#/components/schemas/Customer" {
+		t.Fatalf("expected a reference to the component but got %v", ref)
+	}
+	properties := d.schemas["Customer"]["properties"].(Schema)
+	for _, name := range []string{"id", "town", "city", "name", "created_at", "friends"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("expected property %v", name)
+		}
+	}
+	if _, ok := properties["Password"]; ok {
+		t.Error("expected `json:\"-\"` fields to be skipped")
+	}
+	if properties["created_at"].(Schema)["format"] != "date-time" {
+		t.Errorf("expected time to be a date-time but got %v", properties["created_at"])
+	}
+	if properties["id"].(Schema)["type"] != "string" {
+		t.Errorf("expected object id to be a string but got %v", properties["id"])
+	}
+	if items := properties["friends"].(Schema)["items"].(Schema); items["$ref"] != "#/components/schemas/Customer" {
+		t.Errorf("expected recursive reference but got %v", items)
+	}
+}
+
+func TestAddOperation(t *testing.T) {
+	d := NewDocument("Test", "v1")
+	d.AddOperation(http.MethodDelete, "/v1/customer/{id}", false, true, "customer:manage", &Operation{Summary: "Deletes the customer."})
+
+	op := d.Paths["/v1/customer/{id}"]["delete"].(Schema)
+	if op["operationId"] != "delete_v1_customer_id" {
+		t.Errorf("unexpected operation id %v", op["operationId"])
+	}
+	responses := op["responses"].(Schema)
+	for _, status := range []string{"204", "400", "401", "403"} {
+		if _, ok := responses[status]; !ok {
+			t.Errorf("expected response %v", status)
+		}
+	}
+	params := op["parameters"].([]Schema)
+	if len(params) != 1 || params[0]["name"] != "id" || params[0]["in"] != "path" {
+		t.Errorf("expected the id path parameter but got %v", params)
+	}
+}
@@ -0,0 +1,580 @@
+// Package qrcode implements a QR Code (ISO/IEC 18004) encoder for text in
+// byte mode so we do not depend on an external service to print the codes
+// onto our certificates.
+package qrcode
+
+import (
+	"errors"
+	"math"
+)
+
+// Level is the error correction level of the QR Code; the higher the level
+// the more of the code can be damaged (ex: scratched slab) and still be read.
+type Level int
+
+const (
+	Low      Level = iota // Recovers 7% of the codewords.
+	Medium                // Recovers 15% of the codewords.
+	Quartile              // Recovers 25% of the codewords.
+	High                  // Recovers 30% of the codewords.
+)
+
+// ErrTooLong is returned when the text does not fit into a version 40 code.
+var ErrTooLong = errors.New("text too long for qr code")
+
+const (
+	minVersion = 1
+	maxVersion = 40
+)
+
+// The format bits of each level, see table 12 of the specification.
+var formatBits = [4]int{1, 0, 3, 2}
+
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var numErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// QRCode is the grid of dark and light modules which make up the code.
+type QRCode struct {
+	Version int
+	Size    int // Number of modules on each side.
+	Level   Level
+	Mask    int
+
+	modules    [][]bool // true means dark.
+	isFunction [][]bool // Modules which are not part of the data.
+}
+
+// Encode returns the smallest QR Code which fits the text in byte mode at
+// the error correction level.
+func Encode(text string, level Level) (*QRCode, error) {
+	if level < Low || level > High {
+		return nil, errors.New("unsupported qr code level")
+	}
+	data := []byte(text)
+
+	version := minVersion
+	for ; ; version++ {
+		if version > maxVersion {
+			return nil, ErrTooLong
+		}
+		if bitsNeeded(len(data), version) <= numDataCodewords(version, level)*8 {
+			break
+		}
+	}
+
+	// Mode indicator, character count and the data.
+	var bb bitBuffer
+	bb.appendBits(0x4, 4) // Byte mode.
+	bb.appendBits(len(data), charCountBits(version))
+	for _, b := range data {
+		bb.appendBits(int(b), 8)
+	}
+
+	// Terminator and padding to a byte, then alternate the pad bytes.
+	capacity := numDataCodewords(version, level) * 8
+	bb.appendBits(0, minInt(4, capacity-len(bb)))
+	bb.appendBits(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.appendBits(pad, 8)
+	}
+
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	q := &QRCode{
+		Version: version,
+		Size:    version*4 + 17,
+		Level:   level,
+	}
+	q.modules = newGrid(q.Size)
+	q.isFunction = newGrid(q.Size)
+
+	q.drawFunctionPatterns()
+	q.drawCodewords(q.addECCAndInterleave(codewords))
+
+	// Pick the mask with the lowest penalty. Applying a mask twice undoes it.
+	minPenalty := math.MaxInt32
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penaltyScore(); penalty < minPenalty {
+			q.Mask = mask
+			minPenalty = penalty
+		}
+		q.applyMask(mask)
+	}
+	q.applyMask(q.Mask)
+	q.drawFormatBits(q.Mask)
+	q.isFunction = nil
+	return q, nil
+}
+
+// Black returns true if the module at the column `x` and row `y` is dark.
+// Coordinates outside of the code are light, ex: the quiet zone.
+func (q *QRCode) Black(x, y int) bool {
+	return x >= 0 && x < q.Size && y >= 0 && y < q.Size && q.modules[y][x]
+}
+
+func bitsNeeded(dataLen int, version int) int {
+	return 4 + charCountBits(version) + dataLen*8
+}
+
+func charCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func newGrid(size int) [][]bool {
+	g := make([][]bool, size)
+	for i := range g {
+		g[i] = make([]bool, size)
+	}
+	return g
+}
+
+//
+// Function patterns.
+//
+
+func (q *QRCode) setFunctionModule(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+func (q *QRCode) drawFunctionPatterns() {
+	// Timing patterns.
+	for i := 0; i < q.Size; i++ {
+		q.setFunctionModule(6, i, i%2 == 0)
+		q.setFunctionModule(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns in three of the corners.
+	q.drawFinderPattern(3, 3)
+	q.drawFinderPattern(q.Size-4, 3)
+	q.drawFinderPattern(3, q.Size-4)
+
+	// Alignment patterns, except where they overlap the finder patterns.
+	positions := alignmentPatternPositions(q.Version)
+	n := len(positions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if (i == 0 && j == 0) || (i == 0 && j == n-1) || (i == n-1 && j == 0) {
+				continue
+			}
+			q.drawAlignmentPattern(positions[i], positions[j])
+		}
+	}
+
+	// Reserve the format bits for now, they are drawn after masking.
+	q.drawFormatBits(0)
+	q.drawVersion()
+}
+
+func (q *QRCode) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := maxInt(absInt(dx), absInt(dy)) // Chebyshev distance.
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < q.Size && yy >= 0 && yy < q.Size {
+				q.setFunctionModule(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func (q *QRCode) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			q.setFunctionModule(x+dx, y+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+func (q *QRCode) drawFormatBits(mask int) {
+	// Five data bits protected by a ten bit BCH code.
+	data := formatBits[q.Level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+
+	// First copy around the top left finder pattern.
+	for i := 0; i <= 5; i++ {
+		q.setFunctionModule(8, i, getBit(bits, i))
+	}
+	q.setFunctionModule(8, 7, getBit(bits, 6))
+	q.setFunctionModule(8, 8, getBit(bits, 7))
+	q.setFunctionModule(7, 8, getBit(bits, 8))
+	for i := 9; i < 15; i++ {
+		q.setFunctionModule(14-i, 8, getBit(bits, i))
+	}
+
+	// Second copy split between the other two finder patterns.
+	for i := 0; i < 8; i++ {
+		q.setFunctionModule(q.Size-1-i, 8, getBit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunctionModule(8, q.Size-15+i, getBit(bits, i))
+	}
+	q.setFunctionModule(8, q.Size-8, true) // Always dark.
+}
+
+func (q *QRCode) drawVersion() {
+	if q.Version < 7 {
+		return
+	}
+
+	// Six data bits protected by a twelve bit BCH code.
+	rem := q.Version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := q.Version<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := getBit(bits, i)
+		a, b := q.Size-11+i%3, i/3
+		q.setFunctionModule(a, b, dark)
+		q.setFunctionModule(b, a, dark)
+	}
+}
+
+// alignmentPatternPositions returns the row and column centers of the
+// alignment patterns of the version.
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	num := version/7 + 2
+	var step int
+	if version == 32 {
+		step = 26
+	} else {
+		step = (version*4 + num*2 + 1) / (num*2 - 2) * 2
+	}
+	result := make([]int, num)
+	result[0] = 6
+	for i, pos := num-1, version*4+10; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+//
+// Codewords.
+//
+
+// numRawDataModules returns the number of modules available for data and
+// error correction codewords in the version.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int, level Level) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*numErrorCorrectionBlocks[level][version]
+}
+
+// addECCAndInterleave splits the data into blocks, adds the error correction
+// codewords to each block and interleaves them.
+func (q *QRCode) addECCAndInterleave(data []byte) []byte {
+	numBlocks := numErrorCorrectionBlocks[q.Level][q.Version]
+	blockECCLen := eccCodewordsPerBlock[q.Level][q.Version]
+	rawCodewords := numRawDataModules(q.Version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockECCLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		datLen := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			datLen++
+		}
+		dat := data[k : k+datLen]
+		k += datLen
+
+		// Short blocks get a padding byte so all blocks have equal length;
+		// it is skipped when interleaving.
+		block := make([]byte, 0, shortBlockLen+1)
+		block = append(block, dat...)
+		if i < numShortBlocks {
+			block = append(block, 0)
+		}
+		block = append(block, reedSolomonRemainder(dat, divisor)...)
+		blocks[i] = block
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// drawCodewords places the codewords in the zig-zag order starting from the
+// bottom right corner and going up and down in columns of two.
+func (q *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern.
+		}
+		for vert := 0; vert < q.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = q.Size - 1 - vert
+				}
+				if !q.isFunction[y][x] && i < len(data)*8 {
+					q.modules[y][x] = getBit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+				// Any remainder bits are left light.
+			}
+		}
+	}
+}
+
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+//
+// Mask penalty.
+//
+
+const (
+	penaltyN1 = 3
+	penaltyN2 = 3
+	penaltyN3 = 40
+	penaltyN4 = 10
+)
+
+func (q *QRCode) penaltyScore() int {
+	result := 0
+
+	// Runs of five or more modules of the same color and finder-like
+	// patterns in the rows and columns.
+	for _, vertical := range []bool{false, true} {
+		for a := 0; a < q.Size; a++ {
+			runColor := false
+			runLen := 0
+			var history [7]int
+			for b := 0; b < q.Size; b++ {
+				dark := q.modules[a][b]
+				if vertical {
+					dark = q.modules[b][a]
+				}
+				if dark == runColor {
+					runLen++
+					if runLen == 5 {
+						result += penaltyN1
+					} else if runLen > 5 {
+						result++
+					}
+				} else {
+					q.finderPenaltyAddHistory(runLen, &history)
+					if !runColor {
+						result += q.finderPenaltyCountPatterns(&history) * penaltyN3
+					}
+					runColor = dark
+					runLen = 1
+				}
+			}
+			result += q.finderPenaltyTerminateAndCount(runColor, runLen, &history) * penaltyN3
+		}
+	}
+
+	// Blocks of two by two modules of the same color.
+	for y := 0; y < q.Size-1; y++ {
+		for x := 0; x < q.Size-1; x++ {
+			c := q.modules[y][x]
+			if c == q.modules[y][x+1] && c == q.modules[y+1][x] && c == q.modules[y+1][x+1] {
+				result += penaltyN2
+			}
+		}
+	}
+
+	// Balance of dark and light modules.
+	dark := 0
+	for _, row := range q.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	total := q.Size * q.Size
+	k := (absInt(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyN4
+	return result
+}
+
+func (q *QRCode) finderPenaltyCountPatterns(history *[7]int) int {
+	n := history[1]
+	core := n > 0 && history[2] == n && history[3] == n*3 && history[4] == n && history[5] == n
+	count := 0
+	if core && history[0] >= n*4 && history[6] >= n {
+		count++
+	}
+	if core && history[6] >= n*4 && history[0] >= n {
+		count++
+	}
+	return count
+}
+
+func (q *QRCode) finderPenaltyTerminateAndCount(runColor bool, runLen int, history *[7]int) int {
+	if runColor { // Terminate the dark run.
+		q.finderPenaltyAddHistory(runLen, history)
+		runLen = 0
+	}
+	runLen += q.Size // Add the light border to the final run.
+	q.finderPenaltyAddHistory(runLen, history)
+	return q.finderPenaltyCountPatterns(history)
+}
+
+func (q *QRCode) finderPenaltyAddHistory(runLen int, history *[7]int) {
+	if history[0] == 0 {
+		runLen += q.Size // Add the light border to the initial run.
+	}
+	copy(history[1:], history[:6])
+	history[0] = runLen
+}
+
+//
+// Reed-Solomon error correction over GF(2^8/0x11D).
+//
+
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1 // Start with the monomial x^0.
+
+	// Multiply by (x - r^i) for i in 0..degree-1 where r = 0x02.
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = reedSolomonMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = reedSolomonMultiply(root, 0x02)
+	}
+	return result
+}
+
+func reedSolomonRemainder(data []byte, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= reedSolomonMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+func reedSolomonMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+//
+// Helpers.
+//
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) appendBits(val int, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*bb = append(*bb, (val>>uint(i))&1 != 0)
+	}
+}
+
+func getBit(x int, i int) bool {
+	return (x>>uint(i))&1 != 0
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,76 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// The "HELLO WORLD" 1-M example from the specification.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	actual := reedSolomonRemainder(data, reedSolomonDivisor(len(expected)))
+	if !bytes.Equal(actual, expected) {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	q, err := Encode("https://cpsapp.ca/cpsrn/788346-26649-1-1001-2", Medium)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if q.Version != 4 || q.Size != 33 {
+		t.Errorf("expected version 4 with size 33 but got version %v with size %v", q.Version, q.Size)
+	}
+
+	// Every corner except the bottom right has a finder pattern.
+	for _, corner := range [][2]int{{0, 0}, {q.Size - 7, 0}, {0, q.Size - 7}} {
+		for y := 0; y < 7; y++ {
+			for x := 0; x < 7; x++ {
+				ring := maxInt(absInt(x-3), absInt(y-3))
+				if expected := ring != 2; q.Black(corner[0]+x, corner[1]+y) != expected {
+					t.Fatalf("finder pattern at %v is wrong at %v,%v", corner, x, y)
+				}
+			}
+		}
+	}
+	if q.Black(-1, 0) || q.Black(0, q.Size) {
+		t.Error("expected modules outside the code to be light")
+	}
+}
+
+func TestEncodeVersionInformation(t *testing.T) {
+	// 124 bytes is the most which fits into version 7 at the medium level.
+	q, err := Encode(strings.Repeat("a", 120), Medium)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	if q.Version != 7 {
+		t.Fatalf("expected version 7 but got %v", q.Version)
+	}
+
+	// The version information block for version 7 is 0x07C94.
+	var actual int
+	for i := 17; i >= 0; i-- {
+		actual <<= 1
+		if q.Black(q.Size-11+i%3, i/3) {
+			actual |= 1
+		}
+	}
+	if actual != 0x07C94 {
+		t.Errorf("expected 0x07C94 but got %#05x", actual)
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("a", 3000), High); !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected too long error but got %v", err)
+	}
+	if _, err := Encode("hello", Level(4)); err == nil {
+		t.Error("expected unsupported level error")
+	}
+}
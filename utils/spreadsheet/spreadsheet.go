@@ -0,0 +1,292 @@
+// Package spreadsheet reads the rows of CSV and XLSX files into plain strings
+// using nothing but the standard library.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for files which are neither CSV nor XLSX.
+	ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+	// ErrTooManyRows is returned as soon as a file has more rows than allowed.
+	ErrTooManyRows = errors.New("spreadsheet has too many rows")
+	// ErrTooLarge is returned for XLSX files which decompress to more than we
+	// are willing to read, ex: a zip bomb.
+	ErrTooLarge = errors.New("spreadsheet is too large")
+)
+
+const (
+	// maxColumns is the number of columns of Excel, ie: up to column `XFD`.
+	maxColumns = 16384
+	// maxPartSize is the most we read of any file inside of an XLSX archive.
+	maxPartSize = 32 << 20
+)
+
+// Read returns the rows of the CSV or XLSX file, up to maxRows rows. The
+// format is detected from the content as an XLSX file is a zip archive.
+func Read(b []byte, maxRows int) ([][]string, error) {
+	if bytes.HasPrefix(b, []byte("PK\x03\x04")) {
+		return ReadXLSX(b, maxRows)
+	}
+	if !isText(b) {
+		return nil, ErrUnsupportedFormat
+	}
+	return ReadCSV(bytes.NewReader(b), maxRows)
+}
+
+// ReadCSV returns the rows of the CSV file; rows may have a different number
+// of cells and a leading byte order mark is ignored. Reading stops with
+// ErrTooManyRows if the file has more than maxRows rows.
+func ReadCSV(r io.Reader, maxRows int) ([][]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	var rows [][]string
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+	return rows, nil
+}
+
+// ReadXLSX returns the rows of the first worksheet of the XLSX workbook.
+// Empty cells in between are returned as empty strings. Reading stops with
+// ErrTooManyRows if the worksheet has more than maxRows rows.
+func ReadXLSX(b []byte, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	var sharedStrings []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if sharedStrings, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("worksheet %v does not exist", sheetPath)
+	}
+	rc, err := openPart(f)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	// The rows are decoded one at a time so we stop reading a worksheet with
+	// too many rows as soon as we know.
+	var rows [][]string
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		var r xlsxRow
+		if err := dec.DecodeElement(&r, &start); err != nil {
+			return nil, err
+		}
+
+		row := []string{}
+		for _, c := range r.Cells {
+			col := len(row)
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(sharedStrings) {
+					return nil, fmt.Errorf("cell %v has an invalid shared string", c.Ref)
+				}
+				row[col] = sharedStrings[i]
+			case "inlineStr":
+				row[col] = c.InlineStr.String()
+			case "b":
+				row[col] = strconv.FormatBool(c.Value == "1")
+			default:
+				row[col] = c.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+type xlsxRow struct {
+	Cells []struct {
+		Ref       string     `xml:"r,attr"`
+		Type      string     `xml:"t,attr"`
+		Value     string     `xml:"v"`
+		InlineStr xlsxString `xml:"is"`
+	} `xml:"c"`
+}
+
+// xlsxString is a shared or inline string which is either plain text or a
+// list of formatted runs.
+type xlsxString struct {
+	Text string   `xml:"t"`
+	Runs []string `xml:"r>t"`
+}
+
+func (s xlsxString) String() string {
+	return s.Text + strings.Join(s.Runs, "")
+}
+
+// firstSheetPath returns the path of the first worksheet of the workbook
+// inside of the archive.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrUnsupportedFormat
+	}
+	var workbook struct {
+		Sheets []struct {
+			RelationshipID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeXML(f, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("workbook has no worksheets")
+	}
+
+	f, ok = files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeXML(f, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].RelationshipID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "", fmt.Errorf("worksheet %v does not exist", workbook.Sheets[0].RelationshipID)
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []xlsxString `xml:"si"`
+	}
+	if err := decodeXML(f, &sst); err != nil {
+		return nil, err
+	}
+	s := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		s[i] = item.String()
+	}
+	return s, nil
+}
+
+// openPart opens the file inside of the archive, reading no more than
+// maxPartSize bytes of it whatever size the archive claims it has.
+func openPart(f *zip.File) (io.ReadCloser, error) {
+	if f.UncompressedSize64 > maxPartSize {
+		return nil, ErrTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, maxPartSize), rc}, nil
+}
+
+func decodeXML(f *zip.File, v interface{}) error {
+	rc, err := openPart(f)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// columnIndex returns the zero based column of the cell reference, ex: `C7`
+// returns 2.
+func columnIndex(ref string) (int, error) {
+	col := 0
+	for i, r := range ref {
+		if r >= 'A' && r <= 'Z' {
+			col = col*26 + int(r-'A') + 1
+			if col > maxColumns {
+				break
+			}
+			continue
+		}
+		if i == 0 {
+			break
+		}
+		return col - 1, nil
+	}
+	return 0, fmt.Errorf("invalid cell reference: %v", ref)
+}
+
+// isText returns true if the content does not look like a binary file.
+func isText(b []byte) bool {
+	if len(b) > 512 {
+		b = b[:512]
+	}
+	return !bytes.ContainsRune(b, 0)
+}
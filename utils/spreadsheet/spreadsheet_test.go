@@ -0,0 +1,110 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	rows, err := Read([]byte("\ufeffseries_title,issue_no\n\"Spawn, The\",1\nX-Men\n"), 3)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	expected := [][]string{{"series_title", "issue_no"}, {"Spawn, The", "1"}, {"X-Men"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v but got %v", expected, rows)
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Intake" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/intake.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>series_title</t></si><si><t>issue_no</t></si><si><r><t>Amazing </t></r><r><t>Spider-Man</t></r></si></sst>`,
+		"xl/worksheets/intake.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>
+<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2" t="b"><v>1</v></c><c r="C2"><v>300</v></c></row>
+<row r="3"><c r="B3" t="inlineStr"><is><t>inline</t></is></c></row>
+</sheetData></worksheet>`,
+	}
+	rows, err := Read(newXLSX(t, files), 3)
+	if err != nil {
+		t.Fatalf("received an error %v", err)
+	}
+	expected := [][]string{{"series_title", "", "issue_no"}, {"Amazing Spider-Man", "true", "300"}, {"", "inline"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %q but got %q", expected, rows)
+	}
+}
+
+func TestReadUnsupportedFormat(t *testing.T) {
+	if _, err := Read([]byte{0x25, 0x50, 0x44, 0x46, 0x00, 0x01}, 3); err != ErrUnsupportedFormat {
+		t.Errorf("expected %v but got %v", ErrUnsupportedFormat, err)
+	}
+}
+
+func TestReadTooManyRows(t *testing.T) {
+	if _, err := Read([]byte("series_title\nSpawn\nX-Men\n"), 2); err != ErrTooManyRows {
+		t.Errorf("expected %v but got %v", ErrTooManyRows, err)
+	}
+
+	sheet := `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>series_title</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>Spawn</t></is></c></row>
+<row r="3"><c r="A3" t="inlineStr"><is><t>X-Men</t></is></c></row>
+</sheetData></worksheet>`
+	if _, err := Read(newXLSX(t, worksheet(sheet)), 2); err != ErrTooManyRows {
+		t.Errorf("expected %v but got %v", ErrTooManyRows, err)
+	}
+}
+
+func TestReadXLSXLimits(t *testing.T) {
+	// Excel stops at column XFD so anything past it only wastes our memory.
+	sheet := `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="XFE1"><v>1</v></c></row>
+</sheetData></worksheet>`
+	if _, err := Read(newXLSX(t, worksheet(sheet)), 2); err == nil {
+		t.Error("expected a column past XFD to be refused")
+	}
+	if col, err := columnIndex("XFD1"); err != nil || col != maxColumns-1 {
+		t.Errorf("expected %v but got %v and %v", maxColumns-1, col, err)
+	}
+
+	// A worksheet which decompresses to more than we read is refused.
+	sheet = `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		strings.Repeat(" ", maxPartSize) + `</sheetData></worksheet>`
+	if _, err := Read(newXLSX(t, worksheet(sheet)), 2); err != ErrTooLarge {
+		t.Errorf("expected %v but got %v", ErrTooLarge, err)
+	}
+}
+
+// worksheet returns the files of a workbook made of the worksheet.
+func worksheet(sheet string) map[string]string {
+	return map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Intake" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/worksheets/sheet1.xml": sheet,
+	}
+}
+
+func newXLSX(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
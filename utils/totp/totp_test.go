@@ -0,0 +1,63 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 secret of the test vectors of RFC 6238.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// The RFC lists eight digits; we use the last six.
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, test := range tests {
+		actual, err := Code(rfcSecret, Step(time.Unix(test.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != test.expected {
+			t.Errorf("expected %v at %v but got %v", test.expected, test.unix, actual)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, _ := Code(rfcSecret, Step(now))
+
+	step, ok := Validate(rfcSecret, code, now, 0)
+	if !ok || step != Step(now) {
+		t.Fatalf("expected %v to be valid", code)
+	}
+	if _, ok := Validate(rfcSecret, code, now.Add(Period), 0); !ok {
+		t.Errorf("expected %v to be valid one step later", code)
+	}
+	if _, ok := Validate(rfcSecret, code, now.Add(3*Period), 0); ok {
+		t.Errorf("expected %v to have expired", code)
+	}
+	if _, ok := Validate(rfcSecret, code, now, step); ok {
+		t.Errorf("expected %v to not be valid twice", code)
+	}
+	if _, ok := Validate(rfcSecret, "12345", now, 0); ok {
+		t.Error("expected a short code to be invalid")
+	}
+}
+
+func TestURI(t *testing.T) {
+	expected := "otpauth://totp/CPS:bart@example.com?algorithm=SHA1&digits=6&issuer=CPS&period=30&secret=ABC"
+	if actual := URI("CPS", "bart@example.com", "ABC"); actual != expected {
+		t.Errorf("expected %v but got %v", expected, actual)
+	}
+}
@@ -11,30 +11,49 @@ import (
 	"github.com/LuchaComics/cps-backend/adapter/pdfbuilder"
 	"github.com/LuchaComics/cps-backend/adapter/storage/mongodb"
 	s3_storage "github.com/LuchaComics/cps-backend/adapter/storage/s3"
+	apikey_c "github.com/LuchaComics/cps-backend/app/apikey/controller"
+	apikey_s "github.com/LuchaComics/cps-backend/app/apikey/datastore"
 	attachment_c "github.com/LuchaComics/cps-backend/app/attachment/controller"
 	attachment_s "github.com/LuchaComics/cps-backend/app/attachment/datastore"
+	audit_c "github.com/LuchaComics/cps-backend/app/audit/controller"
+	audit_s "github.com/LuchaComics/cps-backend/app/audit/datastore"
 	comicsub_c "github.com/LuchaComics/cps-backend/app/comicsub/controller"
 	comicsub_s "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
 	customer_c "github.com/LuchaComics/cps-backend/app/customer/controller"
 	gateway_c "github.com/LuchaComics/cps-backend/app/gateway/controller"
+	job_s "github.com/LuchaComics/cps-backend/app/job/datastore"
+	order_c "github.com/LuchaComics/cps-backend/app/order/controller"
+	order_s "github.com/LuchaComics/cps-backend/app/order/datastore"
 	organization_c "github.com/LuchaComics/cps-backend/app/organization/controller"
 	organization_s "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	report_c "github.com/LuchaComics/cps-backend/app/report/controller"
+	report_s "github.com/LuchaComics/cps-backend/app/report/datastore"
+	session_c "github.com/LuchaComics/cps-backend/app/session/controller"
+	session_s "github.com/LuchaComics/cps-backend/app/session/datastore"
 	user_c "github.com/LuchaComics/cps-backend/app/user/controller"
 	user_s "github.com/LuchaComics/cps-backend/app/user/datastore"
+	webhook_c "github.com/LuchaComics/cps-backend/app/webhook/controller"
+	webhook_s "github.com/LuchaComics/cps-backend/app/webhook/datastore"
 	"github.com/LuchaComics/cps-backend/config"
 	"github.com/LuchaComics/cps-backend/inputport/http"
+	apikey_http "github.com/LuchaComics/cps-backend/inputport/http/apikey"
 	attachment_http "github.com/LuchaComics/cps-backend/inputport/http/attachment"
+	audit_http "github.com/LuchaComics/cps-backend/inputport/http/audit"
 	comicsub_http "github.com/LuchaComics/cps-backend/inputport/http/comicsub"
 	customer_http "github.com/LuchaComics/cps-backend/inputport/http/customer"
 	gateway_http "github.com/LuchaComics/cps-backend/inputport/http/gateway"
 	"github.com/LuchaComics/cps-backend/inputport/http/middleware"
+	order_http "github.com/LuchaComics/cps-backend/inputport/http/order"
 	organization_http "github.com/LuchaComics/cps-backend/inputport/http/organization"
+	report_http "github.com/LuchaComics/cps-backend/inputport/http/report"
 	user_http "github.com/LuchaComics/cps-backend/inputport/http/user"
+	webhook_http "github.com/LuchaComics/cps-backend/inputport/http/webhook"
+	"github.com/LuchaComics/cps-backend/inputport/worker"
 	"github.com/LuchaComics/cps-backend/provider/cpsrn"
 	"github.com/LuchaComics/cps-backend/provider/jwt"
-	"github.com/LuchaComics/cps-backend/provider/kmutex"
 	"github.com/LuchaComics/cps-backend/provider/logger"
 	"github.com/LuchaComics/cps-backend/provider/password"
+	"github.com/LuchaComics/cps-backend/provider/signing"
 	"github.com/LuchaComics/cps-backend/provider/time"
 	"github.com/LuchaComics/cps-backend/provider/uuid"
 )
@@ -49,37 +68,54 @@ func InitializeEvent() Application {
 		time.NewProvider,
 		logger.NewProvider,
 		jwt.NewProvider,
-		kmutex.NewProvider,
 		mailgun.NewEmailer,
 		password.NewProvider,
 		cpsrn.NewProvider,
+		signing.NewProvider,
 		mongodb.NewStorage,
 		s3_storage.NewStorage,
 		redis.NewCache,
-		pdfbuilder.NewCBFFBuilder,
-		pdfbuilder.NewPCBuilder,
-		pdfbuilder.NewCCIMGBuilder,
-		pdfbuilder.NewCCSCBuilder,
-		pdfbuilder.NewCCBuilder,
-		pdfbuilder.NewCCUGBuilder,
+		pdfbuilder.NewCertificateRendererRegistry,
+		pdfbuilder.NewPackingSlipRenderer,
 		user_s.NewDatastore,
 		user_c.NewController,
 		customer_c.NewController,
 		organization_s.NewDatastore,
 		organization_c.NewController,
 		comicsub_s.NewDatastore,
+		comicsub_s.NewCPSRNAllocator,
+		job_s.NewDatastore,
 		comicsub_c.NewController,
+		order_s.NewDatastore,
+		order_c.NewController,
+		report_s.NewDatastore,
+		report_c.NewController,
 		gateway_c.NewController,
 		attachment_s.NewDatastore,
 		attachment_c.NewController,
+		apikey_s.NewDatastore,
+		apikey_c.NewController,
+		webhook_s.NewSubscriptionDatastore,
+		webhook_s.NewDeliveryDatastore,
+		webhook_c.NewController,
+		session_s.NewDatastore,
+		session_c.NewController,
+		audit_s.NewDatastore,
+		audit_c.NewController,
 		gateway_http.NewHandler,
 		user_http.NewHandler,
 		customer_http.NewHandler,
 		organization_http.NewHandler,
 		comicsub_http.NewHandler,
 		attachment_http.NewHandler,
+		order_http.NewHandler,
+		report_http.NewHandler,
+		apikey_http.NewHandler,
+		webhook_http.NewHandler,
+		audit_http.NewHandler,
 		middleware.NewMiddleware,
 		http.NewInputPort,
+		worker.NewInputPort,
 		NewApplication)
 	return Application{}
 }
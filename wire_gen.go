@@ -12,30 +12,49 @@ import (
 	"github.com/LuchaComics/cps-backend/adapter/pdfbuilder"
 	"github.com/LuchaComics/cps-backend/adapter/storage/mongodb"
 	"github.com/LuchaComics/cps-backend/adapter/storage/s3"
+	controller9 "github.com/LuchaComics/cps-backend/app/apikey/controller"
+	datastore8 "github.com/LuchaComics/cps-backend/app/apikey/datastore"
 	controller6 "github.com/LuchaComics/cps-backend/app/attachment/controller"
 	datastore4 "github.com/LuchaComics/cps-backend/app/attachment/datastore"
+	controller12 "github.com/LuchaComics/cps-backend/app/audit/controller"
+	datastore11 "github.com/LuchaComics/cps-backend/app/audit/datastore"
 	controller4 "github.com/LuchaComics/cps-backend/app/comicsub/controller"
 	datastore3 "github.com/LuchaComics/cps-backend/app/comicsub/datastore"
 	controller5 "github.com/LuchaComics/cps-backend/app/customer/controller"
 	"github.com/LuchaComics/cps-backend/app/gateway/controller"
+	datastore5 "github.com/LuchaComics/cps-backend/app/job/datastore"
+	controller7 "github.com/LuchaComics/cps-backend/app/order/controller"
+	datastore6 "github.com/LuchaComics/cps-backend/app/order/datastore"
 	controller3 "github.com/LuchaComics/cps-backend/app/organization/controller"
 	datastore2 "github.com/LuchaComics/cps-backend/app/organization/datastore"
+	controller8 "github.com/LuchaComics/cps-backend/app/report/controller"
+	datastore7 "github.com/LuchaComics/cps-backend/app/report/datastore"
+	controller11 "github.com/LuchaComics/cps-backend/app/session/controller"
+	datastore10 "github.com/LuchaComics/cps-backend/app/session/datastore"
 	controller2 "github.com/LuchaComics/cps-backend/app/user/controller"
 	"github.com/LuchaComics/cps-backend/app/user/datastore"
+	controller10 "github.com/LuchaComics/cps-backend/app/webhook/controller"
+	datastore9 "github.com/LuchaComics/cps-backend/app/webhook/datastore"
 	"github.com/LuchaComics/cps-backend/config"
 	"github.com/LuchaComics/cps-backend/inputport/http"
+	"github.com/LuchaComics/cps-backend/inputport/http/apikey"
 	"github.com/LuchaComics/cps-backend/inputport/http/attachment"
+	"github.com/LuchaComics/cps-backend/inputport/http/audit"
 	"github.com/LuchaComics/cps-backend/inputport/http/comicsub"
 	"github.com/LuchaComics/cps-backend/inputport/http/customer"
 	"github.com/LuchaComics/cps-backend/inputport/http/gateway"
 	"github.com/LuchaComics/cps-backend/inputport/http/middleware"
+	"github.com/LuchaComics/cps-backend/inputport/http/order"
 	"github.com/LuchaComics/cps-backend/inputport/http/organization"
+	"github.com/LuchaComics/cps-backend/inputport/http/report"
 	"github.com/LuchaComics/cps-backend/inputport/http/user"
+	"github.com/LuchaComics/cps-backend/inputport/http/webhook"
+	"github.com/LuchaComics/cps-backend/inputport/worker"
 	"github.com/LuchaComics/cps-backend/provider/cpsrn"
 	"github.com/LuchaComics/cps-backend/provider/jwt"
-	"github.com/LuchaComics/cps-backend/provider/kmutex"
 	"github.com/LuchaComics/cps-backend/provider/logger"
 	"github.com/LuchaComics/cps-backend/provider/password"
+	"github.com/LuchaComics/cps-backend/provider/signing"
 	"github.com/LuchaComics/cps-backend/provider/time"
 	"github.com/LuchaComics/cps-backend/provider/uuid"
 )
@@ -54,31 +73,48 @@ func InitializeEvent() Application {
 	client := mongodb.NewStorage(conf, slogLogger)
 	userStorer := datastore.NewDatastore(conf, slogLogger, client)
 	organizationStorer := datastore2.NewDatastore(conf, slogLogger, client)
-	gatewayController := controller.NewController(conf, slogLogger, provider, jwtProvider, passwordProvider, cacher, emailer, userStorer, organizationStorer)
-	middlewareMiddleware := middleware.NewMiddleware(conf, slogLogger, provider, timeProvider, jwtProvider, gatewayController)
+	sessionStorer := datastore10.NewDatastore(conf, slogLogger, client)
+	sessionController := controller11.NewController(conf, slogLogger, provider, cacher, sessionStorer, userStorer)
+	eventStorer := datastore11.NewDatastore(conf, slogLogger, client)
+	gatewayController := controller.NewController(conf, slogLogger, provider, jwtProvider, passwordProvider, cacher, emailer, userStorer, organizationStorer, sessionController, eventStorer)
+	apiKeyStorer := datastore8.NewDatastore(conf, slogLogger, client)
+	apiKeyController := controller9.NewController(conf, slogLogger, passwordProvider, apiKeyStorer, organizationStorer, userStorer)
+	middlewareMiddleware := middleware.NewMiddleware(conf, slogLogger, provider, timeProvider, jwtProvider, gatewayController, apiKeyController, sessionController)
 	handler := gateway.NewHandler(gatewayController)
-	userController := controller2.NewController(conf, slogLogger, provider, passwordProvider, organizationStorer, userStorer)
+	userController := controller2.NewController(conf, slogLogger, provider, passwordProvider, organizationStorer, userStorer, sessionController)
 	userHandler := user.NewHandler(userController)
 	s3Storager := s3.NewStorage(conf, slogLogger, provider)
 	comicSubmissionStorer := datastore3.NewDatastore(conf, slogLogger, client)
 	organizationController := controller3.NewController(conf, slogLogger, provider, s3Storager, emailer, organizationStorer, userStorer, comicSubmissionStorer)
 	organizationHandler := organization.NewHandler(organizationController)
-	kmutexProvider := kmutex.NewProvider()
 	cpsrnProvider := cpsrn.NewProvider()
-	cbffBuilder := pdfbuilder.NewCBFFBuilder(conf, slogLogger, provider)
-	pcBuilder := pdfbuilder.NewPCBuilder(conf, slogLogger, provider)
-	ccimgBuilder := pdfbuilder.NewCCIMGBuilder(conf, slogLogger, provider)
-	ccscBuilder := pdfbuilder.NewCCSCBuilder(conf, slogLogger, provider)
-	ccBuilder := pdfbuilder.NewCCBuilder(conf, slogLogger, provider)
-	ccugBuilder := pdfbuilder.NewCCUGBuilder(conf, slogLogger, provider)
-	comicSubmissionController := controller4.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, kmutexProvider, cpsrnProvider, cbffBuilder, pcBuilder, ccimgBuilder, ccscBuilder, ccBuilder, ccugBuilder, emailer, userStorer, comicSubmissionStorer, organizationStorer)
+	signingProvider := signing.NewProvider(conf)
+	cpsrnAllocator := datastore3.NewCPSRNAllocator(conf, slogLogger, client, cpsrnProvider)
+	certificateRendererRegistry := pdfbuilder.NewCertificateRendererRegistry(conf, slogLogger)
+	jobStorer := datastore5.NewDatastore(conf, slogLogger, client)
+	comicSubmissionController := controller4.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, cpsrnProvider, signingProvider, cpsrnAllocator, certificateRendererRegistry, emailer, userStorer, comicSubmissionStorer, organizationStorer, jobStorer)
 	comicsubHandler := comicsub.NewHandler(comicSubmissionController)
-	customerController := controller5.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, cbffBuilder, emailer, userStorer)
+	customerController := controller5.NewController(conf, slogLogger, provider, s3Storager, passwordProvider, emailer, userStorer, sessionController)
 	customerHandler := customer.NewHandler(customerController)
 	attachmentStorer := datastore4.NewDatastore(conf, slogLogger, client)
 	attachmentController := controller6.NewController(conf, slogLogger, provider, s3Storager, emailer, attachmentStorer, userStorer, comicSubmissionStorer)
 	attachmentHandler := attachment.NewHandler(attachmentController)
-	inputPortServer := http.NewInputPort(conf, slogLogger, middlewareMiddleware, handler, userHandler, organizationHandler, comicsubHandler, customerHandler, attachmentHandler)
-	application := NewApplication(slogLogger, inputPortServer)
+	packingSlipRenderer := pdfbuilder.NewPackingSlipRenderer(conf, slogLogger)
+	orderStorer := datastore6.NewDatastore(conf, slogLogger, client)
+	orderController := controller7.NewController(conf, slogLogger, s3Storager, packingSlipRenderer, comicSubmissionController, comicSubmissionStorer, orderStorer, jobStorer)
+	orderHandler := order.NewHandler(orderController)
+	reportStorer := datastore7.NewDatastore(conf, slogLogger, client)
+	reportController := controller8.NewController(conf, slogLogger, reportStorer)
+	reportHandler := report.NewHandler(reportController)
+	apikeyHandler := apikey.NewHandler(apiKeyController)
+	subscriptionStorer := datastore9.NewSubscriptionDatastore(conf, slogLogger, client)
+	deliveryStorer := datastore9.NewDeliveryDatastore(conf, slogLogger, client)
+	webhookController := controller10.NewController(conf, slogLogger, comicSubmissionController, subscriptionStorer, deliveryStorer, jobStorer)
+	webhookHandler := webhook.NewHandler(webhookController)
+	auditController := controller12.NewController(conf, slogLogger, eventStorer)
+	auditHandler := audit.NewHandler(auditController)
+	inputPortServer := http.NewInputPort(conf, slogLogger, middlewareMiddleware, handler, userHandler, organizationHandler, comicsubHandler, customerHandler, attachmentHandler, orderHandler, reportHandler, apikeyHandler, webhookHandler, auditHandler)
+	workerInputPortServer := worker.NewInputPort(conf, slogLogger, jobStorer, comicSubmissionController, orderController, webhookController)
+	application := NewApplication(slogLogger, inputPortServer, workerInputPortServer)
 	return application
 }